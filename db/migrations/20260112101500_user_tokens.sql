@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE user_tokens
+(
+    id         UUID PRIMARY KEY,
+    user_id    INT       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    TEXT      NOT NULL,
+    token_hash TEXT      NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_user_tokens_user_purpose ON user_tokens (user_id, purpose);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_tokens;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_verified;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE security_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    event_type TEXT      NOT NULL,
+    user_id    INT       REFERENCES users (id) ON DELETE SET NULL,
+    actor_id   INT       REFERENCES users (id) ON DELETE SET NULL,
+    email      TEXT,
+    ip         TEXT,
+    user_agent TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_security_events_user_created ON security_events (user_id, created_at);
+CREATE INDEX idx_security_events_type_created ON security_events (event_type, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS security_events;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE permissions
+(
+    id          SERIAL PRIMARY KEY,
+    code        VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE role_permissions
+(
+    role_id       INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO permissions (code, description)
+VALUES ('users:read', 'View users'),
+       ('users:manage', 'Edit, block and unlock users'),
+       ('roles:manage', 'Manage roles and their permissions'),
+       ('buses:write', 'Manage buses, bus categories and statuses'),
+       ('drivers:write', 'Manage drivers and driver statuses'),
+       ('stops:write', 'Manage stops'),
+       ('trips:write', 'Manage trips and trip stops'),
+       ('orders:read', 'View all orders'),
+       ('orders:refund', 'Refund orders');
+
+-- администратор получает все права
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         CROSS JOIN permissions p
+WHERE r.name = 'admin';
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.code IN ('users:read', 'trips:write', 'stops:write', 'orders:read')
+WHERE r.name = 'moderator';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Telegram и VK могут не отдавать email
+ALTER TABLE users
+    ALTER COLUMN email DROP NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE users
+SET email = 'user' || id || '@no-email.local'
+WHERE email IS NULL;
+
+ALTER TABLE users
+    ALTER COLUMN email SET NOT NULL;
+-- +goose StatementEnd
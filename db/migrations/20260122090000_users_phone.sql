@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Телефон хранится в формате E.164 (+79991234567)
+ALTER TABLE users
+    ADD COLUMN phone          VARCHAR(16) UNIQUE,
+    ADD COLUMN phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS phone_verified,
+    DROP COLUMN IF EXISTS phone;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN is_service_account BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- В базе хранится только sha256 ключа; prefix — открытая часть для поиска и отображения
+CREATE TABLE api_keys
+(
+    id           UUID PRIMARY KEY,
+    user_id      INT         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT        NOT NULL,
+    prefix       VARCHAR(16) NOT NULL UNIQUE,
+    key_hash     TEXT        NOT NULL,
+    scopes       TEXT        NOT NULL DEFAULT '', -- коды прав через пробел, пусто — все права роли
+    expires_at   TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at   TIMESTAMP,
+    created_by   INT REFERENCES users (id) ON DELETE SET NULL,
+    created_at   TIMESTAMP   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_api_keys_user ON api_keys (user_id);
+
+INSERT INTO permissions (code, description)
+VALUES ('api_keys:manage', 'Manage service accounts and API keys');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.code = 'api_keys:manage'
+WHERE r.name = 'admin';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE code = 'api_keys:manage';
+
+DROP TABLE IF EXISTS api_keys;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS is_service_account;
+-- +goose StatementEnd
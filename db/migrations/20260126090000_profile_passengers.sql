@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN preferred_language VARCHAR(8) NOT NULL DEFAULT 'ru';
+
+-- Сохранённые пассажиры пользователя. Номер документа хранится зашифрованным
+CREATE TABLE passengers
+(
+    id              SERIAL PRIMARY KEY,
+    user_id         INT          NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    first_name      VARCHAR(100) NOT NULL,
+    last_name       VARCHAR(100) NOT NULL,
+    middle_name     VARCHAR(100),
+    birth_date      DATE         NOT NULL,
+    document_type   VARCHAR(30)  NOT NULL,
+    document_number TEXT         NOT NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMP    NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_passengers_user ON passengers (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS passengers;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS preferred_language;
+-- +goose StatementEnd
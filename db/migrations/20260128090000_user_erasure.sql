@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN erasure_requested_at TIMESTAMP,
+    ADD COLUMN anonymized_at        TIMESTAMP;
+
+CREATE INDEX idx_users_erasure_requested ON users (erasure_requested_at)
+    WHERE erasure_requested_at IS NOT NULL AND anonymized_at IS NULL;
+
+CREATE INDEX idx_orders_user_id ON orders (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_orders_user_id;
+DROP INDEX IF EXISTS idx_users_erasure_requested;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS anonymized_at,
+    DROP COLUMN IF EXISTS erasure_requested_at;
+-- +goose StatementEnd
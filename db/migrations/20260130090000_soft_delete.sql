@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE drivers
+    ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE stops
+    ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE bus_categories
+    ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE bus_statuses
+    ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE driver_status
+    ADD COLUMN deleted_at TIMESTAMP;
+
+-- частичные индексы для задачи очистки: удалённых записей обычно немного
+CREATE INDEX idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_bus_deleted_at ON bus (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_drivers_deleted_at ON drivers (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_stops_deleted_at ON stops (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_stops_deleted_at;
+DROP INDEX IF EXISTS idx_drivers_deleted_at;
+DROP INDEX IF EXISTS idx_bus_deleted_at;
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+ALTER TABLE driver_status
+    DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE bus_statuses
+    DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE bus_categories
+    DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE stops
+    DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE drivers
+    DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
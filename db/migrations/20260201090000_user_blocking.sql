@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN blocked_at     TIMESTAMP,
+    ADD COLUMN blocked_reason TEXT;
+
+CREATE INDEX idx_users_role_id ON users (role_id);
+
+INSERT INTO permissions (code, description)
+VALUES ('users:impersonate', 'Sign in as another user for support');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+         JOIN permissions p ON p.code = 'users:impersonate'
+WHERE r.name = 'admin';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE code = 'users:impersonate';
+
+DROP INDEX IF EXISTS idx_users_role_id;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS blocked_reason,
+    DROP COLUMN IF EXISTS blocked_at;
+-- +goose StatementEnd
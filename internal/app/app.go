@@ -4,12 +4,18 @@ import (
 	"context"
 	"corpord-api/internal/config"
 	"corpord-api/internal/database"
+	"corpord-api/internal/encryption"
 	"corpord-api/internal/handler"
+	"corpord-api/internal/httpcache"
+	"corpord-api/internal/idempotency"
 	"corpord-api/internal/logger"
+	"corpord-api/internal/mailer"
+	"corpord-api/internal/ratelimit"
 	"corpord-api/internal/repository"
 	"corpord-api/internal/scheduler"
 	"corpord-api/internal/server"
 	"corpord-api/internal/service"
+	"corpord-api/internal/sms"
 	"corpord-api/internal/sso"
 	"corpord-api/internal/token"
 	"corpord-api/pkg/dbx"
@@ -31,6 +37,11 @@ type App struct {
 	t         token.Manager
 	qb        *dbx.QueryBuilder
 	sso       *sso.Registry
+	mailer    mailer.Mailer
+	sms       sms.Sender
+	limiter   ratelimit.Store
+	cache     httpcache.Store
+	replays   idempotency.Store
 	scheduler *scheduler.Scheduler
 }
 
@@ -69,7 +80,7 @@ func New() *App {
 	a.qb = dbx.NewQueryBuilder(a.db.Postgres.DB())
 
 	a.logger.Info("initializing repository layer")
-	a.r = repository.New(a.logger, a.qb)
+	a.r = repository.New(a.logger, a.qb, a.db.Redis.Client())
 
 	a.logger.Info("initializing token manager")
 	a.t = token.NewManager(&a.cfg.JWT)
@@ -91,11 +102,82 @@ func New() *App {
 	)
 	a.sso.Register("yandex", yandex)
 
+	if a.cfg.SSO.VK.Enabled {
+		vk := sso.NewVKProvider(
+			a.cfg.SSO.VK.ClientID,
+			a.cfg.SSO.VK.ClientSecret,
+			a.cfg.SSO.VK.RedirectURL,
+		)
+		a.sso.Register("vk", vk)
+	}
+
+	if a.cfg.SSO.Telegram.Enabled {
+		a.sso.RegisterTelegram(sso.NewTelegramVerifier(a.cfg.SSO.Telegram.BotToken, a.cfg.SSO.Telegram.MaxAge))
+	}
+
+	for _, pc := range a.cfg.SSO.OIDC {
+		if !pc.Enabled {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		p, err := sso.NewOIDCProvider(ctx, sso.OIDCConfig{
+			Name:         pc.Name,
+			Issuer:       pc.Issuer,
+			ClientID:     pc.ClientID,
+			ClientSecret: pc.ClientSecret,
+			RedirectURL:  pc.RedirectURL,
+			Scopes:       pc.Scopes,
+		})
+		cancel()
+		if err != nil {
+			// недоступный издатель не должен мешать запуску остальных способов входа
+			a.logger.Errorf("failed to initialize oidc provider %s: %v", pc.Name, err)
+			continue
+		}
+		a.sso.Register(pc.Name, p)
+	}
+
+	a.logger.Info("initializing mailer")
+	a.mailer, err = mailer.New(&a.cfg.Mailer, a.logger)
+	if err != nil {
+		a.logger.Fatalf("failed to initialize mailer: %v", err)
+	}
+
+	a.logger.Info("initializing sms sender")
+	a.sms, err = sms.New(&a.cfg.SMS, a.logger)
+	if err != nil {
+		a.logger.Fatalf("failed to initialize sms sender: %v", err)
+	}
+
+	a.logger.Info("initializing encryption")
+	cipher, err := encryption.New(a.cfg.Encryption.Key)
+	if err != nil {
+		a.logger.Fatalf("failed to initialize encryption: %v", err)
+	}
+
 	a.logger.Info("initializing service layer")
-	a.s = service.New(a.logger, a.r, a.t, a.sso)
+	a.s = service.New(a.logger, a.r, a.t, a.sso, a.cfg, a.mailer, a.sms, cipher)
+
+	a.logger.Info("initializing rate limiter")
+	a.limiter, err = ratelimit.New(&a.cfg.RateLimit, a.db.Redis.Client())
+	if err != nil {
+		a.logger.Fatalf("failed to initialize rate limiter: %v", err)
+	}
+
+	a.logger.Info("initializing http cache")
+	a.cache, err = httpcache.New(&a.cfg.HTTPCache, a.db.Redis.Client())
+	if err != nil {
+		a.logger.Fatalf("failed to initialize http cache: %v", err)
+	}
+
+	a.logger.Info("initializing idempotency store")
+	a.replays, err = idempotency.New(&a.cfg.Idempotency, a.db.Redis.Client())
+	if err != nil {
+		a.logger.Fatalf("failed to initialize idempotency store: %v", err)
+	}
 
 	a.logger.Info("initializing handler layer")
-	a.h = handler.New(a.logger, a.s, a.cfg, a.t, a.sso)
+	a.h = handler.New(a.logger, a.s, a.cfg, a.t, a.sso, a.limiter, a.cache, a.replays)
 
 	a.logger.Info("initializing server")
 	a.srv = server.New(a.h.InitRoutes())
@@ -105,6 +187,9 @@ func New() *App {
 	// Добавляем задачу очистки токенов
 	cleanupTask := scheduler.NewCleanupRefreshTokensTask(a.r.PgRepository.RefreshToken, a.logger)
 	a.scheduler.AddTask(cleanupTask)
+	a.scheduler.AddTask(scheduler.NewCleanupUserTokensTask(a.r.PgRepository.UserToken, a.logger))
+	a.scheduler.AddTask(scheduler.NewEraseUsersTask(a.s.Privacy, a.logger))
+	a.scheduler.AddTask(scheduler.NewPurgeDeletedTask(a.r.PgRepository.Purge, a.s.Privacy, a.cfg.Retention.SoftDeleted, a.logger))
 
 	// Запускаем планировщик
 	a.scheduler.Start()
@@ -7,13 +7,57 @@ type APIError struct {
 	Status  int    `json:"-"`
 	Code    string `json:"code"`
 	Message string `json:"message"`
+
+	// Detail уточняет ошибку для клиента, Fields — ошибки отдельных полей,
+	// Failed — индексы элементов пакетного запроса с ошибками
+	Detail string       `json:"-"`
+	Fields []FieldError `json:"-"`
+	Failed []int        `json:"-"`
+}
+
+// FieldError ошибка валидации одного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Param   string `json:"-"` // параметр правила, нужен для перевода сообщения
 }
 
 // Error implements the error interface
 func (e *APIError) Error() string {
+	if e.Detail != "" {
+		return e.Detail
+	}
 	return e.Message
 }
 
+// Is сравнивает ошибки по коду, поэтому уточнённая копия совпадает с исходной
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code == e.Code
+}
+
+// WithDetail возвращает копию ошибки с уточнением для клиента
+func (e *APIError) WithDetail(detail string) *APIError {
+	cp := *e
+	cp.Detail = detail
+	return &cp
+}
+
+// WithFailed возвращает копию ошибки с индексами элементов пакетного запроса, из-за которых он не выполнен
+func (e *APIError) WithFailed(indexes []int) *APIError {
+	cp := *e
+	cp.Failed = indexes
+	return &cp
+}
+
+// WithFields возвращает копию ошибки с ошибками отдельных полей или параметров запроса
+func (e *APIError) WithFields(fields ...FieldError) *APIError {
+	cp := *e
+	cp.Fields = fields
+	return &cp
+}
+
 // NewAPIError creates a new API error
 func NewAPIError(status int, code, message string) *APIError {
 	return &APIError{
@@ -23,23 +67,31 @@ func NewAPIError(status int, code, message string) *APIError {
 	}
 }
 
+// Validation возвращает ошибку валидации с ошибками полей
+func Validation(fields ...FieldError) *APIError {
+	cp := *ErrValidation
+	cp.Fields = fields
+	return &cp
+}
+
 // Predefined API errors
 var (
 	// 4xx errors
-	ErrBadRequest   = NewAPIError(http.StatusBadRequest, "bad_request", "Некорректный запрос")
-	ErrUnauthorized = NewAPIError(http.StatusUnauthorized, "unauthorized", "Не авторизован")
-	ErrForbidden    = NewAPIError(http.StatusForbidden, "forbidden", "Доступ запрещен")
-	ErrNotFound     = NewAPIError(http.StatusNotFound, "not_found", "Ресурс не найден")
+	ErrBadRequest           = NewAPIError(http.StatusBadRequest, "bad_request", "Некорректный запрос")
+	ErrUnauthorized         = NewAPIError(http.StatusUnauthorized, "unauthorized", "Не авторизован")
+	ErrForbidden            = NewAPIError(http.StatusForbidden, "forbidden", "Доступ запрещен")
+	ErrNotFound             = NewAPIError(http.StatusNotFound, "not_found", "Ресурс не найден")
+	ErrMethodNotAllowed     = NewAPIError(http.StatusMethodNotAllowed, "method_not_allowed", "Метод не поддерживается")
+	ErrConflict             = NewAPIError(http.StatusConflict, "conflict", "Конфликт с текущим состоянием ресурса")
+	ErrPreconditionFailed   = NewAPIError(http.StatusPreconditionFailed, "precondition_failed", "Условие запроса не выполнено")
+	ErrUnsupportedMediaType = NewAPIError(http.StatusUnsupportedMediaType, "unsupported_media_type", "Тип содержимого не поддерживается")
+	ErrValidation           = NewAPIError(http.StatusUnprocessableEntity, "validation_failed", "Ошибка валидации")
+	ErrTooManyRequests      = NewAPIError(http.StatusTooManyRequests, "too_many_requests", "Слишком много запросов")
 
 	// 5xx errors
 	ErrInternal = NewAPIError(http.StatusInternalServerError, "internal_error", "Внутренняя ошибка сервера")
 )
 
-// ErrorResponse represents a standard error response
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
 // SuccessResponse represents a standard success response
 type SuccessResponse struct {
 	Message string `json:"message"`
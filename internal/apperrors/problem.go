@@ -0,0 +1,33 @@
+package apperrors
+
+// ContentTypeProblem тип содержимого ответа с ошибкой (RFC 7807)
+const ContentTypeProblem = "application/problem+json"
+
+// Problem тело ответа с ошибкой в формате RFC 7807.
+// Code — стабильный машинный код, по нему клиенты различают ошибки вместо текста
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	Failed    []int        `json:"failed,omitempty"` // индексы элементов пакетного запроса с ошибками
+}
+
+// NewProblem собирает ответ по ошибке API. instance — путь запроса
+func NewProblem(e *APIError, instance, requestID string) *Problem {
+	return &Problem{
+		Type:      "/problems/" + e.Code,
+		Title:     e.Message,
+		Status:    e.Status,
+		Detail:    e.Detail,
+		Instance:  instance,
+		Code:      e.Code,
+		RequestID: requestID,
+		Errors:    e.Fields,
+		Failed:    e.Failed,
+	}
+}
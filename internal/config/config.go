@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,12 +12,21 @@ import (
 )
 
 type Config struct {
-	App      App      `mapstructure:"app"`
-	Database Database `mapstructure:"database"`
-	Logger   Logger   `mapstructure:"logger"`
-	HTTP     HTTP     `mapstructure:"http"`
-	JWT      JWT      `mapstructure:"jwt"`
-	SSO      SSO      `mapstructure:"sso"`
+	App         App         `mapstructure:"app"`
+	Database    Database    `mapstructure:"database"`
+	Logger      Logger      `mapstructure:"logger"`
+	HTTP        HTTP        `mapstructure:"http"`
+	JWT         JWT         `mapstructure:"jwt"`
+	SSO         SSO         `mapstructure:"sso"`
+	Mailer      Mailer      `mapstructure:"mailer"`
+	SMS         SMS         `mapstructure:"sms"`
+	Encryption  Encryption  `mapstructure:"encryption"`
+	Account     Account     `mapstructure:"account"`
+	Security    Security    `mapstructure:"security"`
+	Retention   Retention   `mapstructure:"retention"`
+	RateLimit   RateLimit   `mapstructure:"rate_limit"`
+	HTTPCache   HTTPCache   `mapstructure:"http_cache"`
+	Idempotency Idempotency `mapstructure:"idempotency"`
 }
 
 type App struct {
@@ -85,8 +95,26 @@ type JWT struct {
 }
 
 type SSO struct {
-	Google OAuthProvider `mapstructure:"google"`
-	Yandex OAuthProvider `mapstructure:"yandex"`
+	Google OAuthProvider  `mapstructure:"google"`
+	Yandex OAuthProvider  `mapstructure:"yandex"`
+	VK     OAuthProvider  `mapstructure:"vk"`
+	OIDC   []OIDCProvider `mapstructure:"oidc"` // произвольные OpenID Connect провайдеры (Keycloak и т.п.)
+
+	StateTTL               time.Duration `mapstructure:"state_ttl"`                // Сколько живёт начатый вход
+	FrontendRedirectURL    string        `mapstructure:"frontend_redirect_url"`    // Куда вернуть пользователя после входа по умолчанию
+	AllowedRedirectOrigins []string      `mapstructure:"allowed_redirect_origins"` // Разрешённые origin для return_to
+	// Привязывать SSO к существующему аккаунту по email без входа пользователя.
+	// Работает только если провайдер подтверждает email (email_verified)
+	AutoLinkVerifiedEmail bool `mapstructure:"auto_link_verified_email"`
+
+	Telegram Telegram `mapstructure:"telegram"`
+}
+
+// Telegram Login Widget: не OAuth2, данные виджета подписываются токеном бота
+type Telegram struct {
+	BotToken string        `mapstructure:"bot_token"`
+	MaxAge   time.Duration `mapstructure:"max_age"` // Допустимый возраст auth_date
+	Enabled  bool          `mapstructure:"enabled"`
 }
 
 type OAuthProvider struct {
@@ -96,6 +124,106 @@ type OAuthProvider struct {
 	Enabled      bool   `mapstructure:"enabled"`
 }
 
+type OIDCProvider struct {
+	Name         string   `mapstructure:"name"` // Имя в URL /auth/:provider и в user_identities
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+	Enabled      bool     `mapstructure:"enabled"`
+}
+
+type Mailer struct {
+	Driver    string `mapstructure:"driver"`    // Способ отправки писем (smtp, log)
+	From      string `mapstructure:"from"`      // Адрес отправителя
+	Host      string `mapstructure:"host"`      // SMTP хост
+	Port      int    `mapstructure:"port"`      // SMTP порт
+	Username  string `mapstructure:"username"`  // SMTP логин
+	Password  string `mapstructure:"password"`  // SMTP пароль
+	Directory string `mapstructure:"directory"` // Каталог для сохранения писем (driver=log)
+}
+
+type Account struct {
+	PasswordResetURL     string        `mapstructure:"password_reset_url"`     // Страница фронтенда для ввода нового пароля
+	EmailVerificationURL string        `mapstructure:"email_verification_url"` // Страница фронтенда для подтверждения email
+	PasswordResetTTL     time.Duration `mapstructure:"password_reset_ttl"`
+	EmailVerificationTTL time.Duration `mapstructure:"email_verification_ttl"`
+	ErasureGracePeriod   time.Duration `mapstructure:"erasure_grace_period"` // Через сколько после запроса персональные данные обезличиваются
+}
+
+// Encryption ключ для шифрования персональных данных в базе
+type Encryption struct {
+	Key string `mapstructure:"key"` // 32 байта в base64
+}
+
+// Retention сроки хранения мягко удалённых записей
+type Retention struct {
+	SoftDeleted time.Duration `mapstructure:"soft_deleted"` // Через сколько после удаления запись удаляется окончательно
+}
+
+type SMS struct {
+	Driver string `mapstructure:"driver"` // Способ отправки SMS (log)
+}
+
+type Security struct {
+	Login            LoginProtection `mapstructure:"login"`
+	OTP              OTP             `mapstructure:"otp"`
+	ImpersonationTTL time.Duration   `mapstructure:"impersonation_ttl"` // Срок токена входа от имени пользователя
+}
+
+// OTP параметры входа по одноразовому коду из SMS
+type OTP struct {
+	Length         int           `mapstructure:"length"`          // Количество цифр в коде
+	TTL            time.Duration `mapstructure:"ttl"`             // Сколько действует код
+	ResendInterval time.Duration `mapstructure:"resend_interval"` // Не чаще одного кода на номер за интервал
+	MaxAttempts    int           `mapstructure:"max_attempts"`    // Попыток ввода одного кода
+	DefaultCountry string        `mapstructure:"default_country"` // Код страны для номеров без "+"
+}
+
+// LoginProtection параметры защиты входа от перебора паролей
+type LoginProtection struct {
+	MaxAttempts   int           `mapstructure:"max_attempts"`    // Неудачных попыток на email до блокировки
+	IPMaxAttempts int           `mapstructure:"ip_max_attempts"` // Неудачных попыток с одного IP до блокировки
+	Window        time.Duration `mapstructure:"window"`          // Время жизни счётчика попыток
+	Lockout       time.Duration `mapstructure:"lockout"`         // Первая блокировка, далее удваивается
+	MaxLockout    time.Duration `mapstructure:"max_lockout"`     // Максимальная длительность блокировки
+}
+
+type RateLimit struct {
+	Enabled bool                     `mapstructure:"enabled"`
+	Store   string                   `mapstructure:"store"` // memory | redis
+	Rules   map[string]RateLimitRule `mapstructure:"rules"` // global, auth, search, ...
+}
+
+// RateLimitRule лимит для группы маршрутов
+type RateLimitRule struct {
+	Requests int           `mapstructure:"requests"` // Запросов за период Per
+	Per      time.Duration `mapstructure:"per"`
+	Burst    int           `mapstructure:"burst"` // Размер корзины, по умолчанию равен Requests
+	Key      string        `mapstructure:"key"`   // ip | user | api_key; user и api_key — только для маршрутов после аутентификации
+}
+
+type HTTPCache struct {
+	Enabled bool                     `mapstructure:"enabled"`
+	Store   string                   `mapstructure:"store"` // memory | redis
+	Rules   map[string]HTTPCacheRule `mapstructure:"rules"` // catalog, stops, trips, ...
+}
+
+// HTTPCacheRule политика кеширования группы маршрутов
+type HTTPCacheRule struct {
+	TTL    time.Duration `mapstructure:"ttl"`     // Время жизни ответа в кеше сервера, 0 — не хранить
+	MaxAge time.Duration `mapstructure:"max_age"` // Cache-Control: max-age для клиентов и прокси
+}
+
+// Idempotency повтор POST-запроса с тем же Idempotency-Key получает сохранённый ответ
+type Idempotency struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Store   string        `mapstructure:"store"`    // memory | redis
+	TTL     time.Duration `mapstructure:"ttl"`      // Сколько хранится ответ
+	LockTTL time.Duration `mapstructure:"lock_ttl"` // Сколько ключ занят выполняющимся запросом
+}
+
 func Load(path string) (*Config, error) {
 	v := viper.New()
 	v.SetConfigType("yaml")
@@ -126,9 +254,36 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := cfg.SSO.validate(); err != nil {
+		return nil, fmt.Errorf("invalid sso config: %w", err)
+	}
+
 	return &cfg, nil
 }
 
+// reservedSSONames имена встроенных способов входа: OIDC-провайдер с таким именем
+// подменил бы встроенный в реестре и в user_identities
+var reservedSSONames = []string{"google", "yandex", "vk", "telegram"}
+
+// validate проверяет имена OIDC-провайдеров: они уникальны и не совпадают со встроенными
+func (s *SSO) validate() error {
+	seen := make(map[string]struct{}, len(s.OIDC))
+	for i, p := range s.OIDC {
+		name := strings.ToLower(strings.TrimSpace(p.Name))
+		if name == "" {
+			return fmt.Errorf("oidc[%d]: name is required", i)
+		}
+		if slices.Contains(reservedSSONames, name) {
+			return fmt.Errorf("oidc[%d]: name %q is reserved", i, p.Name)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("oidc[%d]: duplicate name %q", i, p.Name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
 func bindEnvs(v *viper.Viper) {
 	// JWT
 	v.BindEnv("jwt.secret", "JWT_SECRET")
@@ -158,6 +313,31 @@ func bindEnvs(v *viper.Viper) {
 	v.BindEnv("sso.yandex.client_secret", "SSO_YANDEX_CLIENT_SECRET")
 	v.BindEnv("sso.yandex.redirect_url", "SSO_YANDEX_REDIRECT_URL")
 	v.BindEnv("sso.yandex.enabled", "SSO_YANDEX_ENABLED")
+
+	// SSO VK ID
+	v.BindEnv("sso.vk.client_id", "SSO_VK_CLIENT_ID")
+	v.BindEnv("sso.vk.client_secret", "SSO_VK_CLIENT_SECRET")
+	v.BindEnv("sso.vk.redirect_url", "SSO_VK_REDIRECT_URL")
+	v.BindEnv("sso.vk.enabled", "SSO_VK_ENABLED")
+
+	// SSO Telegram
+	v.BindEnv("sso.telegram.bot_token", "SSO_TELEGRAM_BOT_TOKEN")
+	v.BindEnv("sso.telegram.enabled", "SSO_TELEGRAM_ENABLED")
+
+	v.BindEnv("sso.frontend_redirect_url", "SSO_FRONTEND_REDIRECT_URL")
+
+	// Mailer
+	v.BindEnv("mailer.driver", "MAILER_DRIVER")
+	v.BindEnv("mailer.from", "MAILER_FROM")
+	v.BindEnv("mailer.host", "SMTP_HOST")
+	v.BindEnv("mailer.port", "SMTP_PORT")
+	v.BindEnv("mailer.username", "SMTP_USERNAME")
+	v.BindEnv("mailer.password", "SMTP_PASSWORD")
+
+	// SMS
+	v.BindEnv("sms.driver", "SMS_DRIVER")
+
+	v.BindEnv("encryption.key", "ENCRYPTION_KEY")
 }
 
 func setDefaults(v *viper.Viper) {
@@ -187,4 +367,60 @@ func setDefaults(v *viper.Viper) {
 
 	v.SetDefault("sso.google.enabled", false)
 	v.SetDefault("sso.yandex.enabled", false)
+	v.SetDefault("sso.vk.enabled", false)
+	v.SetDefault("sso.telegram.enabled", false)
+	v.SetDefault("sso.telegram.max_age", "24h")
+	v.SetDefault("sso.state_ttl", "10m")
+	v.SetDefault("sso.auto_link_verified_email", false)
+	v.SetDefault("sso.frontend_redirect_url", "http://localhost:3000/auth/callback")
+
+	v.SetDefault("mailer.driver", "log")
+	v.SetDefault("mailer.from", "no-reply@corpord.local")
+	v.SetDefault("mailer.port", 587)
+
+	v.SetDefault("account.password_reset_url", "http://localhost:3000/auth/reset-password")
+	v.SetDefault("account.email_verification_url", "http://localhost:3000/auth/verify-email")
+	v.SetDefault("account.password_reset_ttl", "1h")
+	v.SetDefault("account.email_verification_ttl", "48h")
+	v.SetDefault("account.erasure_grace_period", "720h")
+
+	v.SetDefault("security.login.max_attempts", 5)
+	v.SetDefault("security.login.ip_max_attempts", 20)
+	v.SetDefault("security.login.window", "15m")
+	v.SetDefault("security.login.lockout", "1m")
+	v.SetDefault("security.login.max_lockout", "1h")
+
+	v.SetDefault("security.otp.length", 6)
+	v.SetDefault("security.otp.ttl", "5m")
+	v.SetDefault("security.otp.resend_interval", "1m")
+	v.SetDefault("security.otp.max_attempts", 5)
+	v.SetDefault("security.otp.default_country", "7")
+
+	v.SetDefault("security.impersonation_ttl", "15m")
+
+	v.SetDefault("sms.driver", "log")
+
+	v.SetDefault("retention.soft_deleted", "2160h")
+
+	v.SetDefault("rate_limit.enabled", true)
+	v.SetDefault("rate_limit.store", "memory")
+	v.SetDefault("rate_limit.rules", map[string]any{
+		"global": map[string]any{"requests": 300, "per": "1m", "key": "ip"},
+		"auth":   map[string]any{"requests": 10, "per": "1m", "key": "ip"},
+		"search": map[string]any{"requests": 60, "per": "1m", "burst": 20, "key": "ip"},
+		"api":    map[string]any{"requests": 600, "per": "1m", "key": "api_key"},
+	})
+
+	v.SetDefault("http_cache.enabled", true)
+	v.SetDefault("http_cache.store", "redis")
+	v.SetDefault("http_cache.rules", map[string]any{
+		"catalog": map[string]any{"ttl": "1h", "max_age": "5m"},
+		"stops":   map[string]any{"ttl": "10m", "max_age": "1m"},
+		"trips":   map[string]any{"ttl": "1m", "max_age": "0s"},
+	})
+
+	v.SetDefault("idempotency.enabled", true)
+	v.SetDefault("idempotency.store", "redis")
+	v.SetDefault("idempotency.ttl", "24h")
+	v.SetDefault("idempotency.lock_ttl", "1m")
 }
@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestSSOValidateOIDCNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		oidc    []OIDCProvider
+		wantErr bool
+	}{
+		{"no providers", nil, false},
+		{"distinct names", []OIDCProvider{{Name: "keycloak"}, {Name: "okta"}}, false},
+		{"empty name", []OIDCProvider{{Name: " "}}, true},
+		{"reserved name", []OIDCProvider{{Name: "google"}}, true},
+		{"reserved name in other case", []OIDCProvider{{Name: "VK"}}, true},
+		{"telegram", []OIDCProvider{{Name: "telegram"}}, true},
+		{"duplicate name", []OIDCProvider{{Name: "keycloak"}, {Name: "Keycloak"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&SSO{OIDC: tt.oidc}).validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// version пишется перед шифротекстом, чтобы в будущем можно было сменить алгоритм или ключ
+const version = "v1"
+
+var (
+	ErrEmptyKey          = errors.New("encryption key is not set (encryption.key or ENCRYPTION_KEY)")
+	ErrInvalidKey        = errors.New("encryption key must be 32 bytes encoded in base64")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// Cipher шифрует персональные данные для хранения в базе (AES-256-GCM)
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New создает Cipher по ключу в base64 (32 байта после декодирования).
+// Ключа по умолчанию нет: без него сервис не стартует
+func New(key string) (*Cipher, error) {
+	if strings.TrimSpace(key) == "" {
+		return nil, ErrEmptyKey
+	}
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt возвращает строку вида v1:<base64(nonce|ciphertext)>
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return version + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt расшифровывает значение, полученное из Encrypt
+func (c *Cipher) Decrypt(value string) (string, error) {
+	ver, payload, ok := strings.Cut(value, ":")
+	if !ok || ver != version {
+		return "", ErrInvalidCiphertext
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plain), nil
+}
+
+// Mask оставляет видимыми только последние visible символов
+func Mask(value string, visible int) string {
+	r := []rune(value)
+	if len(r) <= visible {
+		return strings.Repeat("*", len(r))
+	}
+	return strings.Repeat("*", len(r)-visible) + string(r[len(r)-visible:])
+}
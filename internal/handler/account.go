@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/service"
+	"corpord-api/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler обрабатывает восстановление пароля и подтверждение email
+type AccountHandler struct {
+	logger *logger.Logger
+	s      service.Account
+}
+
+func NewAccountHandler(logger *logger.Logger, s service.Account) *AccountHandler {
+	return &AccountHandler{
+		logger: logger,
+		s:      s,
+	}
+}
+
+// ForgotPassword отправляет письмо для сброса пароля
+// @Summary Запросить сброс пароля
+// @Description Отправляет на email ссылку для установки нового пароля. Ответ не зависит от наличия аккаунта
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.PasswordForgotRequest true "Email пользователя"
+// @Success 202 {object} apperrors.SuccessResponse "Запрос принят"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /auth/password/forgot [post]
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req model.PasswordForgotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	if err := h.s.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		h.logger.Errorf("password reset request failed: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.Success(c, http.StatusAccepted, "Если аккаунт с таким email существует, на него отправлено письмо")
+}
+
+// ResetPassword устанавливает новый пароль
+// @Summary Сбросить пароль
+// @Description Устанавливает новый пароль по одноразовому токену из письма и завершает все сессии пользователя
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.PasswordResetRequest true "Токен и новый пароль"
+// @Success 200 {object} apperrors.SuccessResponse "Пароль изменён"
+// @Failure 400 {object} apperrors.Problem "Некорректный или просроченный токен"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /auth/password/reset [post]
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req model.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	if err := h.s.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		h.logger.Warnf("password reset failed: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.SetCookie("refresh_token", "", -1, "/", "", true, true)
+	helper.Success(c, http.StatusOK, "Пароль успешно изменён")
+}
+
+// RequestEmailVerification повторно отправляет письмо для подтверждения email
+// @Summary Запросить письмо подтверждения email
+// @Description Отправляет ссылку для подтверждения email. Ответ не зависит от наличия аккаунта
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.EmailVerificationRequest true "Email пользователя"
+// @Success 202 {object} apperrors.SuccessResponse "Запрос принят"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /auth/email/verification [post]
+func (h *AccountHandler) RequestEmailVerification(c *gin.Context) {
+	var req model.EmailVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	if err := h.s.RequestEmailVerification(c.Request.Context(), req.Email); err != nil {
+		h.logger.Errorf("email verification request failed: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.Success(c, http.StatusAccepted, "Если адрес требует подтверждения, на него отправлено письмо")
+}
+
+// VerifyEmail подтверждает email
+// @Summary Подтвердить email
+// @Description Подтверждает email по одноразовому токену из письма
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.EmailVerifyRequest true "Токен из письма"
+// @Success 200 {object} apperrors.SuccessResponse "Email подтверждён"
+// @Failure 400 {object} apperrors.Problem "Некорректный или просроченный токен"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /auth/email/verify [post]
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req model.EmailVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	if err := h.s.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		h.logger.Warnf("email verification failed: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.Success(c, http.StatusOK, "Email подтверждён")
+}
+
+func (h *AccountHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	auth := rg.Group("/auth")
+	auth.POST("/password/forgot", h.ForgotPassword)
+	auth.POST("/password/reset", h.ResetPassword)
+	auth.POST("/email/verification", h.RequestEmailVerification)
+	auth.POST("/email/verify", h.VerifyEmail)
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/service"
+	"corpord-api/model"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyHandler сервисные аккаунты и API-ключи для интеграций
+type APIKeyHandler struct {
+	logger *logger.Logger
+	s      service.APIKey
+}
+
+func NewAPIKey(logger *logger.Logger, s service.APIKey) *APIKeyHandler {
+	return &APIKeyHandler{
+		logger: logger,
+		s:      s,
+	}
+}
+
+// CreateServiceAccount создает сервисный аккаунт
+// @Summary Создать сервисный аккаунт
+// @Description Создает пользователя без пароля для интеграций. Доступ выполняется по API-ключам с правами указанной роли. Права роли не могут быть шире прав администратора
+// @Tags admin/api-keys
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param input body model.ServiceAccountCreate true "Данные сервисного аккаунта"
+// @Success 201 {object} model.UserResponse "Сервисный аккаунт создан"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен или у роли есть права, которых нет у вас"
+// @Failure 404 {object} apperrors.Problem "Роль не найдена"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/service-accounts [post]
+func (h *APIKeyHandler) CreateServiceAccount(c *gin.Context) {
+	var input model.ServiceAccountCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	caller, ok := helper.CurrentClaims(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized)
+		return
+	}
+
+	out, err := h.s.CreateServiceAccount(c.Request.Context(), &input, caller)
+	if err != nil {
+		h.logger.Errorf("failed to create service account: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+// List возвращает ключи пользователя
+// @Summary Ключи пользователя
+// @Description Возвращает API-ключи пользователя или сервисного аккаунта без секретов
+// @Tags admin/api-keys
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пользователя"
+// @Success 200 {array} model.APIKeyResponse "Список ключей"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
+		return
+	}
+
+	keys, err := h.s.List(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Errorf("failed to list api keys of user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// Create выпускает ключ
+// @Summary Создать API-ключ
+// @Description Выпускает ключ для себя или сервисного аккаунта с правами не шире ваших. Ключ возвращается только в этом ответе, передавать его нужно в заголовке X-API-Key
+// @Tags admin/api-keys
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пользователя"
+// @Param input body model.APIKeyCreate true "Параметры ключа"
+// @Success 201 {object} model.APIKeyCreated "Ключ создан"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные или неизвестное право"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен: чужой пользователь или права шире ваших"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
+		return
+	}
+
+	var input model.APIKeyCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	caller, ok := helper.CurrentClaims(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized)
+		return
+	}
+
+	out, err := h.s.Create(c.Request.Context(), userID, &input, caller)
+	if err != nil {
+		h.logger.Errorf("failed to create api key for user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	h.logger.Infof("api key %s created for user %d by %d", out.Prefix, userID, caller.UserID)
+	c.JSON(http.StatusCreated, out)
+}
+
+// Rotate перевыпускает ключ
+// @Summary Ротация API-ключа
+// @Description Выпускает новый ключ с теми же параметрами и отзывает старый
+// @Tags admin/api-keys
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID ключа"
+// @Success 201 {object} model.APIKeyCreated "Новый ключ"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен: чужой пользователь или права шире ваших"
+// @Failure 404 {object} apperrors.Problem "Ключ не найден или отозван"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) Rotate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID ключа"))
+		return
+	}
+
+	caller, ok := helper.CurrentClaims(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized)
+		return
+	}
+
+	out, err := h.s.Rotate(c.Request.Context(), id, caller)
+	if err != nil {
+		h.logger.Errorf("failed to rotate api key %s: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+// Revoke отзывает ключ
+// @Summary Отозвать API-ключ
+// @Description Ключ перестаёт действовать немедленно
+// @Tags admin/api-keys
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID ключа"
+// @Success 200 {object} apperrors.SuccessResponse "Ключ отозван"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Ключ не найден или уже отозван"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID ключа"))
+		return
+	}
+
+	adminID, _ := helper.CurrentUserID(c)
+
+	if err := h.s.Revoke(c.Request.Context(), id, adminID); err != nil {
+		h.logger.Errorf("failed to revoke api key %s: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.Success(c, http.StatusOK, "Ключ отозван")
+}
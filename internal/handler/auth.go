@@ -5,10 +5,13 @@ import (
 	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
+	"corpord-api/internal/sso"
 	"corpord-api/internal/token"
 	"corpord-api/model"
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,13 +19,15 @@ import (
 
 type AuthHandler struct {
 	service service.Auth
+	account service.Account
 	logger  *logger.Logger
 	t       token.Manager
 }
 
-func NewAuthHandler(s service.Auth, l *logger.Logger, t token.Manager) *AuthHandler {
+func NewAuthHandler(s service.Auth, account service.Account, l *logger.Logger, t token.Manager) *AuthHandler {
 	return &AuthHandler{
 		service: s,
+		account: account,
 		logger:  l,
 		t:       t,
 	}
@@ -36,9 +41,9 @@ func NewAuthHandler(s service.Auth, l *logger.Logger, t token.Manager) *AuthHand
 // @Produce json
 // @Param input body model.UserCreate true "Данные пользователя"
 // @Success 201 {object} model.UserResponse "Успешная регистрация"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 409 {object} apperrors.ErrorResponse "Пользователь уже существует"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 409 {object} apperrors.Problem "Пользователь уже существует"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	start := time.Now()
@@ -50,29 +55,24 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	var req model.UserCreate
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warnf("invalid request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: apperrors.ErrBadRequest.Message,
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	tokens, err := h.service.Register(c.Request.Context(), &req, userAgent, ip)
 	if err != nil {
 		h.logger.Warnf("registration failed: %v", err)
-		if errors.Is(err, service.ErrEmailExists) {
-			c.JSON(http.StatusConflict, apperrors.ErrorResponse{
-				Error: "Пользователь с таким email уже существует",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: apperrors.ErrInternal.Message,
-		})
+		helper.Abort(c, err)
 		return
 	}
 
 	helper.SetRefreshCookie(c, tokens.RefreshToken, h.t.RefreshTTL()) // TTL берём как у сервиса
 
+	// письмо с подтверждением не должно ломать регистрацию
+	if err := h.account.RequestEmailVerification(c.Request.Context(), req.Email); err != nil {
+		h.logger.Warnf("failed to send verification email to %s: %v", req.Email, err)
+	}
+
 	h.logger.Infof("user registered successfully in %v", time.Since(start))
 	c.JSON(http.StatusCreated, model.TokenResponse{AccessToken: tokens.AccessToken})
 }
@@ -85,9 +85,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Produce json
 // @Param input body model.UserLogin true "Данные для входа"
 // @Success 200 {object} model.TokenResponse "Успешный вход"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Неверные учетные данные"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Неверные учетные данные"
+// @Failure 403 {object} apperrors.Problem "Аккаунт заблокирован"
+// @Failure 429 {object} apperrors.Problem "Слишком много попыток входа"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	start := time.Now()
@@ -96,9 +98,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	var req model.UserLogin
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warnf("invalid request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: apperrors.ErrBadRequest.Message,
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
@@ -108,15 +108,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	tokens, err := h.service.Login(c.Request.Context(), req, userAgent, ip)
 	if err != nil {
 		h.logger.Warnf("login failed for %s: %v", req.Email, err)
-		if errors.Is(err, service.ErrInvalidCredentials) {
-			c.JSON(apperrors.ErrUnauthorized.Status, apperrors.ErrorResponse{
-				Error: "Неверный email или пароль",
-			})
+		var lockout *service.LockoutError
+		if errors.As(err, &lockout) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockout.RetryAfter.Seconds()))))
+			helper.Abort(c, apperrors.ErrTooManyRequests.WithDetail("Слишком много неудачных попыток входа. Попробуйте позже"))
 			return
 		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: apperrors.ErrInternal.Message,
-		})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -134,9 +132,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Produce json
 // @Param input body model.SSOLoginRequest true "Данные для SSO входа"
 // @Success 200 {object} model.TokenResponse "Успешный вход"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Ошибка авторизации через SSO"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Ошибка авторизации через SSO"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /auth/sso/login [post]
 func (h *AuthHandler) SSOLogin(c *gin.Context) {
 	start := time.Now()
@@ -145,21 +143,17 @@ func (h *AuthHandler) SSOLogin(c *gin.Context) {
 	var req model.SSOLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warnf("invalid SSO request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: apperrors.ErrBadRequest.Message,
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	userAgent := c.GetHeader("User-Agent")
 	ip := c.ClientIP()
 
-	tokens, err := h.service.SSOLogin(c.Request.Context(), req.Provider, req.ProviderID, req.Email, req.Name, userAgent, ip)
+	tokens, err := h.service.SSOLogin(c.Request.Context(), req.Provider, req.ProviderID, sso.AuthParams{}, req.Email, req.Name, userAgent, ip)
 	if err != nil {
 		h.logger.Warnf("SSO login failed for provider.go %s: %v", req.Provider, err)
-		c.JSON(apperrors.ErrUnauthorized.Status, apperrors.ErrorResponse{
-			Error: "Ошибка авторизации через SSO",
-		})
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Ошибка авторизации через SSO"))
 		return
 	}
 
@@ -177,9 +171,9 @@ func (h *AuthHandler) SSOLogin(c *gin.Context) {
 // @Produce json
 // @Param input body model.RefreshRequest true "Refresh токен"
 // @Success 200 {object} model.TokenResponse "Новые токены"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Просроченный или недействительный токен"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Просроченный или недействительный токен"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /auth/refresh [post]
 func (h *AuthHandler) Refresh(c *gin.Context) {
 	start := time.Now()
@@ -189,9 +183,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	refreshCookie, err := c.Cookie("refresh_token")
 	if err != nil || refreshCookie == "" {
 		h.logger.Warn("refresh token cookie not found")
-		c.JSON(apperrors.ErrUnauthorized.Status, apperrors.ErrorResponse{
-			Error: "Просроченный или недействительный токен",
-		})
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Просроченный или недействительный токен"))
 		return
 	}
 
@@ -202,9 +194,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	tokens, err := h.service.Refresh(c.Request.Context(), refreshCookie, userAgent, ip)
 	if err != nil {
 		h.logger.Warnf("refresh token failed: %v", err)
-		c.JSON(apperrors.ErrUnauthorized.Status, apperrors.ErrorResponse{
-			Error: "Просроченный или недействительный токен",
-		})
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Просроченный или недействительный токен"))
 		return
 	}
 
@@ -223,7 +213,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 func (h *AuthHandler) Logout(c *gin.Context) {
 	refreshCookie, err := c.Cookie("refresh_token")
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token not found"})
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Refresh token не найден"))
 		return
 	}
 
@@ -232,7 +222,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 	if err := h.service.Logout(c.Request.Context(), refreshCookie); err != nil {
 		h.logger.Warnf("logout failed: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		helper.Abort(c, service.ErrInvalidRefreshToken)
 		return
 	}
 
@@ -249,7 +239,7 @@ func (h *AuthHandler) LogoutAll(c *gin.Context) {
 
 	if err := h.service.LogoutAll(c.Request.Context(), userID); err != nil {
 		h.logger.Warnf("logout all failed: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not logout all"})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -260,6 +250,230 @@ func (h *AuthHandler) LogoutAll(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "logged out from all sessions"})
 }
 
+// Unlock снимает блокировку входа с пользователя
+// @Summary Разблокировать вход пользователя
+// @Description Сбрасывает счётчик неудачных попыток входа и блокировку для аккаунта (только для администраторов)
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} apperrors.SuccessResponse "Блокировка снята"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/unlock [post]
+func (h *AuthHandler) Unlock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
+		return
+	}
+
+	adminID := c.GetInt("userID")
+
+	if err := h.service.Unlock(c.Request.Context(), id, adminID); err != nil {
+		h.logger.Errorf("failed to unlock user %d: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	h.logger.Infof("user %d unlocked by admin %d", id, adminID)
+	helper.Success(c, http.StatusOK, "Блокировка снята")
+}
+
+// Block блокирует аккаунт пользователя
+// @Summary Заблокировать пользователя
+// @Description Запрещает вход и обновление токенов, отзывает активные сессии (только для администраторов). Нельзя заблокировать пользователя с правами шире ваших
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пользователя"
+// @Param input body model.UserBlock false "Причина блокировки"
+// @Success 200 {object} apperrors.SuccessResponse "Пользователь заблокирован"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен или у пользователя права шире ваших"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/block [post]
+func (h *AuthHandler) Block(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
+		return
+	}
+
+	var req model.UserBlock
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helper.Abort(c, helper.BindingError(err))
+			return
+		}
+	}
+
+	admin, ok := helper.CurrentClaims(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized)
+		return
+	}
+
+	if err := h.service.Block(c.Request.Context(), id, admin, req.Reason); err != nil {
+		h.logger.Errorf("failed to block user %d: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	h.logger.Infof("user %d blocked by admin %d", id, admin.UserID)
+	helper.Success(c, http.StatusOK, "Пользователь заблокирован")
+}
+
+// Unblock снимает блокировку аккаунта
+// @Summary Разблокировать пользователя
+// @Description Снимает блокировку, выставленную администратором (только для администраторов). Нельзя разблокировать пользователя с правами шире ваших
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} apperrors.SuccessResponse "Пользователь разблокирован"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен или у пользователя права шире ваших"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/unblock [post]
+func (h *AuthHandler) Unblock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
+		return
+	}
+
+	admin, ok := helper.CurrentClaims(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized)
+		return
+	}
+
+	if err := h.service.Unblock(c.Request.Context(), id, admin); err != nil {
+		h.logger.Errorf("failed to unblock user %d: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	h.logger.Infof("user %d unblocked by admin %d", id, admin.UserID)
+	helper.Success(c, http.StatusOK, "Пользователь разблокирован")
+}
+
+// Impersonate выдаёт токен для входа от имени пользователя
+// @Summary Войти от имени пользователя
+// @Description Возвращает короткоживущий access token пользователя для поддержки. В токене сохраняется ID администратора (impersonator_id), refresh-токен не выдаётся. Нельзя войти от имени сервисного аккаунта, заблокированного пользователя или пользователя с правами шире ваших
+// @Tags admin
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пользователя"
+// @Success 200 {object} model.ImpersonationResponse "Токен пользователя"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Вход от имени этого пользователя запрещен"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/impersonate [post]
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
+		return
+	}
+
+	admin, ok := helper.CurrentClaims(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized)
+		return
+	}
+
+	resp, err := h.service.Impersonate(c.Request.Context(), id, admin, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		h.logger.Warnf("admin %d failed to impersonate user %d: %v", admin.UserID, id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	h.logger.Infof("admin %d impersonates user %d until %s", admin.UserID, id, resp.ExpiresAt.Format(time.RFC3339))
+	c.JSON(http.StatusOK, resp)
+}
+
+// PhoneCode отправляет код для входа по телефону
+// @Summary Запросить код для входа по телефону
+// @Description Отправляет одноразовый код в SMS. Номер приводится к формату E.164
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.PhoneCodeRequest true "Номер телефона"
+// @Success 200 {object} model.PhoneCodeResponse "Код отправлен"
+// @Failure 400 {object} apperrors.Problem "Некорректный номер телефона"
+// @Failure 429 {object} apperrors.Problem "Код уже отправлен, повторить можно позже"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /auth/phone/code [post]
+func (h *AuthHandler) PhoneCode(c *gin.Context) {
+	var req model.PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	resp, err := h.service.RequestPhoneCode(c.Request.Context(), req.Phone, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		h.logger.Warnf("failed to send phone code: %v", err)
+		var lockout *service.LockoutError
+		if errors.As(err, &lockout) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockout.RetryAfter.Seconds()))))
+			helper.Abort(c, apperrors.ErrTooManyRequests.WithDetail("Код уже отправлен. Повторить можно позже"))
+			return
+		}
+		helper.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// PhoneLogin вход по телефону и коду из SMS
+// @Summary Вход по телефону
+// @Description Проверяет код из SMS и выдаёт токены. Пользователь создаётся при первом входе
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body model.PhoneLoginRequest true "Номер телефона и код"
+// @Success 200 {object} model.TokenResponse "Успешный вход"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Неверный или просроченный код"
+// @Failure 403 {object} apperrors.Problem "Аккаунт заблокирован"
+// @Failure 429 {object} apperrors.Problem "Превышено число попыток ввода кода"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /auth/phone/login [post]
+func (h *AuthHandler) PhoneLogin(c *gin.Context) {
+	var req model.PhoneLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	tokens, err := h.service.PhoneLogin(c.Request.Context(), req, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		h.logger.Warnf("phone login failed: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.SetRefreshCookie(c, tokens.RefreshToken, h.t.RefreshTTL())
+	c.JSON(http.StatusOK, model.TokenResponse{AccessToken: tokens.AccessToken})
+}
+
 func RegisterAuthRoutes(rg *gin.RouterGroup, authHandler *AuthHandler) {
 	auth := rg.Group("/auth")
 	auth.POST("/register", authHandler.Register)
@@ -267,4 +481,45 @@ func RegisterAuthRoutes(rg *gin.RouterGroup, authHandler *AuthHandler) {
 	auth.POST("/refresh", authHandler.Refresh)
 	auth.POST("/logout", authHandler.Logout)
 	auth.POST("/logout/all", authHandler.LogoutAll)
+	auth.POST("/phone/code", authHandler.PhoneCode)
+	auth.POST("/phone/login", authHandler.PhoneLogin)
+}
+
+// ConfirmPhone привязывает телефон к текущему пользователю
+// @Summary Подтвердить телефон
+// @Description Проверяет код, отправленный на /auth/phone/code, и сохраняет номер в профиле. После этого по номеру можно входить
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param input body model.PhoneLoginRequest true "Номер телефона и код"
+// @Success 200 {object} apperrors.SuccessResponse "Телефон подтвержден"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Неверный или просроченный код"
+// @Failure 403 {object} apperrors.Problem "Недоступно при входе от имени пользователя"
+// @Failure 409 {object} apperrors.Problem "Номер привязан к другому аккаунту"
+// @Failure 429 {object} apperrors.Problem "Превышено число попыток ввода кода"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/phone [put]
+func (h *AuthHandler) ConfirmPhone(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	var req model.PhoneLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	if _, err := h.service.ConfirmPhone(c.Request.Context(), userID, req); err != nil {
+		h.logger.Warnf("failed to confirm phone of user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.Success(c, http.StatusOK, "Телефон подтвержден")
 }
@@ -2,10 +2,10 @@ package handler
 
 import (
 	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -29,16 +29,31 @@ func NewBus(logger *logger.Logger, bus service.Bus) *BusHandler {
 // @Description Возвращает список всех автобусов в системе
 // @Tags buses
 // @Produce json
-// @Success 200 {array} model.Bus "Список автобусов"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Param limit query int false "Размер страницы, по умолчанию 20, не больше 100"
+// @Param page query int false "Номер страницы, в ответе появится total"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Поле сортировки: id, license_plate, brand, capacity. С '-' — по убыванию"
+// @Param filter query string false "Фильтр вида filter[поле][оператор]=значение, например filter[status_id]=3. Поля: id, license_plate, brand, capacity, category_id, status_id, created_at. Операторы: eq (по умолчанию), ne, gt, gte, lt, lte, in (через запятую), contains, null"
+// @Success 200 {object} paging.Page[model.ViewBus] "Страница автобусов"
+// @Failure 400 {object} apperrors.Problem "Некорректные параметры списка"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /buses [get]
+// @Router /admin/bus [get]
 func (h *BusHandler) GetAllBuses(c *gin.Context) {
-	buses, err := h.bus.GetAllBuses(c.Request.Context())
+	p, ok := helper.PagingParams(c)
+	if !ok {
+		return
+	}
+	f, ok := helper.FilterParams(c)
+	if !ok {
+		return
+	}
+
+	buses, err := h.bus.GetAllBuses(c.Request.Context(), f, p)
 	if err != nil {
 		h.logger.Errorf("failed to get all buses: %v", err)
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: apperrors.ErrInternal.Message,
-		})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -52,33 +67,26 @@ func (h *BusHandler) GetAllBuses(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID автобуса"
 // @Success 200 {object} model.Bus "Данные автобуса"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 404 {object} apperrors.ErrorResponse "Автобус не найден"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 404 {object} apperrors.Problem "Автобус не найден"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /api/v1/buses/{id} [get]
+// @Router /admin/bus/{id} [get]
 func (h *BusHandler) GetBus(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID автобуса",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID автобуса"))
 		return
 	}
 
 	bus, err := h.bus.GetBus(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Errorf("failed to get bus %d: %v", id, err)
-		if errors.Is(err, service.ErrBusNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{
-				Error: "Автобус не найден",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: apperrors.ErrInternal.Message,
-		})
+		helper.Abort(c, err)
 		return
 	}
 
+	helper.SetETag(c, bus.UpdatedAt)
 	c.JSON(http.StatusOK, bus)
 }
 
@@ -89,31 +97,28 @@ func (h *BusHandler) GetBus(c *gin.Context) {
 // @Tags admin/bus
 // @Accept json
 // @Produce json
-// @Param input body model.Bus true "Данные автобуса"
+// @Param input body model.BusCreate true "Данные автобуса"
 // @Success 200 {object} apperrors.SuccessResponse "Автобус успешно создан"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/bus [post]
 func (h *BusHandler) CreateBus(c *gin.Context) {
-	var bus model.Bus
+	var bus model.BusCreate
 	if err := c.ShouldBindJSON(&bus); err != nil {
 		h.logger.Warnf("invalid request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректные данные автобуса",
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	if err := h.bus.CreateBus(c.Request.Context(), bus); err != nil {
 		h.logger.Errorf("failed to create bus: %v", err)
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось создать автобус"})
+		helper.Abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, apperrors.SuccessResponse{Message: "Автобус успешно создан"})
+	helper.Success(c, http.StatusOK, "Автобус успешно создан")
 }
 
 // UpdateBus updates an existing bus (Admin only)
@@ -126,18 +131,16 @@ func (h *BusHandler) CreateBus(c *gin.Context) {
 // @Param id path int true "ID автобуса"
 // @Param input body model.BusUpdate true "Обновленные данные автобуса"
 // @Success 200 {object} apperrors.SuccessResponse "Данные автобуса обновлены"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Автобус не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Автобус не найден"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/bus/{id} [put]
 func (h *BusHandler) UpdateBus(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID автобуса",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID автобуса"))
 		return
 	}
 
@@ -145,23 +148,58 @@ func (h *BusHandler) UpdateBus(c *gin.Context) {
 	update.ID = id
 	if err := c.ShouldBindJSON(&update); err != nil {
 		h.logger.Warnf("invalid request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректные данные для обновления",
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	if err := h.bus.UpdateBus(c.Request.Context(), update); err != nil {
 		h.logger.Errorf("failed to update bus %d: %v", id, err)
-		if errors.Is(err, service.ErrBusNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{Error: "Автобус не найден"})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{Error: "Не удалось обновить данные автобуса"})
+		helper.Abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, apperrors.SuccessResponse{Message: "Данные автобуса успешно обновлены"})
+	helper.Success(c, http.StatusOK, "Данные автобуса успешно обновлены")
+}
+
+// PatchBus частично обновляет автобус (JSON Merge Patch)
+// @Summary Частично обновить автобус (только админ)
+// @Description Применяет JSON Merge Patch (RFC 7396). Версию из ETag можно передать в If-Match: если автобус уже изменили, вернётся 412
+// @Security Bearer
+// @Tags admin/bus
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "ID автобуса"
+// @Param If-Match header string false "ETag из ответа GET"
+// @Param input body model.BusPatch true "Изменяемые поля"
+// @Success 200 {object} model.BusPatch "Автобус после изменения, новая версия в ETag"
+// @Failure 400 {object} apperrors.Problem "Некорректный патч"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Автобус не найден"
+// @Failure 412 {object} apperrors.Problem "Автобус изменён другим пользователем"
+// @Failure 415 {object} apperrors.Problem "Неподдерживаемый тип содержимого"
+// @Failure 422 {object} apperrors.Problem "Ошибка валидации"
+// @Router /admin/bus/{id} [patch]
+func (h *BusHandler) PatchBus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID автобуса"))
+		return
+	}
+	patch, ok := helper.MergePatchBody(c)
+	if !ok {
+		return
+	}
+
+	bus, err := h.bus.Patch(c.Request.Context(), id, patch, helper.IfMatch(c))
+	if err != nil {
+		h.logger.Warnf("failed to patch bus %d: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.SetETag(c, bus.UpdatedAt)
+	c.JSON(http.StatusOK, bus)
 }
 
 // DeleteBus removes a bus by ID (Admin only)
@@ -172,30 +210,106 @@ func (h *BusHandler) UpdateBus(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID автобуса"
 // @Success 204 "Автобус успешно удален"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Автобус не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Автобус не найден"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/bus/{id} [delete]
 func (h *BusHandler) DeleteBus(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID автобуса",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID автобуса"))
 		return
 	}
 
 	if err = h.bus.DeleteBus(c.Request.Context(), id); err != nil {
 		h.logger.Errorf("failed to delete bus %d: %v", id, err)
-		if errors.Is(err, service.ErrBusNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{Error: "Автобус не найден"})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{Error: "Не удалось удалить автобус"})
+		helper.Abort(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// RestoreBus восстанавливает удалённую запись
+// @Summary Восстановить автобус
+// @Description Снимает отметку об удалении, пока запись не удалена окончательно
+// @Tags admin/bus
+// @Security Bearer
+// @Param id path int true "ID автобуса"
+// @Success 204 "Запись восстановлена"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Запись не найдена среди удалённых"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/bus/{id}/restore [post]
+func (h *BusHandler) RestoreBus(c *gin.Context) {
+	restoreByID(c, h.logger, h.bus.Restore, service.ErrBusNotFound, "Автобус не найден среди удалённых")
+}
+
+// BulkCreate creates several buses at once (Admin only)
+// @Summary Создать несколько автобусов (только админ)
+// @Description Проверяет все автобусы и создаёт их в одной транзакции: при ошибке не создаётся ни один. Индексы элементов с ошибками — в поле failed
+// @Security Bearer
+// @Tags admin/bus
+// @Accept json
+// @Produce json
+// @Param input body []model.BusCreate true "Автобусы, не больше 500"
+// @Success 201 {object} model.BulkResult "ID созданных автобусов в порядке запроса"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 409 {object} apperrors.Problem "Автобус с таким номером уже существует"
+// @Failure 422 {object} apperrors.Problem "Ошибки валидации элементов"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
+// @Router /admin/bus/bulk [post]
+func (h *BusHandler) BulkCreate(c *gin.Context) {
+	input, ok := helper.BindBulk[model.BusCreate](c)
+	if !ok {
+		return
+	}
+	ids, err := h.bus.CreateMany(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Errorf("failed to create buses: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, model.NewBulkResult(ids))
+}
+
+// BulkUpdate updates several buses at once (Admin only)
+// @Summary Обновить несколько автобусов (только админ)
+// @Description Проверяет все автобусы и обновляет их в одной транзакции: при ошибке не меняется ни один. Индексы элементов с ошибками — в поле failed
+// @Security Bearer
+// @Tags admin/bus
+// @Accept json
+// @Produce json
+// @Param input body []model.BulkUpdate[model.BusUpdate] true "ID автобусов и изменяемые поля, не больше 500"
+// @Success 200 {object} model.BulkResult "ID обновлённых автобусов в порядке запроса"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Автобус не найден"
+// @Failure 422 {object} apperrors.Problem "Ошибки валидации элементов"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
+// @Router /admin/bus/bulk [put]
+func (h *BusHandler) BulkUpdate(c *gin.Context) {
+	input, ok := helper.BindBulk[model.BulkUpdate[model.BusUpdate]](c)
+	if !ok {
+		return
+	}
+	buses := make([]*model.BusUpdate, len(input))
+	ids := make([]int, len(input))
+	for i := range input {
+		input[i].Data.ID = input[i].ID
+		buses[i], ids[i] = &input[i].Data, input[i].ID
+	}
+	if err := h.bus.UpdateMany(c.Request.Context(), buses); err != nil {
+		h.logger.Errorf("failed to update buses: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.NewBulkResult(ids))
+}
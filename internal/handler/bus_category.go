@@ -2,10 +2,10 @@ package handler
 
 import (
 	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
-	"errors"
 	"net/http"
 	"strconv"
 
@@ -31,15 +31,15 @@ func NewBusCategory(logger *logger.Logger, bc service.BusCategory) *BusCategoryH
 // @Tags bus/categories
 // @Produce json
 // @Success 200 {array} model.BusCategory "Список категорий автобусов"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /bus/categories [get]
+// @Router /admin/bus/categories [get]
 func (h *BusCategoryHandler) GetAll(c *gin.Context) {
 	output, err := h.bc.GetAll(c.Request.Context())
 	if err != nil {
 		h.logger.Errorf("error while getting categories: %v", err)
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось получить список категорий",
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, output)
@@ -52,31 +52,23 @@ func (h *BusCategoryHandler) GetAll(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID категории"
 // @Success 200 {object} model.BusCategory "Данные категории"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 404 {object} apperrors.ErrorResponse "Категория не найдена"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 404 {object} apperrors.Problem "Категория не найдена"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /bus/categories/{id} [get]
+// @Router /admin/bus/categories/{id} [get]
 func (h *BusCategoryHandler) GetById(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID категории",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID категории"))
 		return
 	}
 
 	output, err := h.bc.GetById(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Errorf("failed to get category: %v", err)
-		if errors.Is(err, service.ErrBusCategoryNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{
-				Error: "Категория не найдена",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось получить данные категории",
-		})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -92,39 +84,27 @@ func (h *BusCategoryHandler) GetById(c *gin.Context) {
 // @Security Bearer
 // @Param input body model.BusCategory true "Данные категории"
 // @Success 201 {object} apperrors.SuccessResponse "Категория успешно создана"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен. Требуются права администратора"
-// @Failure 409 {object} apperrors.ErrorResponse "Категория с таким названием уже существует"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен. Требуются права администратора"
+// @Failure 409 {object} apperrors.Problem "Категория с таким названием уже существует"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/bus/categories [post]
 func (h *BusCategoryHandler) Create(c *gin.Context) {
 	var input model.BusCategory
 	if err := c.ShouldBindJSON(&input); err != nil {
 		h.logger.Warnf("invalid request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректные данные категории",
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	if err := h.bc.Create(c.Request.Context(), input); err != nil {
 		h.logger.Errorf("failed to create category: %v", err)
-		if errors.Is(err, service.ErrBusCategoryExists) {
-			c.JSON(http.StatusConflict, apperrors.ErrorResponse{
-				Error: "Категория с таким названием уже существует",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось создать категорию",
-		})
+		helper.Abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, apperrors.SuccessResponse{
-		Message: "Категория успешно создана",
-	})
+	helper.Success(c, http.StatusCreated, "Категория успешно создана")
 }
 
 // Delete удаляет категорию автобуса
@@ -135,31 +115,22 @@ func (h *BusCategoryHandler) Create(c *gin.Context) {
 // @Security Bearer
 // @Param id path int true "ID категории"
 // @Success 204 "Категория успешно удалена"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен. Требуются права администратора"
-// @Failure 404 {object} apperrors.ErrorResponse "Категория не найдена"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен. Требуются права администратора"
+// @Failure 404 {object} apperrors.Problem "Категория не найдена"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/bus/categories/{id} [delete]
 func (h *BusCategoryHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID категории",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID категории"))
 		return
 	}
 
 	if err := h.bc.Delete(c.Request.Context(), id); err != nil {
 		h.logger.Errorf("failed to delete category %d: %v", id, err)
-		if errors.Is(err, service.ErrBusCategoryNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{
-				Error: "Категория не найдена",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось удалить категорию"})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -176,28 +147,24 @@ func (h *BusCategoryHandler) Delete(c *gin.Context) {
 // @Param id path int true "ID категории"
 // @Param input body model.BusCategory true "Обновленные данные категории"
 // @Success 200 {object} model.BusCategory "Обновленные данные категории"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен. Требуются права администратора"
-// @Failure 404 {object} apperrors.ErrorResponse "Категория не найдена"
-// @Failure 409 {object} apperrors.ErrorResponse "Категория с таким названием уже существует"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен. Требуются права администратора"
+// @Failure 404 {object} apperrors.Problem "Категория не найдена"
+// @Failure 409 {object} apperrors.Problem "Категория с таким названием уже существует"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/bus/categories/{id} [put]
 func (h *BusCategoryHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID категории",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID категории"))
 		return
 	}
 
 	var category model.BusCategory
 	if err := c.ShouldBindJSON(&category); err != nil {
 		h.logger.Warnf("invalid request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректные данные для обновления",
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
@@ -206,16 +173,26 @@ func (h *BusCategoryHandler) Update(c *gin.Context) {
 	updatedCategory, err := h.bc.Update(c.Request.Context(), category)
 	if err != nil {
 		h.logger.Errorf("failed to update category %d: %v", id, err)
-		switch {
-		case errors.Is(err, service.ErrBusCategoryNotFound):
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{Error: "Категория не найдена"})
-		case errors.Is(err, service.ErrBusCategoryExists):
-			c.JSON(http.StatusConflict, apperrors.ErrorResponse{Error: "Категория с таким названием уже существует"})
-		default:
-			c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{Error: "Не удалось обновить категорию"})
-		}
+		helper.Abort(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, updatedCategory)
 }
+
+// Restore восстанавливает удалённую запись
+// @Summary Восстановить категорию автобуса
+// @Description Снимает отметку об удалении, пока запись не удалена окончательно
+// @Tags admin/bus/categories
+// @Security Bearer
+// @Param id path int true "ID категории"
+// @Success 204 "Запись восстановлена"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Запись не найдена среди удалённых"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/bus/categories/{id}/restore [post]
+func (h *BusCategoryHandler) Restore(c *gin.Context) {
+	restoreByID(c, h.logger, h.bc.Restore, service.ErrBusCategoryNotFound, "Категория не найдена среди удалённых")
+}
@@ -2,10 +2,10 @@ package handler
 
 import (
 	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
@@ -29,16 +29,14 @@ func NewBusStatus(logger *logger.Logger, bs service.BusStatus) *BusStatusHandler
 // @Tags bus/statuses
 // @Produce json
 // @Success 200 {array} model.BusStatus "Список статусов автобусов"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /bus/statuses [get]
+// @Router /admin/bus/statuses [get]
 func (h *BusStatusHandler) All(c *gin.Context) {
 	output, err := h.bs.All(c.Request.Context())
 	if err != nil {
-		if errors.Is(err, service.ErrBusStatusNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{Error: apperrors.ErrNotFound.Message})
-			return
-		}
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, apperrors.ErrBadRequest)
 		return
 	}
 
@@ -52,24 +50,22 @@ func (h *BusStatusHandler) All(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID статуса"
 // @Success 200 {object} model.BusStatus "Данные статуса"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 404 {object} apperrors.ErrorResponse "Статус не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 404 {object} apperrors.Problem "Статус не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /bus/statuses/{id} [get]
+// @Router /admin/bus/statuses/{id} [get]
 func (h *BusStatusHandler) ByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, apperrors.ErrBadRequest)
 		return
 	}
 
 	output, err := h.bs.ByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, service.ErrBusStatusNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{Error: apperrors.ErrNotFound.Message})
-			return
-		}
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, apperrors.ErrBadRequest)
 		return
 	}
 
@@ -85,29 +81,25 @@ func (h *BusStatusHandler) ByID(c *gin.Context) {
 // @Security Bearer
 // @Param input body model.BusStatus true "Данные статуса"
 // @Success 201 {object} apperrors.SuccessResponse "Статус успешно создан"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен. Требуются права администратора"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен. Требуются права администратора"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/bus/statuses [post]
 func (h *BusStatusHandler) Create(c *gin.Context) {
 	var req model.BusStatus
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	err := h.bs.Create(c.Request.Context(), req)
 	if err != nil {
-		if errors.Is(err, service.ErrBusStatusNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{Error: apperrors.ErrNotFound.Message})
-			return
-		}
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, apperrors.ErrBadRequest)
 		return
 	}
 
-	c.JSON(http.StatusCreated, apperrors.SuccessResponse{Message: "Статус успешно создан"})
+	helper.Success(c, http.StatusCreated, "Статус успешно создан")
 }
 
 // Update godoc
@@ -120,33 +112,29 @@ func (h *BusStatusHandler) Create(c *gin.Context) {
 // @Param id path int true "ID статуса"
 // @Param input body model.BusStatus true "Обновленные данные статуса"
 // @Success 200 {object} model.BusStatus "Обновленные данные статуса"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен. Требуются права администратора"
-// @Failure 404 {object} apperrors.ErrorResponse "Статус не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен. Требуются права администратора"
+// @Failure 404 {object} apperrors.Problem "Статус не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/bus/statuses/{id} [put]
 func (h *BusStatusHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, apperrors.ErrBadRequest)
 		return
 	}
 
 	var req model.BusStatus
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	req.ID = id
 	status, err := h.bs.Update(c.Request.Context(), req)
 	if err != nil {
-		if errors.Is(err, service.ErrBusStatusNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{Error: apperrors.ErrNotFound.Message})
-			return
-		}
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, apperrors.ErrBadRequest)
 		return
 	}
 
@@ -161,28 +149,41 @@ func (h *BusStatusHandler) Update(c *gin.Context) {
 // @Security Bearer
 // @Param id path int true "ID статуса"
 // @Success 204 "Статус успешно удален"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен. Требуются права администратора"
-// @Failure 404 {object} apperrors.ErrorResponse "Статус не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен. Требуются права администратора"
+// @Failure 404 {object} apperrors.Problem "Статус не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/bus/statuses/{id} [delete]
 func (h *BusStatusHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, apperrors.ErrBadRequest)
 		return
 	}
 
 	err = h.bs.Delete(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, service.ErrBusStatusNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{Error: apperrors.ErrNotFound.Message})
-			return
-		}
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{Error: apperrors.ErrBadRequest.Message})
+		helper.Abort(c, apperrors.ErrBadRequest)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// Restore восстанавливает удалённую запись
+// @Summary Восстановить статус автобуса
+// @Description Снимает отметку об удалении, пока запись не удалена окончательно
+// @Tags admin/bus/statuses
+// @Security Bearer
+// @Param id path int true "ID статуса"
+// @Success 204 "Запись восстановлена"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Запись не найдена среди удалённых"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/bus/statuses/{id}/restore [post]
+func (h *BusStatusHandler) Restore(c *gin.Context) {
+	restoreByID(c, h.logger, h.bs.Restore, service.ErrBusStatusNotFound, "Статус не найден среди удалённых")
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
@@ -27,14 +28,30 @@ func NewDriver(logger *logger.Logger, s service.Driver) *Driver {
 // @Description Возвращает список всех водителей в системе
 // @Tags drivers
 // @Produce json
-// @Success 200 {array} model.Driver "Список водителей"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Param limit query int false "Размер страницы, по умолчанию 20, не больше 100"
+// @Param page query int false "Номер страницы, в ответе появится total"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Поле сортировки: id, last_name, first_name. С '-' — по убыванию"
+// @Param filter query string false "Фильтр вида filter[поле][оператор]=значение, например filter[status_id]=3. Поля: id, first_name, last_name, phone_number, status_id. Операторы: eq (по умолчанию), ne, gt, gte, lt, lte, in (через запятую), contains, null"
+// @Success 200 {object} paging.Page[model.DriverOutput] "Страница водителей"
+// @Failure 400 {object} apperrors.Problem "Некорректные параметры списка"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /driver [get]
+// @Router /admin/driver [get]
 func (h *Driver) All(c *gin.Context) {
-	drivers, err := h.s.All(c.Request.Context())
+	p, ok := helper.PagingParams(c)
+	if !ok {
+		return
+	}
+	f, ok := helper.FilterParams(c)
+	if !ok {
+		return
+	}
+	drivers, err := h.s.All(c.Request.Context(), f, p)
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, apperrors.ErrInternal.Message)
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, drivers)
@@ -47,20 +64,22 @@ func (h *Driver) All(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID водителя"
 // @Success 200 {object} model.Driver "Данные водителя"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 404 {object} apperrors.ErrorResponse "Водитель не найден"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 404 {object} apperrors.Problem "Водитель не найден"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /driver/{id} [get]
+// @Router /admin/driver/{id} [get]
 func (h *Driver) ByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID водителя"))
 		return
 	}
 	driver, err := h.s.ByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrNotFound.Status, err)
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, driver)
@@ -75,27 +94,27 @@ func (h *Driver) ByID(c *gin.Context) {
 // @Produce json
 // @Param input body model.DriverInput true "Данные водителя"
 // @Success 201 {object} apperrors.SuccessResponse "Водитель успешно создан"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/driver [post]
 func (h *Driver) Create(c *gin.Context) {
 	var driver model.DriverInput
 	err := c.ShouldBind(&driver)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, helper.BindingError(err))
+		return
 	}
 
 	err = h.s.Create(c.Request.Context(), driver)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrInternal.Status, err)
+		helper.Abort(c, err)
+		return
 	}
-	c.JSON(http.StatusCreated, apperrors.SuccessResponse{
-		Message: "created",
-	})
+	helper.Success(c, http.StatusCreated, "Водитель успешно создан")
 }
 
 // Update updates an existing driver (Admin only)
@@ -108,17 +127,17 @@ func (h *Driver) Create(c *gin.Context) {
 // @Param id path int true "ID водителя"
 // @Param input body model.DriverInput true "Обновленные данные водителя"
 // @Success 200 {object} apperrors.SuccessResponse "Данные водителя обновлены"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Водитель не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Водитель не найден"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/driver/{id} [put]
 func (h *Driver) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID водителя"))
 		return
 	}
 
@@ -127,19 +146,17 @@ func (h *Driver) Update(c *gin.Context) {
 	err = c.ShouldBind(&driver)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 	err = h.s.Update(c.Request.Context(), driver)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrInternal.Status, err)
+		helper.Abort(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, apperrors.SuccessResponse{
-		Message: "updated",
-	})
+	helper.Success(c, http.StatusOK, "Данные водителя обновлены")
 }
 
 // Delete removes a driver by ID (Admin only)
@@ -150,26 +167,106 @@ func (h *Driver) Update(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID водителя"
 // @Success 204 "Водитель успешно удален"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Водитель не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Водитель не найден"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/driver/{id} [delete]
 func (h *Driver) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID водителя"))
 		return
 	}
 	err = h.s.Delete(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrInternal.Status, err)
+		helper.Abort(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Restore восстанавливает удалённую запись
+// @Summary Восстановить водителя
+// @Description Снимает отметку об удалении, пока запись не удалена окончательно
+// @Tags admin/driver
+// @Security Bearer
+// @Param id path int true "ID водителя"
+// @Success 204 "Запись восстановлена"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Запись не найдена среди удалённых"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/driver/{id}/restore [post]
+func (h *Driver) Restore(c *gin.Context) {
+	restoreByID(c, h.logger, h.s.Restore, service.ErrDriverNotFound, "Водитель не найден среди удалённых")
+}
+
+// BulkCreate creates several drivers at once (Admin only)
+// @Summary Создать несколько водителей (только админ)
+// @Description Проверяет всех водителей и создаёт их в одной транзакции: при ошибке не создаётся ни один. Индексы элементов с ошибками — в поле failed
+// @Security Bearer
+// @Tags admin/driver
+// @Accept json
+// @Produce json
+// @Param input body []model.DriverInput true "Водители, не больше 500"
+// @Success 201 {object} model.BulkResult "ID созданных водителей в порядке запроса"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 409 {object} apperrors.Problem "Элемент конфликтует с существующими данными"
+// @Failure 422 {object} apperrors.Problem "Ошибки валидации элементов"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
+// @Router /admin/driver/bulk [post]
+func (h *Driver) BulkCreate(c *gin.Context) {
+	input, ok := helper.BindBulk[model.DriverInput](c)
+	if !ok {
+		return
+	}
+	ids, err := h.s.CreateMany(c.Request.Context(), input)
+	if err != nil {
+		h.logger.Error(err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, model.NewBulkResult(ids))
+}
+
+// BulkUpdate updates several drivers at once (Admin only)
+// @Summary Обновить данные нескольких водителей (только админ)
+// @Description Проверяет всех водителей и обновляет их в одной транзакции: при ошибке не меняется ни один. Индексы элементов с ошибками — в поле failed
+// @Security Bearer
+// @Tags admin/driver
+// @Accept json
+// @Produce json
+// @Param input body []model.BulkUpdate[model.DriverInput] true "ID водителей и их данные, не больше 500"
+// @Success 200 {object} model.BulkResult "ID обновлённых водителей в порядке запроса"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Водитель не найден"
+// @Failure 422 {object} apperrors.Problem "Ошибки валидации элементов"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
+// @Router /admin/driver/bulk [put]
+func (h *Driver) BulkUpdate(c *gin.Context) {
+	input, ok := helper.BindBulk[model.BulkUpdate[model.DriverInput]](c)
+	if !ok {
+		return
+	}
+	drivers := make([]model.DriverInput, len(input))
+	ids := make([]int, len(input))
+	for i := range input {
+		input[i].Data.ID = input[i].ID
+		drivers[i], ids[i] = input[i].Data, input[i].ID
+	}
+	if err := h.s.UpdateMany(c.Request.Context(), drivers); err != nil {
+		h.logger.Error(err)
+		helper.Abort(c, err)
 		return
 	}
-	c.JSON(http.StatusNoContent, apperrors.SuccessResponse{
-		Message: "deleted",
-	})
+	c.JSON(http.StatusOK, model.NewBulkResult(ids))
 }
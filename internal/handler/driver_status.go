@@ -2,6 +2,7 @@ package handler
 
 import (
 	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
@@ -28,13 +29,15 @@ func NewDriverStatus(l *logger.Logger, s service.DriverStatus) *DriverStatus {
 // @Tags driver/status
 // @Produce json
 // @Success 200 {array} model.DriverStatus "Список статусов водителя"
-// @Failure 404 {object} apperrors.ErrorResponse "Ничего не найдено"
+// @Failure 404 {object} apperrors.Problem "Ничего не найдено"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /driver/status [get]
+// @Router /admin/driver/status [get]
 func (h *DriverStatus) All(c *gin.Context) {
 	h.logger.Debug("All")
 	output := h.s.All(c.Request.Context())
 	if output == nil {
-		c.AbortWithError(apperrors.ErrNotFound.Status, apperrors.ErrNotFound)
+		helper.Abort(c, apperrors.ErrNotFound)
 		return
 	}
 	c.JSON(http.StatusOK, output)
@@ -47,26 +50,28 @@ func (h *DriverStatus) All(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID статуса"
 // @Success 200 {object} model.DriverStatus "Данные статуса"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 404 {object} apperrors.ErrorResponse "Категория не найдена"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /bus/categories/{id} [get]
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 404 {object} apperrors.Problem "Категория не найдена"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
+// @Router /driver/status/{id} [get]
+// @Router /admin/driver/status/{id} [get]
 func (h *DriverStatus) ById(c *gin.Context) {
 	h.logger.Debug("ById")
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusBadRequest, apperrors.ErrBadRequest)
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID статуса"))
 		return
 	}
 	output, err := h.s.ById(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, err)
+		return
 	}
 	if output.Name == "" {
-		h.logger.Error(err)
-		c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrNotFound.Message)
+		helper.Abort(c, service.ErrDriverStatusNotFound)
 		return
 	}
 	c.JSON(http.StatusOK, output)
@@ -78,10 +83,10 @@ func (h *DriverStatus) ById(c *gin.Context) {
 // @Tags admin/driver/status
 // @Produce json
 // @Param id path int true "ID статуса"
-// @Success 200 {object} model.DriverStatus "Данные статуса"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 404 {object} apperrors.ErrorResponse "Категория не найдена"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Success 200 {object} apperrors.SuccessResponse "Статус водителя создан"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 404 {object} apperrors.Problem "Категория не найдена"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/driver/status [post]
 func (h *DriverStatus) Create(c *gin.Context) {
 	h.logger.Debug("Create")
@@ -89,16 +94,16 @@ func (h *DriverStatus) Create(c *gin.Context) {
 	err := c.ShouldBindJSON(&status)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, helper.BindingError(err))
+		return
 	}
 	err = h.s.Create(c.Request.Context(), &status)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, apperrors.SuccessResponse{
-		Message: "created",
-	})
+	helper.Success(c, http.StatusOK, "Статус водителя создан")
 }
 
 // ById измменяет статус по ID
@@ -107,33 +112,34 @@ func (h *DriverStatus) Create(c *gin.Context) {
 // @Tags admin/driver/status
 // @Produce json
 // @Param id path int true "ID статуса"
-// @Success 200 {object} model.DriverStatus "Данные статуса"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 404 {object} apperrors.ErrorResponse "Категория не найдена"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Success 200 {object} apperrors.SuccessResponse "Статус водителя обновлён"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 404 {object} apperrors.Problem "Категория не найдена"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/driver/status/{id} [put]
 func (h *DriverStatus) Update(c *gin.Context) {
 	h.logger.Debug("Update")
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusBadRequest, apperrors.ErrBadRequest)
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID статуса"))
+		return
 	}
 	var status model.DriverStatus
 	status.ID = id
 	err = c.ShouldBindJSON(&status)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, helper.BindingError(err))
+		return
 	}
 	err = h.s.Update(c.Request.Context(), &status)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, apperrors.SuccessResponse{
-		Message: "updated",
-	})
+	helper.Success(c, http.StatusOK, "Статус водителя обновлён")
 }
 
 // Delete удаляет статус водителя
@@ -142,24 +148,41 @@ func (h *DriverStatus) Update(c *gin.Context) {
 // @Tags admin/driver/status
 // @Produce json
 // @Param id path int true "ID статуса"
-// @Success 204 {object} model.DriverStatus "Данные статуса"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 404 {object} apperrors.ErrorResponse "Категория не найдена"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Success 204 "Статус водителя удалён"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 404 {object} apperrors.Problem "Категория не найдена"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/driver/status/{id} [delete]
 func (h *DriverStatus) Delete(c *gin.Context) {
 	h.logger.Debug("Delete")
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, apperrors.ErrBadRequest)
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID статуса"))
+		return
 	}
 	err = h.s.Delete(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error(err)
-		c.AbortWithError(apperrors.ErrBadRequest.Status, err)
+		helper.Abort(c, err)
+		return
 	}
-	c.JSON(http.StatusNoContent, apperrors.SuccessResponse{
-		Message: "deleted",
-	})
+	c.Status(http.StatusNoContent)
+}
+
+// Restore восстанавливает удалённую запись
+// @Summary Восстановить статус водителя
+// @Description Снимает отметку об удалении, пока запись не удалена окончательно
+// @Tags admin/driver/status
+// @Security Bearer
+// @Param id path int true "ID статуса"
+// @Success 204 "Запись восстановлена"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Запись не найдена среди удалённых"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/driver/status/{id}/restore [post]
+func (h *DriverStatus) Restore(c *gin.Context) {
+	restoreByID(c, h.logger, h.s.Restore, service.ErrDriverStatusNotFound, "Статус не найден среди удалённых")
 }
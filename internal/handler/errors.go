@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/policy"
+	"corpord-api/internal/service"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// serviceErrors сопоставляет ошибки сервисов с ответами API.
+// Коды — часть контракта с клиентами: их можно добавлять, но не переименовывать
+var serviceErrors = []struct {
+	err error
+	api *apperrors.APIError
+}{
+	{service.ErrNoFields, apperrors.NewAPIError(http.StatusBadRequest, "no_fields", "Не указаны поля для обновления")},
+	{service.ErrUserNotFound, apperrors.NewAPIError(http.StatusNotFound, "user_not_found", "Пользователь не найден")},
+	{service.ErrInvalidCredentials, apperrors.NewAPIError(http.StatusUnauthorized, "invalid_credentials", "Неверный email или пароль")},
+	{service.ErrInvalidPass, apperrors.NewAPIError(http.StatusUnauthorized, "invalid_credentials", "Неверный email или пароль")},
+	{service.ErrUseSSOLogin, apperrors.NewAPIError(http.StatusBadRequest, "use_sso_login", "Войдите через SSO-провайдера")},
+	{service.ErrEmailExists, apperrors.NewAPIError(http.StatusConflict, "email_taken", "Пользователь с таким email уже существует")},
+	{service.ErrPhoneTaken, apperrors.NewAPIError(http.StatusConflict, "phone_taken", "Номер привязан к другому аккаунту")},
+	{service.ErrUserBlocked, apperrors.NewAPIError(http.StatusForbidden, "user_blocked", "Аккаунт заблокирован")},
+	{service.ErrCannotImpersonate, apperrors.NewAPIError(http.StatusForbidden, "impersonation_forbidden", "Вход от имени этого пользователя запрещен")},
+	{service.ErrPrivilegeEscalation, apperrors.NewAPIError(http.StatusForbidden, "privilege_escalation", "У роли или пользователя есть права, которых нет у вас")},
+	{service.ErrSelfAction, apperrors.NewAPIError(http.StatusBadRequest, "self_action", "Действие недоступно для собственного аккаунта")},
+	{service.ErrInvalidRefreshToken, apperrors.NewAPIError(http.StatusUnauthorized, "invalid_refresh_token", "Сессия недействительна, войдите снова")},
+	{service.ErrRefreshTokenExpired, apperrors.NewAPIError(http.StatusUnauthorized, "refresh_token_expired", "Сессия истекла, войдите снова")},
+	{service.ErrInvalidUserToken, apperrors.NewAPIError(http.StatusBadRequest, "invalid_token", "Ссылка недействительна или устарела")},
+	{service.ErrTooManyAttempts, apperrors.NewAPIError(http.StatusTooManyRequests, "too_many_attempts", "Слишком много попыток. Попробуйте позже")},
+	{service.ErrInvalidPhone, apperrors.NewAPIError(http.StatusBadRequest, "invalid_phone", "Некорректный номер телефона")},
+	{service.ErrInvalidOTP, apperrors.NewAPIError(http.StatusUnauthorized, "invalid_otp", "Неверный или просроченный код")},
+	{service.ErrOTPAttemptsExceeded, apperrors.NewAPIError(http.StatusTooManyRequests, "otp_attempts_exceeded", "Слишком много неверных попыток. Запросите новый код")},
+	{service.ErrProviderNotSupported, apperrors.NewAPIError(http.StatusNotFound, "provider_not_supported", "Провайдер не поддерживается")},
+	{service.ErrInvalidOAuthState, apperrors.NewAPIError(http.StatusBadRequest, "invalid_oauth_state", "Сессия входа устарела, начните вход заново")},
+	{service.ErrSSOEmailTaken, apperrors.NewAPIError(http.StatusConflict, "sso_email_taken", "Аккаунт уже существует, войдите и привяжите провайдера")},
+	{service.ErrIdentityLinked, apperrors.NewAPIError(http.StatusConflict, "identity_linked", "Аккаунт провайдера уже привязан")},
+	{service.ErrIdentityNotFound, apperrors.NewAPIError(http.StatusNotFound, "identity_not_found", "Привязка не найдена")},
+	{service.ErrLastLoginMethod, apperrors.NewAPIError(http.StatusConflict, "last_login_method", "Нельзя отвязать последний способ входа. Сначала установите пароль или привяжите другой аккаунт")},
+	{service.ErrInvalidTelegramAuth, apperrors.NewAPIError(http.StatusUnauthorized, "invalid_telegram_auth", "Не удалось подтвердить данные Telegram")},
+	{service.ErrInvalidAPIKey, apperrors.NewAPIError(http.StatusUnauthorized, "invalid_api_key", "Недействительный API-ключ")},
+	{service.ErrAPIKeyNotFound, apperrors.NewAPIError(http.StatusNotFound, "api_key_not_found", "Ключ не найден или отозван")},
+	{service.ErrAPIKeyTarget, apperrors.NewAPIError(http.StatusForbidden, "api_key_forbidden", "Ключ можно выпустить только для себя или сервисного аккаунта")},
+	{service.ErrInvalidExpiry, apperrors.NewAPIError(http.StatusBadRequest, "invalid_expiry", "Срок действия ключа должен быть в будущем")},
+	{service.ErrRoleNotFound, apperrors.NewAPIError(http.StatusNotFound, "role_not_found", "Роль не найдена")},
+	{service.ErrRoleExists, apperrors.NewAPIError(http.StatusConflict, "role_exists", "Роль с таким названием уже существует")},
+	{service.ErrUnknownPermission, apperrors.NewAPIError(http.StatusBadRequest, "unknown_permission", "Указано неизвестное право")},
+	{service.ErrPassengerNotFound, apperrors.NewAPIError(http.StatusNotFound, "passenger_not_found", "Пассажир не найден")},
+	{service.ErrInvalidDocument, apperrors.NewAPIError(http.StatusBadRequest, "invalid_document", "Некорректный номер документа")},
+	{service.ErrInvalidBirthDate, apperrors.NewAPIError(http.StatusBadRequest, "invalid_birth_date", "Некорректная дата рождения")},
+	{service.ErrErasureNotRequested, apperrors.NewAPIError(http.StatusNotFound, "erasure_not_requested", "Запрос на удаление не найден")},
+	{service.ErrBusNotFound, apperrors.NewAPIError(http.StatusNotFound, "bus_not_found", "Автобус не найден")},
+	{service.ErrBusCategoryNotFound, apperrors.NewAPIError(http.StatusNotFound, "bus_category_not_found", "Категория не найдена")},
+	{service.ErrBusCategoryExists, apperrors.NewAPIError(http.StatusConflict, "bus_category_exists", "Категория с таким названием уже существует")},
+	{service.ErrBusStatusNotFound, apperrors.NewAPIError(http.StatusNotFound, "bus_status_not_found", "Статус автобуса не найден")},
+	{service.ErrBusStatusExists, apperrors.NewAPIError(http.StatusConflict, "bus_status_exists", "Статус автобуса с таким названием уже существует")},
+	{service.ErrDriverNotFound, apperrors.NewAPIError(http.StatusNotFound, "driver_not_found", "Водитель не найден")},
+	{service.ErrDriverStatusNotFound, apperrors.NewAPIError(http.StatusNotFound, "driver_status_not_found", "Статус водителя не найден")},
+	{service.ErrStopNotFound, apperrors.NewAPIError(http.StatusNotFound, "stop_not_found", "Остановка не найдена")},
+	{service.ErrTripStopNotFound, apperrors.NewAPIError(http.StatusNotFound, "trip_stop_not_found", "Остановка рейса не найдена")},
+	{service.ErrTripNotFound, apperrors.NewAPIError(http.StatusNotFound, "trip_not_found", "Рейс не найден")},
+	{service.ErrVersionConflict, apperrors.NewAPIError(http.StatusPreconditionFailed, "version_conflict", "Запись изменена другим пользователем, загрузите её заново")},
+	{policy.ErrForbidden, apperrors.ErrForbidden.WithDetail("Недостаточно прав для выполнения операции")},
+}
+
+// mapServiceError переводит ошибку сервиса в ответ API для middleware.Problems
+func mapServiceError(err error) *apperrors.APIError {
+	// документ после merge patch проверяется в сервисе теми же правилами, что и тело запроса
+	var (
+		fieldErrs validator.ValidationErrors
+		typeErr   *json.UnmarshalTypeError
+	)
+	if errors.As(err, &fieldErrs) || errors.As(err, &typeErr) {
+		return helper.BindingError(err)
+	}
+
+	var invalid *service.ValidationError
+	if errors.As(err, &invalid) {
+		return apperrors.ErrValidation.WithDetail(invalid.Error())
+	}
+
+	for _, e := range serviceErrors {
+		if errors.Is(err, e.err) {
+			return e.api
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/handler/middleware"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/service"
+	"corpord-api/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestServiceErrorResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{
+			name:   "version conflict",
+			err:    fmt.Errorf("update trip 1: %w", service.ErrVersionConflict),
+			status: http.StatusPreconditionFailed,
+			code:   "version_conflict",
+		},
+		{
+			name:   "trip not found",
+			err:    fmt.Errorf("update trip 9: %w", service.ErrTripNotFound),
+			status: http.StatusNotFound,
+			code:   "trip_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.Problems(log, false, mapServiceError))
+			r.PUT("/trips/:id", func(c *gin.Context) { helper.Abort(c, tt.err) })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/trips/1", nil))
+
+			var body struct {
+				Code string `json:"code"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q: %v", w.Body.String(), err)
+			}
+			if w.Code != tt.status || body.Code != tt.code {
+				t.Fatalf("response = %d %q, want %d %q", w.Code, body.Code, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+func TestValidationErrorDetailIsTranslated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	invalid := &service.ValidationError{Err: (&model.TripUpdate{StartTime: &start, EndTime: &end}).Validate()}
+
+	r := gin.New()
+	r.Use(middleware.Problems(log, false, mapServiceError))
+	r.PUT("/trips/:id", func(c *gin.Context) { helper.Abort(c, invalid) })
+
+	tests := map[string]string{
+		"ru": "время окончания должно быть позже времени начала",
+		"en": "The end time must be after the start time",
+	}
+	for lang, want := range tests {
+		t.Run(lang, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/trips/1", nil)
+			req.Header.Set("Accept-Language", lang)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var body struct {
+				Detail string `json:"detail"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q: %v", w.Body.String(), err)
+			}
+			if body.Detail != want {
+				t.Fatalf("detail = %q, want %q", body.Detail, want)
+			}
+		})
+	}
+}
@@ -2,12 +2,18 @@ package handler
 
 import (
 	_ "corpord-api/docs"
+	"corpord-api/internal/apperrors"
 	"corpord-api/internal/config"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/handler/middleware"
+	"corpord-api/internal/httpcache"
+	"corpord-api/internal/idempotency"
 	"corpord-api/internal/logger"
+	"corpord-api/internal/ratelimit"
 	"corpord-api/internal/service"
 	"corpord-api/internal/sso"
 	"corpord-api/internal/token"
+	"corpord-api/internal/validation"
 	"corpord-api/model"
 
 	"github.com/gin-gonic/gin"
@@ -16,43 +22,68 @@ import (
 )
 
 type handler struct {
-	user     *UserHandler
-	auth     *AuthHandler
-	bus      *BusHandler
-	bc       *BusCategoryHandler
-	bs       *BusStatusHandler
-	ds       *DriverStatus
-	driver   *Driver
-	trip     *Trip
-	tripStop TripStop
-	stop     Stop
-	sso      *SSOHandler
-	logger   *logger.Logger
-	s        *service.Service
-	r        *gin.Engine
-	cfg      *config.Config
-	t        token.Manager
+	user      *UserHandler
+	auth      *AuthHandler
+	account   *AccountHandler
+	role      *RoleHandler
+	apiKey    *APIKeyHandler
+	pass      *PassengerHandler
+	privacy   *PrivacyHandler
+	bus       *BusHandler
+	bc        *BusCategoryHandler
+	bs        *BusStatusHandler
+	ds        *DriverStatus
+	driver    *Driver
+	trip      *Trip
+	tripStop  TripStop
+	stop      Stop
+	sso       *SSOHandler
+	logger    *logger.Logger
+	s         *service.Service
+	r         *gin.Engine
+	cfg       *config.Config
+	t         token.Manager
+	limiter   ratelimit.Store
+	responses httpcache.Store
+	replays   idempotency.Store
 }
 
+// Теги кеша ответов: по ним админские изменения сбрасывают публичные выдачи
+const (
+	cacheTagBusCategories  = "bus_categories"
+	cacheTagBusStatuses    = "bus_statuses"
+	cacheTagDriverStatuses = "driver_statuses"
+	cacheTagStops          = "stops"
+	cacheTagTrips          = "trips" // рейсы показываются вместе с автобусами, водителями и остановками
+)
+
 // New creates a new handler instance with all dependencies
-func New(logger *logger.Logger, s *service.Service, cfg *config.Config, t token.Manager, sso *sso.Registry) Handler {
+func New(logger *logger.Logger, s *service.Service, cfg *config.Config, t token.Manager, sso *sso.Registry, limiter ratelimit.Store, cache httpcache.Store, replays idempotency.Store) Handler {
 	return &handler{
-		user:     NewUser(logger, s.User),
-		auth:     NewAuthHandler(s.Auth, logger, t),
-		bus:      NewBus(logger, s.Bus),
-		bc:       NewBusCategory(logger, s.BC),
-		bs:       NewBusStatus(logger, s.BS),
-		ds:       NewDriverStatus(logger, s.DS),
-		driver:   NewDriver(logger, s.Driver),
-		trip:     NewTrip(logger, s.Trip),
-		tripStop: NewTripStop(logger, s.TripStop),
-		stop:     NewStop(logger, s.Stop),
-		sso:      NewSSOHandler(logger, s.Auth, sso, t),
-		logger:   logger,
-		s:        s,
-		r:        gin.Default(),
-		cfg:      cfg,
-		t:        t,
+		user:      NewUser(logger, s.User),
+		auth:      NewAuthHandler(s.Auth, s.Account, logger, t),
+		account:   NewAccountHandler(logger, s.Account),
+		role:      NewRole(logger, s.Role),
+		apiKey:    NewAPIKey(logger, s.APIKey),
+		pass:      NewPassenger(logger, s.Passenger),
+		privacy:   NewPrivacy(logger, s.Privacy),
+		bus:       NewBus(logger, s.Bus),
+		bc:        NewBusCategory(logger, s.BC),
+		bs:        NewBusStatus(logger, s.BS),
+		ds:        NewDriverStatus(logger, s.DS),
+		driver:    NewDriver(logger, s.Driver),
+		trip:      NewTrip(logger, s.Trip),
+		tripStop:  NewTripStop(logger, s.TripStop),
+		stop:      NewStop(logger, s.Stop),
+		sso:       NewSSOHandler(logger, s.Auth, sso, t, &cfg.SSO),
+		logger:    logger,
+		s:         s,
+		r:         gin.Default(),
+		cfg:       cfg,
+		t:         t,
+		limiter:   limiter,
+		responses: cache,
+		replays:   replays,
 	}
 }
 
@@ -61,40 +92,47 @@ func (h *handler) InitRoutes() *gin.Engine {
 	h.logger.Info("Initializing routes")
 	h.r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	// Add global middleware
+	validation.Register()
+	h.r.Use(middleware.RequestID())
 	h.r.Use(middleware.RequestLogger(h.logger))
+	h.r.Use(middleware.Problems(h.logger, h.cfg.App.Debug, mapServiceError))
 	h.r.Use(middleware.CORSMiddleware())
+	h.r.NoRoute(func(c *gin.Context) { helper.Abort(c, apperrors.ErrNotFound) })
 	// API v1 routes
 	v1 := h.r.Group("api/v1")
+	v1.Use(h.rateLimit("global"))
 	{
 		// Public routes - no authentication required
-		RegisterAuthRoutes(v1, h.auth)
-		h.sso.RegisterRoutes(v1)
+		authLimited := v1.Group("", h.rateLimit("auth"))
+		RegisterAuthRoutes(authLimited, h.auth)
+		h.account.RegisterRoutes(authLimited)
+		h.sso.RegisterRoutes(authLimited)
 		bus := v1.Group("/bus")
 		{
 			bus.GET("/", h.bus.GetAllBuses)
 			bus.GET("/:id", h.bus.GetBus)
 
-			busCategories := bus.Group("/categories")
+			busCategories := bus.Group("/categories", h.cache("catalog", cacheTagBusCategories))
 			{
 				busCategories.GET("/", h.bc.GetAll)
 				busCategories.GET("/:id", h.bc.GetById)
 			}
-			busStatus := bus.Group("/statuses")
+			busStatus := bus.Group("/statuses", h.cache("catalog", cacheTagBusStatuses))
 			{
 				busStatus.GET("/", h.bs.All)
 				busStatus.GET("/:id", h.bs.ByID)
 			}
 		}
-		s := v1.Group("/stops")
+		s := v1.Group("/stops", h.cache("stops", cacheTagStops))
 		{
 			s.GET("/", h.stop.All)
 			s.GET("/:id", h.stop.ByID)
 		}
 		trip := v1.Group("/trips")
 		{
-			trip.GET("/", h.trip.AllShort)
-			trip.GET("/all", h.trip.All)
-			trip.GET("/:id", h.trip.ByID)
+			trip.GET("/", h.rateLimit("search"), h.cache("trips", cacheTagTrips), h.trip.AllShort)
+			trip.GET("/all", h.rateLimit("search"), h.cache("trips", cacheTagTrips), h.trip.All)
+			trip.GET("/:id", h.cache("trips", cacheTagTrips), h.trip.ByID)
 		}
 		ts := v1.Group("/trip_stops")
 		{
@@ -105,85 +143,148 @@ func (h *handler) InitRoutes() *gin.Engine {
 		{
 			driver.GET("/", h.driver.All)
 			driver.GET("/:id", h.driver.ByID)
-			driverStatus := driver.Group("status")
+			driverStatus := driver.Group("status", h.cache("catalog", cacheTagDriverStatuses))
 			{
 				driverStatus.GET("/", h.ds.All)
 				driverStatus.GET("/:id", h.ds.ById)
 			}
 		}
-		// Protected routes - require valid JWT token
+		// Protected routes - require valid JWT token or X-API-Key
 		authorized := v1.Group("")
-		authorized.Use(middleware.AuthMiddleware(h.logger, h.t), middleware.RefreshMiddleware(h.auth.service))
+		authorized.Use(
+			middleware.AuthMiddleware(h.logger, h.t, h.s.APIKey),
+			h.rateLimit("api"),
+			middleware.AuditImpersonation(h.logger),
+			middleware.RefreshMiddleware(h.auth.service),
+			h.idempotent(),
+		)
 		{
-			// Example of admin-only route
-			admin := authorized.Group("/admin")
-			admin.Use(middleware.RoleMiddleware(h.logger, model.RoleAdmin))
+			// Admin routes - доступ определяется правами роли
+			admin := authorized.Group("/admin", middleware.IncludeDeleted())
 			{
-				// Add admin routes here
-				// admin.GET("/users", h.user.GetAllUsers)
 				users := admin.Group("/users")
 				{
-					users.PUT("/:id", h.user.Update) // Update user
-
+					users.GET("", h.can(model.PermUsersRead), h.user.Search)
+					users.GET("/:id", h.can(model.PermUsersRead), h.user.Get)
+					users.POST("/:id/impersonate", h.can(model.PermUsersImpersonate), h.auth.Impersonate)
+					manage := users.Group("", h.can(model.PermUsersManage))
+					{
+						manage.POST("", h.user.Create)    // Create user
+						manage.PUT("/:id", h.user.Update) // Update user
+						manage.POST("/:id/restore", h.user.Restore)
+						manage.POST("/:id/unlock", h.auth.Unlock)
+						manage.POST("/:id/block", h.auth.Block)
+						manage.POST("/:id/unblock", h.auth.Unblock)
+						manage.PUT("/:id/role", h.can(model.PermRolesManage), h.role.AssignToUser)
+					}
 				}
-				adminBus := admin.Group("/bus")
+				roles := admin.Group("/roles", h.can(model.PermRolesManage))
 				{
+					roles.GET("/", h.role.All)
+					roles.POST("/", h.role.Create)
+					roles.PUT("/:id/permissions", h.role.SetPermissions)
+				}
+				admin.GET("/permissions", h.can(model.PermRolesManage), h.role.Permissions)
+				apiKeys := admin.Group("", h.can(model.PermAPIKeysManage), middleware.ForbidImpersonation(h.logger))
+				{
+					apiKeys.POST("/service-accounts", h.apiKey.CreateServiceAccount)
+					apiKeys.GET("/users/:id/api-keys", h.apiKey.List)
+					apiKeys.POST("/users/:id/api-keys", h.apiKey.Create)
+					apiKeys.POST("/api-keys/:id/rotate", h.apiKey.Rotate)
+					apiKeys.DELETE("/api-keys/:id", h.apiKey.Revoke)
+				}
+				adminBus := admin.Group("/bus", h.can(model.PermBusesWrite), h.invalidate(cacheTagTrips))
+				{
+					adminBus.GET("/", h.bus.GetAllBuses)
+					adminBus.GET("/:id", h.bus.GetBus)
 					adminBus.POST("/", h.bus.CreateBus)
+					adminBus.POST("/bulk", h.bus.BulkCreate)
+					adminBus.PUT("/bulk", h.bus.BulkUpdate)
 					adminBus.PUT("/:id", h.bus.UpdateBus)
+					adminBus.PATCH("/:id", h.bus.PatchBus)
 					adminBus.DELETE("/:id", h.bus.DeleteBus)
-					categories := adminBus.Group("/categories")
+					adminBus.POST("/:id/restore", h.bus.RestoreBus)
+					categories := adminBus.Group("/categories", h.invalidate(cacheTagBusCategories))
 					{
+						categories.GET("/", h.bc.GetAll)
+						categories.GET("/:id", h.bc.GetById)
 						categories.POST("/", h.bc.Create)
 						categories.DELETE("/:id", h.bc.Delete)
 						categories.PUT("/:id", h.bc.Update)
+						categories.POST("/:id/restore", h.bc.Restore)
 					}
-					status := adminBus.Group("/statuses")
+					status := adminBus.Group("/statuses", h.invalidate(cacheTagBusStatuses))
 					{
+						status.GET("/", h.bs.All)
+						status.GET("/:id", h.bs.ByID)
 						status.POST("/", h.bs.Create)
 						status.PUT("/:id", h.bs.Update)
 						status.DELETE("/:id", h.bs.Delete)
+						status.POST("/:id/restore", h.bs.Restore)
 					}
 				}
-				adminDriver := admin.Group("/driver")
+				adminDriver := admin.Group("/driver", h.can(model.PermDriversWrite), h.invalidate(cacheTagTrips))
 				{
+					adminDriver.GET("/", h.driver.All)
+					adminDriver.GET("/:id", h.driver.ByID)
 					adminDriver.POST("/", h.driver.Create)
+					adminDriver.POST("/bulk", h.driver.BulkCreate)
+					adminDriver.PUT("/bulk", h.driver.BulkUpdate)
 					adminDriver.PUT("/:id", h.driver.Update)
 					adminDriver.DELETE("/:id", h.driver.Delete)
-					status := adminDriver.Group("/status")
+					adminDriver.POST("/:id/restore", h.driver.Restore)
+					status := adminDriver.Group("/status", h.invalidate(cacheTagDriverStatuses))
 					{
+						status.GET("/", h.ds.All)
+						status.GET("/:id", h.ds.ById)
 						status.POST("/", h.ds.Create)
 						status.PUT("/:id", h.ds.Update)
 						status.DELETE("/:id", h.ds.Delete)
+						status.POST("/:id/restore", h.ds.Restore)
 					}
 				}
-				adminTrip := admin.Group("/trips")
+				adminTrip := admin.Group("/trips", h.can(model.PermTripsWrite), h.invalidate(cacheTagTrips))
 				{
 					adminTrip.POST("/", h.trip.Create)
 					adminTrip.PUT("/:id", h.trip.Update)
+					adminTrip.PATCH("/:id", h.trip.Patch)
 					adminTrip.DELETE("/:id", h.trip.Delete)
 				}
-				adminTripStop := admin.Group("/trip_stops")
+				adminTripStop := admin.Group("/trip_stops", h.can(model.PermTripsWrite), h.invalidate(cacheTagTrips))
 				{
 					adminTripStop.POST("/", h.tripStop.Create)
+					adminTripStop.POST("/bulk", h.tripStop.BulkCreate)
+					adminTripStop.PUT("/bulk", h.tripStop.BulkUpdate)
 					adminTripStop.PUT("/:id", h.tripStop.Update)
 					adminTripStop.DELETE("/:id", h.tripStop.Delete)
 				}
-				adminStop := admin.Group("/stops")
+				adminStop := admin.Group("/stops", h.can(model.PermStopsWrite), h.invalidate(cacheTagStops, cacheTagTrips))
 				{
+					adminStop.GET("/", h.stop.All)
+					adminStop.GET("/:id", h.stop.ByID)
 					adminStop.POST("/", h.stop.Create)
+					adminStop.POST("/bulk", h.stop.BulkCreate)
+					adminStop.PUT("/bulk", h.stop.BulkUpdate)
 					adminStop.PUT("/:id", h.stop.Update)
+					adminStop.PATCH("/:id", h.stop.Patch)
 					adminStop.DELETE("/:id", h.stop.Delete)
+					adminStop.POST("/:id/restore", h.stop.Restore)
 				}
 			}
 
 			// User management
 			users := authorized.Group("/users")
 			{
-				users.GET("", h.user.All) // Get all users
 				users.GET("/me", h.user.Me)
-				users.GET("/:id", h.user.Get)       // Get user by ID
-				users.POST("", h.user.Create)       // Create user (kept for backward compatibility)
-				users.DELETE("/:id", h.user.Delete) // Delete user
+				users.PATCH("/me", h.user.UpdateMe)
+				users.DELETE("/me", middleware.ForbidImpersonation(h.logger), h.user.DeleteMe)
+				users.PUT("/me/phone", middleware.ForbidImpersonation(h.logger), h.auth.ConfirmPhone)
+				h.pass.RegisterRoutes(users)
+				h.privacy.RegisterRoutes(users)
+				users.GET("/:id", h.ownerOr("id", model.PermUsersRead), h.user.Get)         // Get user by ID
+				users.POST("", h.can(model.PermUsersManage), h.user.Create)                 // Create user (kept for backward compatibility)
+				users.DELETE("/:id", h.ownerOr("id", model.PermUsersManage), h.user.Delete) // Delete user
+				h.sso.RegisterLinkRoutes(users)
 			}
 		}
 	}
@@ -192,3 +293,34 @@ func (h *handler) InitRoutes() *gin.Engine {
 
 	return h.r
 }
+
+// rateLimit возвращает ограничитель частоты для правила name из конфигурации
+func (h *handler) rateLimit(name string) gin.HandlerFunc {
+	return middleware.RateLimit(h.logger, &h.cfg.RateLimit, h.limiter, name)
+}
+
+// cache кеширует ответы публичного маршрута по правилу name, помечая их тегами tags
+func (h *handler) cache(name string, tags ...string) gin.HandlerFunc {
+	return middleware.Cache(h.logger, &h.cfg.HTTPCache, h.responses, name, tags...)
+}
+
+// invalidate сбрасывает кеш с тегами tags после успешных изменений в группе маршрутов
+func (h *handler) invalidate(tags ...string) gin.HandlerFunc {
+	return middleware.Invalidate(h.logger, &h.cfg.HTTPCache, h.responses, tags...)
+}
+
+// idempotent сохраняет ответы на POST с Idempotency-Key, чтобы повтор не выполнился дважды
+func (h *handler) idempotent() gin.HandlerFunc {
+	return middleware.Idempotency(h.logger, &h.cfg.Idempotency, h.replays)
+}
+
+// can требует у пользователя одно из прав codes
+func (h *handler) can(codes ...string) gin.HandlerFunc {
+	return middleware.RequirePermission(h.logger, h.s.Role, codes...)
+}
+
+// ownerOr пропускает пользователя к его собственному ресурсу (ID в параметре пути param),
+// к чужим — только с одним из прав codes
+func (h *handler) ownerOr(param string, codes ...string) gin.HandlerFunc {
+	return middleware.RequireOwnerOrPermission(h.logger, h.s.Role, param, codes...)
+}
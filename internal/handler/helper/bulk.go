@@ -0,0 +1,110 @@
+package helper
+
+import (
+	"bytes"
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/i18n"
+	"corpord-api/internal/validation"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// MaxBulkItems ограничивает число элементов пакетного запроса
+	MaxBulkItems = 500
+	maxBulkSize  = 4 << 20
+)
+
+// BindBulk читает тело пакетного запроса — JSON-массив — и проверяет каждый элемент
+// по тегам binding. Ошибки всех элементов собираются в один ответ 422: поля адресуются
+// индексом ([3].name), индексы некорректных элементов перечисляются в failed
+func BindBulk[T any](c *gin.Context) ([]T, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBulkSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			Abort(c, apperrors.ErrBadRequest.WithDetail("Тело запроса слишком большое"))
+			return nil, false
+		}
+		Abort(c, err)
+		return nil, false
+	}
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] != '[' {
+		Abort(c, apperrors.ErrBadRequest.WithDetail("Тело запроса должно быть массивом"))
+		return nil, false
+	}
+
+	var items []T
+	if err := json.Unmarshal(body, &items); err != nil {
+		if len(body) == 0 {
+			err = io.EOF
+		}
+		Abort(c, bulkDecodeError(err))
+		return nil, false
+	}
+	if len(items) == 0 || len(items) > MaxBulkItems {
+		Abort(c, apperrors.ErrBadRequest.WithDetail("Допустимое количество элементов: 1-"+strconv.Itoa(MaxBulkItems)))
+		return nil, false
+	}
+
+	var (
+		fields []apperrors.FieldError
+		failed []int
+	)
+	for i := range items {
+		prefix := "[" + strconv.Itoa(i) + "]"
+		// null вместо объекта
+		if v := reflect.ValueOf(items[i]); v.Kind() == reflect.Ptr && v.IsNil() {
+			fields = append(fields, apperrors.FieldError{
+				Field:   prefix,
+				Code:    "required",
+				Message: i18n.Field(i18n.Default, "required", ""),
+			})
+			failed = append(failed, i)
+			continue
+		}
+
+		err := validation.Struct(&items[i])
+		if err == nil {
+			continue
+		}
+		for _, f := range BindingError(err).Fields {
+			f.Field = prefix + "." + f.Field
+			fields = append(fields, f)
+		}
+		failed = append(failed, i)
+	}
+	if len(failed) > 0 {
+		Abort(c, apperrors.Validation(fields...).WithFailed(failed))
+		return nil, false
+	}
+	return items, true
+}
+
+// bulkDecodeError ошибка разбора массива. encoding/json называет поле элемента 3.name,
+// клиенту оно показывается так же, как ошибки валидации: [3].name
+func bulkDecodeError(err error) *apperrors.APIError {
+	apiErr := BindingError(err)
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) || len(apiErr.Fields) != 1 {
+		return apiErr
+	}
+	idx, rest, _ := strings.Cut(typeErr.Field, ".")
+	i, convErr := strconv.Atoi(idx)
+	if convErr != nil {
+		return apiErr
+	}
+	field := apiErr.Fields[0]
+	field.Field = "[" + idx + "]"
+	if rest != "" {
+		field.Field += "." + rest
+	}
+	return apperrors.Validation(field).WithFailed([]int{i})
+}
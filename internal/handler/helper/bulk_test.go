@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"corpord-api/internal/apperrors"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBindBulk(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		fields []string
+		failed []int
+	}{
+		{name: "valid items", body: `[{"document_number":"1"},{"document_number":"2"}]`},
+		{name: "not an array", body: `{"document_number":"1"}`, status: http.StatusBadRequest},
+		{name: "empty array", body: `[]`, status: http.StatusBadRequest},
+		{name: "empty body", body: ``, status: http.StatusBadRequest},
+		{name: "broken json", body: `[{"document_number":`, status: http.StatusBadRequest},
+		{
+			name:   "every invalid item is reported",
+			body:   `[{"document_number":"1"},{},null]`,
+			status: http.StatusUnprocessableEntity,
+			fields: []string{"[1].document_number", "[2]"},
+			failed: []int{1, 2},
+		},
+		{
+			name:   "wrong type names the item",
+			body:   `[{"document_number":"1"},{"document_number":5}]`,
+			status: http.StatusUnprocessableEntity,
+			fields: []string{"[1].document_number"},
+			failed: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(tt.body))
+
+			items, ok := BindBulk[*testPassenger](c)
+			if tt.status == 0 {
+				if !ok || len(items) != 2 {
+					t.Fatalf("BindBulk = %d items, %v (errors: %v)", len(items), ok, c.Errors)
+				}
+				return
+			}
+			if ok {
+				t.Fatal("BindBulk accepted invalid body")
+			}
+
+			var apiErr *apperrors.APIError
+			if !errors.As(c.Errors.Last().Err, &apiErr) || apiErr.Status != tt.status {
+				t.Fatalf("error = %v, want status %d", c.Errors.Last().Err, tt.status)
+			}
+			var fields []string
+			for _, f := range apiErr.Fields {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) || !reflect.DeepEqual(apiErr.Failed, tt.failed) {
+				t.Fatalf("fields = %v, failed = %v, want %v, %v", fields, apiErr.Failed, tt.fields, tt.failed)
+			}
+		})
+	}
+}
@@ -1,24 +1,55 @@
 package helper
 
 import (
-	"github.com/gin-gonic/gin"
+	"net/http"
 	"os"
 	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
+// OAuthStateCookie привязывает начатый SSO-вход к браузеру, который его начал
+const OAuthStateCookie = "oauth_state"
+
 func SetRefreshCookie(c *gin.Context, refreshToken string, ttl time.Duration) {
-	secure := true
-	env := os.Getenv("APP_ENV")
-	if env != "production" {
-		secure = false
-	}
 	c.SetCookie(
 		"refresh_token",
 		refreshToken,
 		int(ttl.Seconds()),
 		"/",
-		"localhost", // потом меняем на домен сервера
-		secure,      // Secure=true на продакшене
-		true,        // HttpOnly
+		"localhost",     // потом меняем на домен сервера
+		secureCookies(), // Secure=true на продакшене
+		true,            // HttpOnly
 	)
 }
+
+// SetOAuthStateCookie кладёт значение привязки SSO-входа. SameSite=Lax: cookie уходит
+// при возврате от провайдера (переход верхнего уровня), но не в запросах с чужих сайтов
+func SetOAuthStateCookie(c *gin.Context, value string, ttl time.Duration) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     OAuthStateCookie,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   secureCookies(),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearOAuthStateCookie удаляет cookie привязки после callback
+func ClearOAuthStateCookie(c *gin.Context) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     OAuthStateCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   secureCookies(),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// secureCookies включает Secure только при APP_ENV=production: локально API работает по http
+func secureCookies() bool {
+	return os.Getenv("APP_ENV") == "production"
+}
@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Lang язык ответа: язык из профиля вошедшего пользователя, иначе из Accept-Language
+func Lang(c *gin.Context) i18n.Lang {
+	if claims, ok := CurrentClaims(c); ok {
+		if lang, ok := i18n.Parse(claims.Language); ok {
+			return lang
+		}
+	}
+	return i18n.Negotiate(c.GetHeader("Accept-Language"))
+}
+
+// SetLangHeaders помечает ответ языком, на котором он отдан
+func SetLangHeaders(c *gin.Context, lang i18n.Lang) {
+	c.Header("Content-Language", string(lang))
+	c.Writer.Header().Add("Vary", "Accept-Language")
+}
+
+// Success отвечает сообщением об успехе на языке клиента
+func Success(c *gin.Context, status int, message string) {
+	lang := Lang(c)
+	SetLangHeaders(c, lang)
+	c.JSON(status, apperrors.SuccessResponse{Message: i18n.Text(lang, message)})
+}
@@ -0,0 +1,28 @@
+package helper
+
+import (
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PagingParams читает limit, page, cursor и sort из запроса. При ошибке прерывает запрос с 400 и возвращает false
+func PagingParams(c *gin.Context) (paging.Params, bool) {
+	p, err := paging.FromQuery(c.Request.URL.Query())
+	if err != nil {
+		Abort(c, err)
+		return p, false
+	}
+	return p, true
+}
+
+// FilterParams читает параметры filter[...] из запроса. При ошибке прерывает запрос с 400 и возвращает false
+func FilterParams(c *gin.Context) (filter.Filter, bool) {
+	f, err := filter.FromQuery(c.Request.URL.Query())
+	if err != nil {
+		Abort(c, err)
+		return nil, false
+	}
+	return f, true
+}
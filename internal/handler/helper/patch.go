@@ -0,0 +1,73 @@
+package helper
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/pkg/mergepatch"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPatchSize ограничивает тело PATCH-запроса
+const maxPatchSize = 1 << 20
+
+// ETag строгий ETag версии ресурса. Версия — время последнего изменения записи
+func ETag(version time.Time) string {
+	return `"` + strconv.FormatInt(version.UnixMicro(), 36) + `"`
+}
+
+// SetETag отдаёт версию ресурса в заголовке ETag
+func SetETag(c *gin.Context, version time.Time) {
+	c.Header("ETag", ETag(version))
+}
+
+// IfMatch возвращает проверку версии по заголовку If-Match или nil, если заголовка нет.
+// Слабые ETag (W/...) при If-Match не совпадают никогда (RFC 9110, 13.1.1)
+func IfMatch(c *gin.Context) func(version time.Time) bool {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return nil
+	}
+	return func(version time.Time) bool {
+		etag := ETag(version)
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MergePatchBody читает тело PATCH-запроса в формате JSON Merge Patch.
+// Принимается application/merge-patch+json и, для простых клиентов, application/json
+func MergePatchBody(c *gin.Context) ([]byte, bool) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != mergepatch.ContentType && mediaType != gin.MIMEJSON {
+		Abort(c, apperrors.ErrUnsupportedMediaType.WithDetail("Используйте application/merge-patch+json"))
+		return nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			Abort(c, apperrors.ErrBadRequest.WithDetail("Тело запроса слишком большое"))
+			return nil, false
+		}
+		Abort(c, err)
+		return nil, false
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		Abort(c, apperrors.ErrBadRequest.WithDetail("Пустое тело запроса"))
+		return nil, false
+	}
+	return body, true
+}
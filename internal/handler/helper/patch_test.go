@@ -0,0 +1,105 @@
+package helper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	version := time.Date(2025, 3, 1, 10, 0, 0, 123456000, time.UTC)
+	etag := ETag(version)
+
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "same version", header: etag, want: true},
+		{name: "any version", header: "*", want: true},
+		{name: "one of list", header: `"other", ` + etag, want: true},
+		{name: "surrounding spaces", header: "  " + etag + "  ", want: true},
+		{name: "stale version", header: ETag(version.Add(-time.Microsecond))},
+		{name: "weak tag never matches", header: "W/" + etag},
+		{name: "unquoted tag", header: strings.Trim(etag, `"`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+			c.Request.Header.Set("If-Match", tt.header)
+
+			match := IfMatch(c)
+			if match == nil {
+				t.Fatal("IfMatch = nil with header set")
+			}
+			if got := match(version); got != tt.want {
+				t.Fatalf("match(%s) with If-Match %q = %v, want %v", etag, tt.header, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("no header means no precondition", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+		if IfMatch(c) != nil {
+			t.Fatal("IfMatch without header must be nil")
+		}
+	})
+}
+
+func TestSetETagRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	version := time.Now()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	SetETag(c, version)
+
+	c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+	c.Request.Header.Set("If-Match", w.Header().Get("ETag"))
+	if !IfMatch(c)(version) {
+		t.Fatalf("ETag %q from SetETag does not match its own version", w.Header().Get("ETag"))
+	}
+}
+
+func TestMergePatchBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		ok          bool
+	}{
+		{name: "merge patch", contentType: "application/merge-patch+json", body: `{"name":"x"}`, ok: true},
+		{name: "plain json", contentType: "application/json; charset=utf-8", body: `{"name":"x"}`, ok: true},
+		{name: "json patch is not supported", contentType: "application/json-patch+json", body: `[]`},
+		{name: "empty body", contentType: "application/merge-patch+json", body: "  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			body, ok := MergePatchBody(c)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v (errors: %v)", ok, tt.ok, c.Errors)
+			}
+			if ok && string(body) != tt.body {
+				t.Fatalf("body = %q, want %q", body, tt.body)
+			}
+			if !ok && len(c.Errors) == 0 {
+				t.Fatal("rejected body must be reported through c.Error")
+			}
+		})
+	}
+}
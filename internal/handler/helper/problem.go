@@ -0,0 +1,96 @@
+package helper
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/i18n"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// Abort прерывает запрос с ошибкой. Ответ application/problem+json формирует middleware.Problems
+func Abort(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// BindingError переводит ошибку ShouldBind* в ошибку API: неразборчивое тело — 400,
+// нарушенные правила полей — 422 со списком полей
+func BindingError(err error) *apperrors.APIError {
+	var (
+		fieldErrs validator.ValidationErrors
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &fieldErrs):
+		fields := make([]apperrors.FieldError, len(fieldErrs))
+		for i, fe := range fieldErrs {
+			param := fieldParam(fe)
+			fields[i] = apperrors.FieldError{
+				Field:   fieldPath(fe),
+				Code:    fe.Tag(),
+				Message: i18n.Field(i18n.Default, fe.Tag(), param),
+				Param:   param,
+			}
+		}
+		return apperrors.Validation(fields...)
+	case errors.As(err, &typeErr):
+		kind := typeErr.Type.Kind().String()
+		return apperrors.Validation(apperrors.FieldError{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: i18n.Field(i18n.Default, "type", kind),
+			Param:   kind,
+		})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return apperrors.ErrBadRequest.WithDetail("Тело запроса не является корректным JSON")
+	case errors.Is(err, io.EOF):
+		return apperrors.ErrBadRequest.WithDetail("Пустое тело запроса")
+	}
+	return apperrors.ErrBadRequest.WithDetail("Некорректные параметры запроса")
+}
+
+// fieldPath путь поля без имени корневой структуры: passengers[0].document_number
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	// в имени обобщённого типа есть точки: BulkUpdate[corpord-api/model.Stop].data.name
+	if open := strings.IndexByte(ns, '['); open >= 0 && open < strings.IndexByte(ns, '.') {
+		if end := strings.Index(ns, "]."); end >= 0 {
+			return ns[end+2:]
+		}
+	}
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return fe.Field()
+}
+
+// fieldParam параметр правила для сообщения. Правила сравнения полей получают имя поля Go,
+// клиенту оно показывается так же, как в json: StartTime -> start_time
+func fieldParam(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "after", "gtfield", "gtefield", "ltfield", "ltefield", "eqfield", "nefield":
+		return snakeCase(fe.Param())
+	}
+	return fe.Param()
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
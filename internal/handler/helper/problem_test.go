@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"corpord-api/internal/validation"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestMain(m *testing.M) {
+	validation.Register()
+	os.Exit(m.Run())
+}
+
+type testPassenger struct {
+	Document string `json:"document_number" binding:"required"`
+}
+
+type testBooking struct {
+	TripID     int             `json:"trip_id" binding:"required"`
+	Passengers []testPassenger `json:"passengers" binding:"dive"`
+}
+
+type testEnvelope[T any] struct {
+	Data T `json:"data"`
+}
+
+func TestFieldPath(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{name: "top level field", v: &testBooking{Passengers: []testPassenger{{Document: "1"}}}, want: "trip_id"},
+		{name: "slice element", v: &testBooking{TripID: 1, Passengers: []testPassenger{{Document: "1"}, {}}}, want: "passengers[1].document_number"},
+		{name: "generic root type", v: &testEnvelope[testPassenger]{}, want: "data.document_number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fieldErrs validator.ValidationErrors
+			if err := validation.Struct(tt.v); !errors.As(err, &fieldErrs) || len(fieldErrs) != 1 {
+				t.Fatalf("validation error = %v, want one field error", err)
+			}
+			if got := fieldPath(fieldErrs[0]); got != tt.want {
+				t.Fatalf("fieldPath(%s) = %q, want %q", fieldErrs[0].Namespace(), got, tt.want)
+			}
+		})
+	}
+}
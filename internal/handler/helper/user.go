@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"corpord-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClaimsCtx ключ контекста gin, под которым AuthMiddleware хранит *model.Claims.
+// Объявлен здесь, а не в middleware, потому что middleware импортирует helper
+const ClaimsCtx = "claims"
+
+// CurrentUserID возвращает ID вошедшего пользователя: из claims (AuthMiddleware)
+// или из userID, который кладёт RefreshMiddleware после обновления токена
+func CurrentUserID(c *gin.Context) (int, bool) {
+	if raw, ok := c.Get(ClaimsCtx); ok {
+		if claims, ok := raw.(*model.Claims); ok && claims.UserID != 0 {
+			return claims.UserID, true
+		}
+	}
+	id := c.GetInt("userID")
+	return id, id != 0
+}
+
+// CurrentClaims возвращает claims, положенные AuthMiddleware
+func CurrentClaims(c *gin.Context) (*model.Claims, bool) {
+	raw, ok := c.Get(ClaimsCtx)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := raw.(*model.Claims)
+	return claims, ok && claims.UserID != 0
+}
@@ -1,20 +1,44 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/token"
+	"corpord-api/model"
 )
 
 const (
 	AuthorizationHeader = "Authorization"
-	ClaimsCtx           = "claims"
+	ClaimsCtx           = helper.ClaimsCtx
 )
 
-// AuthMiddleware validates JWT and injects claims into context
-func AuthMiddleware(log *logger.Logger, tm token.Manager) gin.HandlerFunc {
+// APIKeyAuthenticator проверяет ключ из заголовка X-API-Key
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*model.Claims, error)
+}
+
+// AuthMiddleware validates JWT and injects claims into context.
+// Запросы с заголовком X-API-Key аутентифицируются ключом, claims при этом
+// такие же, как у владельца ключа, поэтому проверки ролей и прав работают без изменений
+func AuthMiddleware(log *logger.Logger, tm token.Manager, keys APIKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if rawKey := c.GetHeader(APIKeyHeader); rawKey != "" && keys != nil {
+			claims, err := keys.Authenticate(c.Request.Context(), rawKey)
+			if err != nil {
+				log.Warnf("Invalid api key: %v", err)
+				abortUnauthorized(c)
+				return
+			}
+
+			c.Set(ClaimsCtx, claims)
+			c.Next()
+			return
+		}
+
 		tokenString, err := extractToken(c)
 		if err != nil {
 			log.Warnf("Auth error: %v", err)
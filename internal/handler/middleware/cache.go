@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/httpcache"
+	"corpord-api/internal/logger"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheStatusHeader показывает, взят ли ответ из кеша сервера: HIT или MISS
+const CacheStatusHeader = "X-Cache"
+
+// Cache кеширует ответы публичного GET-маршрута по правилу name из конфигурации.
+// Ответ получает строгий ETag: версию, выставленную обработчиком, или хеш тела.
+// По If-None-Match с совпавшим ETag отдаётся 304 без тела. Успешные ответы
+// хранятся TTL правила с тегами tags, пока их не сбросит Invalidate; ответ,
+// собранный во время сброса, не сохраняется. Ошибки хранилища не блокируют запросы
+func Cache(log *logger.Logger, cfg *config.HTTPCache, store httpcache.Store, name string, tags ...string) gin.HandlerFunc {
+	rule, ok := cfg.Rules[name]
+	if !cfg.Enabled || !ok {
+		return func(c *gin.Context) { c.Next() }
+	}
+	cacheControl := cacheControl(rule.MaxAge)
+
+	return func(c *gin.Context) {
+		key := name + ":" + c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+
+		if rule.TTL > 0 {
+			entry, err := store.Get(c.Request.Context(), key)
+			if err != nil {
+				log.Warnf("http cache read failed for %s: %v", key, err)
+			}
+			if entry != nil {
+				c.Header(CacheStatusHeader, "HIT")
+				writeCached(c, entry, cacheControl)
+				c.Abort()
+				return
+			}
+		}
+
+		// поколение читается до обработчика: сброс после этого момента отменит запись
+		save := rule.TTL > 0
+		var gen string
+		if save {
+			var err error
+			if gen, err = store.Generation(c.Request.Context(), tags...); err != nil {
+				log.Warnf("http cache generation read failed for %s: %v", key, err)
+				save = false
+			}
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if len(c.Errors) > 0 || !w.Written() || w.Status() != http.StatusOK {
+			w.flush()
+			return
+		}
+
+		entry := &httpcache.Entry{
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			ETag:        c.Writer.Header().Get("ETag"),
+			Body:        w.body.Bytes(),
+		}
+		if entry.ETag == "" {
+			entry.ETag = bodyETag(entry.Body)
+		}
+		if save {
+			if err := store.Set(c.Request.Context(), key, entry, rule.TTL, gen, tags...); err != nil {
+				log.Warnf("http cache write failed for %s: %v", key, err)
+			}
+		}
+		if rule.TTL > 0 {
+			c.Header(CacheStatusHeader, "MISS")
+		}
+		writeCached(c, entry, cacheControl)
+	}
+}
+
+// Invalidate сбрасывает кеш с тегами tags после успешного изменяющего запроса.
+// GET и HEAD пропускаются, поэтому middleware можно вешать на всю группу маршрутов
+func Invalidate(log *logger.Logger, cfg *config.HTTPCache, store httpcache.Store, tags ...string) gin.HandlerFunc {
+	if !cfg.Enabled || len(tags) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			return
+		}
+		if len(c.Errors) > 0 || c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		// запрос клиента мог уже завершиться, а сбросить кеш нужно в любом случае
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+		defer cancel()
+		if err := store.Invalidate(ctx, tags...); err != nil {
+			log.Errorf("http cache invalidation failed for %v: %v", tags, err)
+		}
+	}
+}
+
+// writeCached отдаёт ответ из кеша или 304, если версия у клиента совпадает
+func writeCached(c *gin.Context, e *httpcache.Entry, cacheControl string) {
+	c.Header("ETag", e.ETag)
+	c.Header("Cache-Control", cacheControl)
+	if etagMatches(c.GetHeader("If-None-Match"), e.ETag) {
+		c.Writer.Header().Del("Content-Type")
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(http.StatusOK, e.ContentType, e.Body)
+}
+
+// cacheControl политика для клиентов: без max-age ответ хранится, но перепроверяется по ETag
+func cacheControl(maxAge time.Duration) string {
+	if maxAge <= 0 {
+		return "public, no-cache"
+	}
+	return "public, max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+}
+
+// bodyETag строгий ETag по содержимому ответа
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches сравнивает ETag из If-None-Match слабым сравнением (RFC 9110, 13.1.2)
+func etagMatches(header, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter придерживает ответ обработчика, чтобы посчитать ETag до отправки
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+// WriteHeader как и в gin, статус можно менять, пока ответ не начат
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// flush отправляет придержанный ответ как есть
+func (w *bufferedWriter) flush() {
+	if !w.written {
+		// ответ без тела (c.Status): статус отправит gin после обработчиков
+		if w.status != 0 {
+			w.ResponseWriter.WriteHeader(w.status)
+		}
+		return
+	}
+	w.ResponseWriter.WriteHeader(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
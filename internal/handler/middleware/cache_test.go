@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/httpcache"
+	"corpord-api/internal/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func newCacheRouter(store httpcache.Store, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	cfg := &config.HTTPCache{
+		Enabled: true,
+		Rules:   map[string]config.HTTPCacheRule{"trips": {TTL: time.Minute}},
+	}
+
+	r := gin.New()
+	r.GET("/trips/:id", Cache(log, cfg, store, "trips", "trips"), handler)
+	return r
+}
+
+func TestCacheRevalidation(t *testing.T) {
+	calls := 0
+	r := newCacheRouter(httpcache.NewMemoryStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trips/1", nil))
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || w.Header().Get(CacheStatusHeader) != "MISS" || etag == "" {
+		t.Fatalf("first request: %d %s etag=%q", w.Code, w.Header().Get(CacheStatusHeader), etag)
+	}
+	if etag != bodyETag(w.Body.Bytes()) {
+		t.Fatalf("ETag = %s, want hash of body", etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/trips/1", nil)
+	req.Header.Set("If-None-Match", "W/"+etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get(CacheStatusHeader) != "HIT" {
+		t.Fatalf("revalidation: %d %s body=%q", w.Code, w.Header().Get(CacheStatusHeader), w.Body.String())
+	}
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestCacheSkipsFillRacingInvalidation(t *testing.T) {
+	store := httpcache.NewMemoryStore()
+	calls := 0
+	r := newCacheRouter(store, func(c *gin.Context) {
+		calls++
+		// изменение данных и сброс кеша пришлись на время, пока собирался ответ
+		if calls == 1 {
+			_ = store.Invalidate(context.Background(), "trips")
+		}
+		c.JSON(http.StatusOK, gin.H{"call": calls})
+	})
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trips/1", nil))
+		if w.Header().Get(CacheStatusHeader) != "MISS" {
+			t.Fatalf("request %d: X-Cache = %q, want MISS", i+1, w.Header().Get(CacheStatusHeader))
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("handler called %d times, want 2", calls)
+	}
+}
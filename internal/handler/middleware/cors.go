@@ -35,7 +35,8 @@ func CORSMiddleware() gin.HandlerFunc {
 		}
 
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Requested-With, If-Match, If-None-Match, "+IdempotencyKeyHeader+", "+RequestIDHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", RequestIDHeader+", Retry-After, ETag, "+CacheStatusHeader+", "+IdempotentReplayedHeader)
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 
 		if c.Request.Method == http.MethodOptions {
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/token"
 	"github.com/gin-gonic/gin"
 )
@@ -28,8 +29,5 @@ func extractToken(c *gin.Context) (string, error) {
 }
 
 func abortUnauthorized(c *gin.Context) {
-	c.AbortWithStatusJSON(
-		apperrors.ErrUnauthorized.Status,
-		apperrors.ErrorResponse{Error: "Необходима авторизация"},
-	)
+	helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Необходима авторизация"))
 }
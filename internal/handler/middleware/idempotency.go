@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/config"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/idempotency"
+	"corpord-api/internal/logger"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// replayedHeaders заголовки ответа, которые повтор получает вместе с телом
+var replayedHeaders = []string{"Location", "Content-Location", "ETag", "Last-Modified"}
+
+const (
+	maxIdempotencyKey  = 255
+	maxIdempotentBody  = 1 << 20
+	idempotencyTimeout = 5 * time.Second
+)
+
+// Idempotency выполняет POST-запрос с заголовком Idempotency-Key не больше одного раза.
+// Повтор с тем же ключом, адресом и телом получает сохранённый ответ с заголовками
+// из replayedHeaders, с другим адресом или телом — 409.
+// Пока первый запрос выполняется, повторы получают 409 с Retry-After.
+// Ключи разделяются по пользователю, для анонимных запросов — по IP.
+// Ошибки, отданные через c.Error, и ответы 5xx не сохраняются: ключ освобождается
+// и запрос можно повторить. Ошибки хранилища не блокируют запросы
+func Idempotency(log *logger.Logger, cfg *config.Idempotency, store idempotency.Store) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		header := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || header == "" {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(header) {
+			helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный Idempotency-Key"))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBody))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Тело запроса слишком большое"))
+				return
+			}
+			helper.Abort(c, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		key := subjectKey(c, RateLimitByUser) + ":" + header
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path+"?"+c.Request.URL.RawQuery, body)
+		// метка владельца: освободить или закрыть ключ может только занявший его запрос
+		token := newRequestID()
+
+		rec, err := store.Begin(c.Request.Context(), key, fingerprint, token, cfg.LockTTL)
+		if err != nil {
+			log.Warnf("idempotency check failed for %s: %v", key, err)
+			c.Next()
+			return
+		}
+		if rec != nil {
+			replay(c, rec, fingerprint)
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		// запрос клиента мог уже завершиться, а запись нужно закрыть в любом случае
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), idempotencyTimeout)
+		defer cancel()
+
+		if len(c.Errors) > 0 || !w.Written() || w.Status() >= http.StatusInternalServerError {
+			if err := store.Release(ctx, key, token); err != nil {
+				log.Errorf("idempotency release failed for %s: %v", key, err)
+			}
+			w.flush()
+			return
+		}
+
+		err = store.Complete(ctx, key, token, &idempotency.Record{
+			Fingerprint: fingerprint,
+			Done:        true,
+			Status:      w.Status(),
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Header:      responseHeaders(c.Writer.Header()),
+			Body:        w.body.Bytes(),
+		}, cfg.TTL)
+		if err != nil {
+			log.Errorf("idempotency save failed for %s: %v", key, err)
+		}
+		w.flush()
+	}
+}
+
+// replay отвечает на повтор запроса по уже существующей записи
+func replay(c *gin.Context, rec *idempotency.Record, fingerprint string) {
+	switch {
+	case rec.Fingerprint != fingerprint:
+		helper.Abort(c, apperrors.ErrConflict.WithDetail("Idempotency-Key уже использован для другого запроса"))
+	case !rec.Done:
+		c.Header("Retry-After", "1")
+		helper.Abort(c, apperrors.ErrConflict.WithDetail("Запрос с этим Idempotency-Key ещё выполняется"))
+	default:
+		for name, value := range rec.Header {
+			c.Header(name, value)
+		}
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(rec.Status, rec.ContentType, rec.Body)
+		c.Abort()
+	}
+}
+
+// responseHeaders выбирает из ответа заголовки для повтора
+func responseHeaders(h http.Header) map[string]string {
+	var result map[string]string
+	for _, name := range replayedHeaders {
+		if v := h.Get(name); v != "" {
+			if result == nil {
+				result = make(map[string]string, len(replayedHeaders))
+			}
+			result[name] = v
+		}
+	}
+	return result
+}
+
+// requestFingerprint отличает повтор запроса от другого запроса с тем же ключом.
+// target — путь вместе со строкой запроса
+func requestFingerprint(method, target string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + target + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// validIdempotencyKey ключ — непустая строка из видимых ASCII-символов, обычно UUID
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKey {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < '!' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"corpord-api/internal/config"
+	"corpord-api/internal/idempotency"
+	"corpord-api/internal/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func newIdempotencyRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	cfg := &config.Idempotency{Enabled: true, TTL: time.Hour, LockTTL: time.Minute}
+
+	r := gin.New()
+	r.Use(Problems(log, false), Idempotency(log, cfg, idempotency.NewMemoryStore()))
+	r.POST("/orders", handler)
+	return r
+}
+
+func postOrder(r *gin.Engine, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, "b6f4a1c2-key")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysResponseHeaders(t *testing.T) {
+	calls := 0
+	r := newIdempotencyRouter(func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/api/v1/orders/7")
+		c.Header("ETag", `"v1"`)
+		c.Header("Set-Cookie", "session=secret")
+		c.JSON(http.StatusCreated, gin.H{"id": 7})
+	})
+
+	first := postOrder(r, "/orders", `{"trip_id":1}`)
+	replay := postOrder(r, "/orders", `{"trip_id":1}`)
+
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %q, want %d %q", replay.Code, replay.Body.String(), first.Code, first.Body.String())
+	}
+	if replay.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatal("replay is not marked")
+	}
+	for _, name := range []string{"Location", "ETag"} {
+		if got, want := replay.Header().Get(name), first.Header().Get(name); got != want {
+			t.Fatalf("replayed %s = %q, want %q", name, got, want)
+		}
+	}
+	if replay.Header().Get("Set-Cookie") != "" {
+		t.Fatal("Set-Cookie must not be replayed")
+	}
+}
+
+func TestIdempotencyFingerprint(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		want   int
+	}{
+		{name: "same request", target: "/orders?notify=email", body: `{"trip_id":1}`, want: http.StatusCreated},
+		{name: "other query string", target: "/orders?notify=sms", body: `{"trip_id":1}`, want: http.StatusConflict},
+		{name: "other body", target: "/orders?notify=email", body: `{"trip_id":2}`, want: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newIdempotencyRouter(func(c *gin.Context) {
+				c.JSON(http.StatusCreated, gin.H{"id": 7})
+			})
+			postOrder(r, "/orders?notify=email", `{"trip_id":1}`)
+
+			if w := postOrder(r, tt.target, tt.body); w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdempotencyReleasesKeyOnServerError(t *testing.T) {
+	calls := 0
+	r := newIdempotencyRouter(func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "unavailable"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 7})
+	})
+
+	if w := postOrder(r, "/orders", `{}`); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("first status = %d", w.Code)
+	}
+	if w := postOrder(r, "/orders", `{}`); w.Code != http.StatusCreated || w.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatalf("retry after 5xx = %d, replayed %q", w.Code, w.Header().Get(IdempotentReplayedHeader))
+	}
+	if calls != 2 {
+		t.Fatalf("handler called %d times, want 2", calls)
+	}
+}
+
+func TestIdempotencyKeepsStatusWithoutBody(t *testing.T) {
+	r := newIdempotencyRouter(func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+
+	if w := postOrder(r, "/orders", `{}`); w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+}
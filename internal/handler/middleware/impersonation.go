@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForbidImpersonation закрывает маршрут для токена входа от имени пользователя.
+// Ставится на действия с учётными данными: привязав к чужому аккаунту свой телефон,
+// SSO или ключ, администратор сохранил бы доступ после истечения токена
+func ForbidImpersonation(log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := claimsFromContext(c, log)
+		if !ok {
+			return
+		}
+
+		if claims.Impersonated() {
+			log.Warnf("Admin %d denied %s %s while impersonating user %d",
+				claims.ImpersonatorID, c.Request.Method, c.FullPath(), claims.UserID)
+			helper.Abort(c, apperrors.ErrForbidden.WithDetail("Действие недоступно при входе от имени пользователя"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuditImpersonation пишет в лог каждый изменяющий запрос, выполненный
+// от имени пользователя, с ID администратора
+func AuditImpersonation(log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if isSafeMethod(c.Request.Method) {
+			return
+		}
+		claims, ok := c.Get(ClaimsCtx)
+		if !ok {
+			return
+		}
+		if cl, ok := claims.(*model.Claims); ok && cl.Impersonated() {
+			log.Infof("Admin %d impersonating user %d: %s %s -> %d [%s]",
+				cl.ImpersonatorID, cl.UserID, c.Request.Method, c.Request.URL.Path, c.Writer.Status(), c.GetString(RequestIDCtx))
+		}
+	}
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
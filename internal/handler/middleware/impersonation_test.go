@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestForbidImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+
+	tests := []struct {
+		name   string
+		claims *model.Claims
+		want   int
+	}{
+		{name: "own session", claims: &model.Claims{UserID: 5}, want: http.StatusOK},
+		{name: "impersonated session", claims: &model.Claims{UserID: 5, ImpersonatorID: 1}, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(Problems(log, false), func(c *gin.Context) { c.Set(ClaimsCtx, tt.claims) })
+			r.PUT("/users/me/phone", ForbidImpersonation(log), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/me/phone", nil))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuditImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		method string
+		claims *model.Claims
+		logged bool
+	}{
+		{name: "write under impersonation", method: http.MethodPatch, claims: &model.Claims{UserID: 5, ImpersonatorID: 1}, logged: true},
+		{name: "read under impersonation", method: http.MethodGet, claims: &model.Claims{UserID: 5, ImpersonatorID: 1}},
+		{name: "write in own session", method: http.MethodPatch, claims: &model.Claims{UserID: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			log := &logger.Logger{SugaredLogger: zap.New(core).Sugar()}
+
+			r := gin.New()
+			r.Use(func(c *gin.Context) { c.Set(ClaimsCtx, tt.claims) }, AuditImpersonation(log))
+			r.Handle(tt.method, "/users/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, "/users/me", nil))
+
+			entries := logs.All()
+			if !tt.logged {
+				if len(entries) != 0 {
+					t.Fatalf("unexpected log entries: %v", entries)
+				}
+				return
+			}
+			if len(entries) != 1 || !strings.Contains(entries[0].Message, "Admin 1 impersonating user 5: PATCH /users/me") {
+				t.Fatalf("log entries = %v, want one entry with the admin id", entries)
+			}
+		})
+	}
+}
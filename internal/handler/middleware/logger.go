@@ -16,7 +16,7 @@ func RequestLogger(logger *logger.Logger) gin.HandlerFunc {
 		method := c.Request.Method
 
 		// Log request
-		logger.Infof("Started %s %s", method, path)
+		logger.Infof("Started %s %s [%s]", method, path, c.GetString(RequestIDCtx))
 
 		// Process request
 		c.Next()
@@ -28,9 +28,10 @@ func RequestLogger(logger *logger.Logger) gin.HandlerFunc {
 		statusCode := c.Writer.Status()
 		statusText := http.StatusText(statusCode)
 
-		logger.Infof("Completed %s %s | %d %s | %v",
+		logger.Infof("Completed %s %s [%s] | %d %s | %v",
 			method,
 			path,
+			c.GetString(RequestIDCtx),
 			statusCode,
 			statusText,
 			latency,
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/policy"
+	"corpord-api/model"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission пропускает запрос, если у роли пользователя есть хотя бы одно из прав codes.
+// Права роли берутся из базы (с коротким кешем), а не из JWT, поэтому правки ролей
+// применяются без перевыпуска токенов. Если claims ограничены scopes (API-ключ),
+// учитываются только права из этого списка
+func RequirePermission(log *logger.Logger, roles policy.Permissions, codes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := claimsFromContext(c, log)
+		if !ok {
+			return
+		}
+
+		allowed, err := policy.Allowed(c.Request.Context(), roles, claims, codes...)
+		if err != nil {
+			log.Errorf("Permission check failed for role %s: %v", claims.Role, err)
+			helper.Abort(c, apperrors.ErrInternal)
+			return
+		}
+
+		if !allowed {
+			log.Warnf("Insufficient permissions: required %v, role %s", codes, claims.Role)
+			helper.Abort(c, apperrors.ErrForbidden.WithDetail("Недостаточно прав для выполнения операции"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireOwnerOrPermission пропускает запрос, если параметр пути param — ID самого пользователя,
+// иначе требует одно из прав codes
+func RequireOwnerOrPermission(log *logger.Logger, roles policy.Permissions, param string, codes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := claimsFromContext(c, log)
+		if !ok {
+			return
+		}
+
+		ownerID, err := strconv.Atoi(c.Param(param))
+		if err != nil {
+			helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
+			return
+		}
+
+		err = policy.OwnerOrPermission(c.Request.Context(), roles, claims, ownerID, codes...)
+		switch {
+		case errors.Is(err, policy.ErrForbidden):
+			log.Warnf("User %d denied access to resource of user %d", claims.UserID, ownerID)
+			helper.Abort(c, apperrors.ErrForbidden.WithDetail("Недостаточно прав для выполнения операции"))
+			return
+		case err != nil:
+			log.Errorf("Ownership check failed for user %d: %v", claims.UserID, err)
+			helper.Abort(c, apperrors.ErrInternal)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// claimsFromContext достаёт claims, положенные AuthMiddleware. При ошибке запрос уже прерван
+func claimsFromContext(c *gin.Context, log *logger.Logger) (*model.Claims, bool) {
+	claimsRaw, ok := c.Get(ClaimsCtx)
+	if !ok {
+		log.Warn("Permission check failed: claims not found in context")
+		helper.Abort(c, apperrors.ErrForbidden.WithDetail("Не удалось определить данные пользователя"))
+		return nil, false
+	}
+
+	claims, ok := claimsRaw.(*model.Claims)
+	if !ok {
+		log.Error("Permission check failed: invalid claims type in context")
+		helper.Abort(c, apperrors.ErrInternal)
+		return nil, false
+	}
+
+	return claims, true
+}
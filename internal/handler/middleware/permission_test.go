@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"corpord-api/internal/logger"
+	"corpord-api/internal/policy/policytest"
+	"corpord-api/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestRequireOwnerOrPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	perms := policytest.Permissions{
+		model.RoleAdmin: {model.PermUsersRead, model.PermOrdersRead},
+		"user":          {},
+	}
+
+	tests := []struct {
+		name   string
+		claims *model.Claims
+		path   string
+		want   int
+	}{
+		{name: "own resource", claims: &model.Claims{UserID: 1, Role: "user"}, path: "/users/1", want: http.StatusOK},
+		{name: "another user's resource", claims: &model.Claims{UserID: 1, Role: "user"}, path: "/users/2", want: http.StatusForbidden},
+		{name: "admin", claims: &model.Claims{UserID: 3, Role: model.RoleAdmin}, path: "/users/2", want: http.StatusOK},
+		{
+			name:   "admin api key without scope",
+			claims: &model.Claims{UserID: 3, Role: model.RoleAdmin, Scopes: []string{model.PermOrdersRead}},
+			path:   "/users/2",
+			want:   http.StatusForbidden,
+		},
+		{name: "bad id", claims: &model.Claims{UserID: 1, Role: "user"}, path: "/users/abc", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(Problems(log, false), func(c *gin.Context) { c.Set(ClaimsCtx, tt.claims) })
+			r.GET("/users/:id", RequireOwnerOrPermission(log, perms, "id", model.PermUsersRead), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/i18n"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/mergepatch"
+	"corpord-api/pkg/paging"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorMapper сопоставляет ошибку сервиса с ошибкой API. nil — ошибка ему не знакома
+type ErrorMapper func(err error) *apperrors.APIError
+
+// Problems отвечает application/problem+json на ошибку, записанную обработчиком через c.Error.
+// Ошибки *apperrors.APIError отдаются как есть, известные ошибки слоёв ниже переводятся
+// в стабильные коды, остальные становятся 500. Текст внутренних ошибок попадает
+// в ответ только при debug, в лог — всегда. Текст ответа переводится на язык клиента
+func Problems(log *logger.Logger, debug bool, mappers ...ErrorMapper) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+
+		apiErr := resolveError(err, mappers)
+		if failed := failedItems(err); len(failed) > 0 {
+			apiErr = apiErr.WithFailed(failed)
+		}
+		if apiErr.Status >= 500 {
+			log.Errorf("%s %s [%s]: %v", c.Request.Method, c.Request.URL.Path, c.GetString(RequestIDCtx), err)
+			if debug && apiErr.Detail == "" {
+				apiErr = apiErr.WithDetail(err.Error())
+			}
+		}
+
+		writeProblem(c, apiErr)
+	}
+}
+
+// writeProblem записывает ответ с ошибкой и прерывает обработку
+func writeProblem(c *gin.Context, e *apperrors.APIError) {
+	lang := helper.Lang(c)
+	helper.SetLangHeaders(c, lang)
+
+	body, err := json.Marshal(apperrors.NewProblem(localize(e, lang), c.Request.URL.Path, c.GetString(RequestIDCtx)))
+	if err != nil {
+		c.AbortWithStatus(e.Status)
+		return
+	}
+	c.Data(e.Status, apperrors.ContentTypeProblem, body)
+	c.Abort()
+}
+
+// localize возвращает копию ошибки с заголовком, уточнением и ошибками полей на языке lang
+func localize(e *apperrors.APIError, lang i18n.Lang) *apperrors.APIError {
+	cp := *e
+	cp.Message = i18n.Title(lang, e.Code, e.Message)
+	cp.Detail = i18n.Text(lang, e.Detail)
+	if len(e.Fields) > 0 {
+		cp.Fields = make([]apperrors.FieldError, len(e.Fields))
+		for i, f := range e.Fields {
+			f.Message = i18n.Field(lang, f.Code, f.Param)
+			cp.Fields[i] = f
+		}
+	}
+	return &cp
+}
+
+// failedItems собирает индексы элементов пакетной операции, упомянутых в ошибке
+func failedItems(err error) []int {
+	var failed []int
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case *pg.ItemError:
+			failed = append(failed, e.Index)
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		}
+	}
+	walk(err)
+	return failed
+}
+
+func resolveError(err error, mappers []ErrorMapper) *apperrors.APIError {
+	var (
+		apiErr    *apperrors.APIError
+		filterErr *filter.Error
+	)
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	for _, m := range mappers {
+		if e := m(err); e != nil {
+			return e
+		}
+	}
+
+	switch {
+	case errors.Is(err, paging.ErrInvalidParams):
+		return apperrors.ErrBadRequest.WithDetail("Некорректные параметры постраничной выдачи")
+	case errors.Is(err, paging.ErrInvalidSort):
+		return apperrors.ErrBadRequest.WithDetail("Сортировка по этому полю не поддерживается")
+	case errors.Is(err, paging.ErrInvalidCursor):
+		return apperrors.ErrBadRequest.WithDetail("Некорректный курсор")
+	case errors.As(err, &filterErr):
+		return filterError(filterErr)
+	case errors.Is(err, mergepatch.ErrInvalidPatch):
+		return apperrors.ErrBadRequest.WithDetail("Тело запроса не является корректным merge patch")
+	case errors.Is(err, pg.ErrNotFound), errors.Is(err, sql.ErrNoRows):
+		return apperrors.ErrNotFound
+	case errors.Is(err, pg.ErrUniqueViolation), errors.Is(err, pg.ErrAlreadyExists),
+		pg.IsPgError(err, pg.ErrorCodeUniqueViolation):
+		return apperrors.ErrConflict.WithDetail("Запись с такими данными уже существует")
+	case errors.Is(err, pg.ErrForeignKeyViolation), pg.IsPgError(err, pg.ErrorCodeForeignKeyViolation):
+		return apperrors.ErrConflict.WithDetail("Операция нарушает связь с другими записями")
+	}
+
+	return apperrors.ErrInternal
+}
+
+// filterCodes коды ошибок параметров фильтра для клиента
+var filterCodes = []struct {
+	err  error
+	code string
+}{
+	{filter.ErrInvalidSyntax, "filter_syntax"},
+	{filter.ErrUnknownField, "filter_field"},
+	{filter.ErrUnsupportedOp, "filter_op"},
+	{filter.ErrInvalidValue, "filter_value"},
+}
+
+// filterError ошибка фильтра с параметром запроса и оператором в errors, а не в тексте
+func filterError(e *filter.Error) *apperrors.APIError {
+	code := "filter_syntax"
+	for _, fc := range filterCodes {
+		if errors.Is(e.Err, fc.err) {
+			code = fc.code
+			break
+		}
+	}
+	op := string(e.Op)
+	return apperrors.ErrBadRequest.WithDetail("Некорректный фильтр").WithFields(apperrors.FieldError{
+		Field:   e.Key,
+		Code:    code,
+		Message: i18n.Field(i18n.Default, code, op),
+		Param:   op,
+	})
+}
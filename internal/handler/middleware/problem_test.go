@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/pkg/filter"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestFailedItems(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []int
+	}{
+		{name: "plain error", err: pg.ErrNotFound},
+		{name: "single item", err: &pg.ItemError{Index: 3, Err: pg.ErrNotFound}, want: []int{3}},
+		{
+			name: "wrapped item",
+			err:  fmt.Errorf("update stops: %w", &pg.ItemError{Index: 1, Err: pg.ErrNotFound}),
+			want: []int{1},
+		},
+		{
+			name: "joined items",
+			err: errors.Join(
+				&pg.ItemError{Index: 0, Err: pg.ErrNotFound},
+				errors.New("unrelated"),
+				fmt.Errorf("item: %w", &pg.ItemError{Index: 4, Err: pg.ErrUniqueViolation}),
+			),
+			want: []int{0, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := failedItems(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("failedItems = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblemsFilterError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	spec := filter.Spec{"price": {Column: "price", Kind: filter.Float}}
+
+	r := gin.New()
+	r.Use(Problems(log, false))
+	r.GET("/trips", func(c *gin.Context) {
+		f, err := filter.FromQuery(c.Request.URL.Query())
+		if err == nil {
+			_, err = spec.Build(f)
+		}
+		helper.Abort(c, fmt.Errorf("list trips: %w", err))
+	})
+
+	tests := []struct {
+		lang  string
+		query string
+		want  apperrors.FieldError
+	}{
+		{
+			lang:  "ru",
+			query: "filter[price][contains]=1",
+			want:  apperrors.FieldError{Field: "filter[price][contains]", Code: "filter_op", Message: "Оператор contains не поддерживается для этого поля"},
+		},
+		{
+			lang:  "en",
+			query: "filter[price][lt]=cheap",
+			want:  apperrors.FieldError{Field: "filter[price][lt]", Code: "filter_value", Message: "Invalid value for the lt operator"},
+		},
+		{
+			lang:  "en",
+			query: "filter[color]=red",
+			want:  apperrors.FieldError{Field: "filter[color]", Code: "filter_field", Message: "Filtering by this field is not supported"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/trips?"+tt.query, nil)
+			req.Header.Set("Accept-Language", tt.lang)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var problem apperrors.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("body %q: %v", w.Body.String(), err)
+			}
+			if w.Code != http.StatusBadRequest || len(problem.Errors) != 1 || problem.Errors[0] != tt.want {
+				t.Fatalf("response = %d %+v, want 400 with %+v", w.Code, problem.Errors, tt.want)
+			}
+		})
+	}
+}
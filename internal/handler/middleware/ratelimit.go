@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/config"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/ratelimit"
+	"corpord-api/model"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// Ключи, по которым считаются лимиты. Пользователь и API-ключ берутся только
+// из claims, проверенных AuthMiddleware, поэтому такие правила ставятся после неё;
+// на анонимных маршрутах лимит считается по IP
+const (
+	RateLimitByIP     = "ip"
+	RateLimitByUser   = "user"
+	RateLimitByAPIKey = "api_key"
+)
+
+// RateLimit ограничивает частоту запросов по правилу name из конфигурации.
+// Если правило не задано или лимиты выключены, возвращает пустой middleware.
+// Ошибки хранилища не блокируют запросы.
+func RateLimit(log *logger.Logger, cfg *config.RateLimit, store ratelimit.Store, name string) gin.HandlerFunc {
+	ruleCfg, ok := cfg.Rules[name]
+	if !cfg.Enabled || !ok || ruleCfg.Requests <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	rule := ratelimit.Rule{
+		Requests: ruleCfg.Requests,
+		Per:      ruleCfg.Per,
+		Burst:    ruleCfg.Burst,
+	}
+
+	return func(c *gin.Context) {
+		key := name + ":" + subjectKey(c, ruleCfg.Key)
+
+		res, err := store.Allow(c.Request.Context(), key, rule)
+		if err != nil {
+			log.Warnf("rate limit check failed for %s: %v", key, err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+
+		if !res.Allowed {
+			log.Warnf("rate limit exceeded for %s", key)
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			helper.Abort(c, apperrors.ErrTooManyRequests.WithDetail("Слишком много запросов. Попробуйте позже"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// subjectKey определяет субъекта запроса. API-ключ и пользователь
+// откатываются к более общему ключу, если запрос не аутентифицирован ими.
+// Заголовок X-API-Key сам по себе не учитывается: иначе случайный ключ
+// в каждом запросе давал бы новую корзину
+func subjectKey(c *gin.Context, by string) string {
+	var claims *model.Claims
+	if raw, ok := c.Get(ClaimsCtx); ok {
+		claims, _ = raw.(*model.Claims)
+	}
+
+	switch by {
+	case RateLimitByAPIKey:
+		if claims != nil && claims.Provider == model.ProviderAPIKey && claims.ProviderID != "" {
+			return "key:" + claims.ProviderID
+		}
+		fallthrough
+	case RateLimitByUser:
+		if claims != nil && claims.UserID != 0 {
+			return "user:" + strconv.Itoa(claims.UserID)
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
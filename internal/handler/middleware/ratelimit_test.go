@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"corpord-api/internal/config"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/ratelimit"
+	"corpord-api/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestSubjectKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &model.Claims{UserID: 7, Provider: "local"}
+	apiKey := &model.Claims{UserID: 8, Provider: model.ProviderAPIKey, ProviderID: "ck_abc123"}
+
+	tests := []struct {
+		name   string
+		by     string
+		claims *model.Claims
+		header string
+		want   string
+	}{
+		{name: "ip", by: RateLimitByIP, claims: user, want: "ip:192.0.2.1"},
+		{name: "user", by: RateLimitByUser, claims: user, want: "user:7"},
+		{name: "anonymous user falls back to ip", by: RateLimitByUser, want: "ip:192.0.2.1"},
+		{name: "authenticated api key", by: RateLimitByAPIKey, claims: apiKey, want: "key:ck_abc123"},
+		{name: "api key rule with user token", by: RateLimitByAPIKey, claims: user, want: "user:7"},
+		{name: "unauthenticated api key header", by: RateLimitByAPIKey, header: "ck_random", want: "ip:192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(APIKeyHeader, tt.header)
+			}
+			if tt.claims != nil {
+				c.Set(ClaimsCtx, tt.claims)
+			}
+
+			if got := subjectKey(c, tt.by); got != tt.want {
+				t.Fatalf("subjectKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitIgnoresRandomAPIKeyHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+	cfg := &config.RateLimit{
+		Enabled: true,
+		Rules:   map[string]config.RateLimitRule{"api": {Requests: 1, Per: time.Minute, Key: RateLimitByAPIKey}},
+	}
+
+	r := gin.New()
+	r.Use(Problems(log, false), RateLimit(log, cfg, ratelimit.NewMemoryStore(), "api"))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i, key := range []string{"ck_first", "ck_second"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(APIKeyHeader, key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		want := http.StatusOK
+		if i > 0 {
+			want = http.StatusTooManyRequests
+		}
+		if w.Code != want {
+			t.Fatalf("request %d: status = %d, want %d", i, w.Code, want)
+		}
+	}
+}
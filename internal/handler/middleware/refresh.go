@@ -1,9 +1,10 @@
 package middleware
 
 import (
+	"corpord-api/internal/apperrors"
 	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/service"
-	"net/http"
+	"corpord-api/model"
 	"strings"
 	"time"
 
@@ -18,6 +19,15 @@ const (
 // RefreshMiddleware автоматически обновляет access token при необходимости
 func RefreshMiddleware(auth service.Auth) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// API-ключ не использует refresh-сессии
+		if raw, ok := c.Get(ClaimsCtx); ok {
+			if claims, ok := raw.(*model.Claims); ok && claims.Provider == model.ProviderAPIKey {
+				c.Set("userID", claims.UserID)
+				c.Next()
+				return
+			}
+		}
+
 		// Получаем access token из заголовка Authorization
 		authHeader := c.GetHeader(AccessTokenHeader)
 		tokenStr := ""
@@ -40,7 +50,7 @@ func RefreshMiddleware(auth service.Auth) gin.HandlerFunc {
 		rtCookie, err := c.Cookie(RefreshTokenCookie)
 		if err != nil {
 			// Нет refresh token → Unauthorized
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token expired, please login"})
+			helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Сессия истекла, войдите снова"))
 			return
 		}
 
@@ -49,7 +59,7 @@ func RefreshMiddleware(auth service.Auth) gin.HandlerFunc {
 
 		tokens, err := auth.Refresh(c.Request.Context(), rtCookie, userAgent, ip)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token, please login"})
+			helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Сессия недействительна, войдите снова"))
 			return
 		}
 
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	RequestIDCtx    = "requestID"
+)
+
+// входящий ID принимаем, только если он не сломает логи и заголовки
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID присваивает запросу идентификатор: берёт X-Request-ID от прокси или клиента,
+// иначе генерирует новый. ID возвращается в заголовке ответа и попадает в логи и ошибки
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Set(RequestIDCtx, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/model"
 	"fmt"
@@ -19,26 +20,20 @@ func RoleMiddleware(log *logger.Logger, requiredRoles ...string) gin.HandlerFunc
 		claimsRaw, ok := c.Get(ClaimsCtx)
 		if !ok {
 			log.Warn("Role check failed: claims not found in context")
-			c.AbortWithStatusJSON(apperrors.ErrForbidden.Status, apperrors.ErrorResponse{
-				Error: "Не удалось определить данные пользователя",
-			})
+			helper.Abort(c, apperrors.ErrForbidden.WithDetail("Не удалось определить данные пользователя"))
 			return
 		}
 
 		claims, ok := claimsRaw.(*model.Claims)
 		if !ok {
 			log.Error("Role check failed: invalid claims type in context")
-			c.AbortWithStatusJSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-				Error: "Внутренняя ошибка сервера",
-			})
+			helper.Abort(c, apperrors.ErrInternal)
 			return
 		}
 
 		if _, exists := roleSet[claims.Role]; !exists {
 			log.Warnf("Insufficient permissions: required %v, got %s", requiredRoles, claims.Role)
-			c.AbortWithStatusJSON(apperrors.ErrForbidden.Status, apperrors.ErrorResponse{
-				Error: fmt.Sprintf("Недостаточно прав. Требуется одна из ролей: %v", requiredRoles),
-			})
+			helper.Abort(c, apperrors.ErrForbidden.WithDetail(fmt.Sprintf("Недостаточно прав. Требуется одна из ролей: %v", requiredRoles)))
 			return
 		}
 
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"corpord-api/pkg/dbx"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IncludeDeleted по ?include_deleted=true помечает контекст запроса, чтобы репозитории
+// вернули и мягко удалённые записи. Ставится только на маршруты, закрытые правами администратора
+func IncludeDeleted() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if include, _ := strconv.ParseBool(c.Query("include_deleted")); include {
+			c.Request = c.Request.WithContext(dbx.WithDeleted(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
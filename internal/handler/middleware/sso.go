@@ -1,31 +1,26 @@
 package middleware
 
 import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/sso"
 	"github.com/gin-gonic/gin"
-	"net/http"
 )
 
+// SSOMiddleware проверяет провайдера и кладёт в контекст provider и code.
+// Отсутствие code обрабатывает сам callback: провайдер мог вернуть ?error=
 func SSOMiddleware(reg *sso.Registry) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		providerName := c.Param("provider")
 
 		_, err := reg.Get(providerName)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "provider not supported"})
-			c.Abort()
-			return
-		}
-
-		code := c.Query("code")
-		if code == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "missing code"})
-			c.Abort()
+			helper.Abort(c, apperrors.ErrNotFound.WithDetail("Провайдер не поддерживается"))
 			return
 		}
 
 		c.Set("provider", providerName)
-		c.Set("code", code)
+		c.Set("code", c.Query("code"))
 
 		c.Next()
 	}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/service"
+	"corpord-api/model"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PassengerHandler записная книжка пассажиров текущего пользователя
+type PassengerHandler struct {
+	logger *logger.Logger
+	s      service.Passenger
+}
+
+func NewPassenger(logger *logger.Logger, s service.Passenger) *PassengerHandler {
+	return &PassengerHandler{
+		logger: logger,
+		s:      s,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты пассажиров в группе /users
+func (h *PassengerHandler) RegisterRoutes(users *gin.RouterGroup) {
+	passengers := users.Group("/me/passengers")
+	{
+		passengers.GET("", h.All)
+		passengers.POST("", h.Create)
+		passengers.GET("/:id", h.ByID)
+		passengers.PUT("/:id", h.Update)
+		passengers.DELETE("/:id", h.Delete)
+	}
+}
+
+// All возвращает сохранённых пассажиров
+// @Summary Получить пассажиров
+// @Description Возвращает пассажиров текущего пользователя. Номера документов замаскированы
+// @Tags passengers
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} model.PassengerResponse "Список пассажиров"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/passengers [get]
+func (h *PassengerHandler) All(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.s.All(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Errorf("failed to get passengers of user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// ByID возвращает пассажира
+// @Summary Получить пассажира
+// @Description Возвращает сохранённого пассажира текущего пользователя
+// @Tags passengers
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пассажира"
+// @Success 200 {object} model.PassengerResponse "Пассажир"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 404 {object} apperrors.Problem "Пассажир не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/passengers/{id} [get]
+func (h *PassengerHandler) ByID(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := h.passengerID(c)
+	if !ok {
+		return
+	}
+
+	p, err := h.s.ByID(c.Request.Context(), userID, id)
+	if err != nil {
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Create сохраняет пассажира
+// @Summary Добавить пассажира
+// @Description Сохраняет пассажира и его документ. Номер документа хранится в зашифрованном виде
+// @Tags passengers
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param input body model.PassengerCreate true "Данные пассажира"
+// @Success 201 {object} model.PassengerResponse "Пассажир сохранён"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/passengers [post]
+func (h *PassengerHandler) Create(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var input model.PassengerCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	p, err := h.s.Create(c.Request.Context(), userID, &input)
+	if err != nil {
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// Update изменяет пассажира
+// @Summary Обновить пассажира
+// @Description Изменяет данные сохранённого пассажира. Передаются только изменяемые поля
+// @Tags passengers
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пассажира"
+// @Param input body model.PassengerUpdate true "Изменяемые поля"
+// @Success 200 {object} model.PassengerResponse "Пассажир обновлён"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 404 {object} apperrors.Problem "Пассажир не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/passengers/{id} [put]
+func (h *PassengerHandler) Update(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := h.passengerID(c)
+	if !ok {
+		return
+	}
+
+	var input model.PassengerUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	p, err := h.s.Update(c.Request.Context(), userID, id, &input)
+	if err != nil {
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Delete удаляет пассажира
+// @Summary Удалить пассажира
+// @Description Удаляет сохранённого пассажира текущего пользователя
+// @Tags passengers
+// @Security Bearer
+// @Param id path int true "ID пассажира"
+// @Success 204 "Пассажир удалён"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 404 {object} apperrors.Problem "Пассажир не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/passengers/{id} [delete]
+func (h *PassengerHandler) Delete(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	id, ok := h.passengerID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.s.Delete(c.Request.Context(), userID, id); err != nil {
+		helper.Abort(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PassengerHandler) currentUser(c *gin.Context) (int, bool) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+	}
+	return userID, ok
+}
+
+func (h *PassengerHandler) passengerID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пассажира"))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/handler/middleware"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/service"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PrivacyHandler выгрузка и удаление персональных данных текущего пользователя
+type PrivacyHandler struct {
+	logger *logger.Logger
+	s      service.Privacy
+}
+
+func NewPrivacy(logger *logger.Logger, s service.Privacy) *PrivacyHandler {
+	return &PrivacyHandler{
+		logger: logger,
+		s:      s,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты в группе /users
+func (h *PrivacyHandler) RegisterRoutes(users *gin.RouterGroup) {
+	users.GET("/me/export", h.Export)
+	// удаление аккаунта — решение самого пользователя, не администратора, вошедшего от его имени
+	own := middleware.ForbidImpersonation(h.logger)
+	users.POST("/me/erasure", own, h.RequestErasure)
+	users.DELETE("/me/erasure", own, h.CancelErasure)
+}
+
+// Export выгружает персональные данные
+// @Summary Выгрузить персональные данные
+// @Description Возвращает ZIP-архив с JSON-файлами: профиль, привязанные аккаунты, сессии, пассажиры и заказы
+// @Tags users
+// @Produce application/zip
+// @Security Bearer
+// @Success 200 {file} file "ZIP-архив"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/export [get]
+func (h *PrivacyHandler) Export(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	data, err := h.s.Export(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Errorf("failed to export data of user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("corpord-export-%d-%s.zip", userID, time.Now().Format("20060102"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// RequestErasure запрашивает удаление персональных данных
+// @Summary Запросить удаление данных
+// @Description Ставит аккаунт в очередь на обезличивание. До указанного срока запрос можно отменить, после него имя, контакты, документы и сессии удаляются, а заказы сохраняются без персональных данных
+// @Tags users
+// @Produce json
+// @Security Bearer
+// @Success 202 {object} model.ErasureResponse "Запрос принят"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Недоступно при входе от имени пользователя"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/erasure [post]
+func (h *PrivacyHandler) RequestErasure(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	resp, err := h.s.RequestErasure(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Errorf("failed to request erasure for user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// CancelErasure отменяет запрос на удаление персональных данных
+// @Summary Отменить удаление данных
+// @Description Отменяет запрос на удаление, пока данные ещё не обезличены
+// @Tags users
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} apperrors.SuccessResponse "Запрос отменен"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Недоступно при входе от имени пользователя"
+// @Failure 404 {object} apperrors.Problem "Запрос на удаление не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/erasure [delete]
+func (h *PrivacyHandler) CancelErasure(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	if err := h.s.CancelErasure(c.Request.Context(), userID); err != nil {
+		h.logger.Errorf("failed to cancel erasure for user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.Success(c, http.StatusOK, "Запрос на удаление отменен")
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"context"
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// restoreByID общая часть обработчиков POST .../:id/restore: разбирает id, вызывает restore
+// и отвечает 204, 400, 404 (notFound) или 500
+func restoreByID(c *gin.Context, log *logger.Logger, restore func(ctx context.Context, id int) error, notFound error, notFoundMsg string) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
+		return
+	}
+
+	if err := restore(c.Request.Context(), id); err != nil {
+		if errors.Is(err, notFound) {
+			helper.Abort(c, apperrors.ErrNotFound.WithDetail(notFoundMsg))
+			return
+		}
+		log.Errorf("failed to restore %d: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
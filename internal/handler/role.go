@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/service"
+	"corpord-api/model"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler управление ролями и правами
+type RoleHandler struct {
+	logger *logger.Logger
+	s      service.Role
+}
+
+func NewRole(logger *logger.Logger, s service.Role) *RoleHandler {
+	return &RoleHandler{
+		logger: logger,
+		s:      s,
+	}
+}
+
+// All возвращает список ролей с правами
+// @Summary Получить все роли
+// @Description Возвращает список ролей и назначенных им прав
+// @Tags admin/roles
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} model.Role "Список ролей"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/roles [get]
+func (h *RoleHandler) All(c *gin.Context) {
+	roles, err := h.s.All(c.Request.Context())
+	if err != nil {
+		h.logger.Errorf("failed to get roles: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// Permissions возвращает справочник прав
+// @Summary Получить все права
+// @Description Возвращает список прав, которые можно назначить ролям
+// @Tags admin/roles
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} model.Permission "Список прав"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/permissions [get]
+func (h *RoleHandler) Permissions(c *gin.Context) {
+	perms, err := h.s.AllPermissions(c.Request.Context())
+	if err != nil {
+		h.logger.Errorf("failed to get permissions: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, perms)
+}
+
+// Create создает роль
+// @Summary Создать роль
+// @Description Создает новую роль с указанным набором прав
+// @Tags admin/roles
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param input body model.RoleCreate true "Данные роли"
+// @Success 201 {object} model.Role "Созданная роль"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные или неизвестное право"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 409 {object} apperrors.Problem "Роль уже существует"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/roles [post]
+func (h *RoleHandler) Create(c *gin.Context) {
+	var input model.RoleCreate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	out, err := h.s.Create(c.Request.Context(), &input)
+	if err != nil {
+		h.logger.Errorf("failed to create role: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+// SetPermissions заменяет права роли
+// @Summary Изменить права роли
+// @Description Заменяет набор прав роли переданным списком
+// @Tags admin/roles
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID роли"
+// @Param input body model.RolePermissionsUpdate true "Список прав"
+// @Success 200 {object} model.Role "Обновленная роль"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные или неизвестное право"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Роль не найдена"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/roles/{id}/permissions [put]
+func (h *RoleHandler) SetPermissions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID роли"))
+		return
+	}
+
+	var input model.RolePermissionsUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	out, err := h.s.SetPermissions(c.Request.Context(), id, input.Permissions)
+	if err != nil {
+		h.logger.Errorf("failed to update permissions of role %d: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// AssignToUser назначает роль пользователю
+// @Summary Назначить роль пользователю
+// @Description Меняет роль пользователя. Действует на новые токены, текущий access token сохраняет прежнюю роль до истечения
+// @Tags admin/users
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "ID пользователя"
+// @Param input body model.UserRoleUpdate true "ID роли"
+// @Success 200 {object} apperrors.SuccessResponse "Роль назначена"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Пользователь или роль не найдены"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/role [put]
+func (h *RoleHandler) AssignToUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
+		return
+	}
+
+	var input model.UserRoleUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	if err := h.s.AssignToUser(c.Request.Context(), id, input.RoleID); err != nil {
+		h.logger.Errorf("failed to assign role %d to user %d: %v", input.RoleID, id, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.Success(c, http.StatusOK, "Роль назначена")
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/config"
 	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/handler/middleware"
 	"corpord-api/internal/logger"
@@ -8,90 +10,293 @@ import (
 	"corpord-api/internal/sso"
 	"corpord-api/internal/token"
 	"corpord-api/model"
-	"crypto/rand"
-	"encoding/base64"
+	"errors"
 	"net/http"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Коды ошибок, которые фронтенд получает в ?error= после неудачного SSO-входа
+const (
+	ssoErrorDenied  = "access_denied"
+	ssoErrorState   = "invalid_state"
+	ssoErrorFailed  = "login_failed"
+	ssoErrorMissing = "missing_code"
+	ssoErrorExists  = "account_exists"
+	ssoErrorLinked  = "already_linked"
+	ssoErrorBlocked = "account_blocked"
+)
+
 type SSOHandler struct {
 	log      *logger.Logger
 	s        service.Auth
 	registry *sso.Registry
 	t        token.Manager
+	cfg      *config.SSO
 }
 
-func NewSSOHandler(log *logger.Logger, s service.Auth, reg *sso.Registry, t token.Manager) *SSOHandler {
-	return &SSOHandler{log: log, s: s, registry: reg, t: t}
-}
-
-func generateState() (string, error) {
-	b := make([]byte, 32)
-	if _, err := rand.Read(b); err != nil {
-		return "", err
-	}
-	return base64.RawURLEncoding.EncodeToString(b), nil
+func NewSSOHandler(log *logger.Logger, s service.Auth, reg *sso.Registry, t token.Manager, cfg *config.SSO) *SSOHandler {
+	return &SSOHandler{log: log, s: s, registry: reg, t: t, cfg: cfg}
 }
 
+// redirectToProvider начинает вход через провайдера
+// @Summary Вход через SSO-провайдера
+// @Description Перенаправляет на страницу авторизации провайдера. return_to — адрес фронтенда для возврата, должен входить в список разрешённых
+// @Tags auth
+// @Param provider path string true "Провайдер (google, yandex, vk, ...)"
+// @Param return_to query string false "Адрес возврата после входа"
+// @Success 307 "Перенаправление к провайдеру"
+// @Failure 404 {object} apperrors.Problem "Провайдер не поддерживается"
+// @Router /auth/{provider} [get]
 func (h *SSOHandler) redirectToProvider(c *gin.Context) {
 	providerName := c.Param("provider")
 
-	p, err := h.registry.Get(providerName)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "provider not supported"})
-		return
-	}
-
-	state, err := generateState()
+	authURL, binding, err := h.s.BeginSSO(c.Request.Context(), providerName, c.Query("return_to"))
 	if err != nil {
-		h.log.Errorf("state generation error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "state error"})
+		if !errors.Is(err, service.ErrProviderNotSupported) {
+			h.log.Errorf("failed to start sso login with %s: %v", providerName, err)
+		}
+		helper.Abort(c, err)
 		return
 	}
 
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     "oauth_state",
-		Value:    state,
-		Path:     "/",
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   300,
-	})
+	helper.SetOAuthStateCookie(c, binding, h.cfg.StateTTL)
 
-	url := p.AuthURL(state)
-
-	h.log.Infof("redirecting to provider %s: %s", providerName, url)
-	c.Redirect(http.StatusTemporaryRedirect, url)
+	h.log.Infof("redirecting to provider %s", providerName)
+	c.Redirect(http.StatusTemporaryRedirect, authURL)
 }
 
+// callbackFromProvider завершает вход: проверяет state, выдаёт refresh cookie
+// и возвращает пользователя на фронтенд, который получает access token через /auth/refresh
+// @Summary Callback SSO-провайдера
+// @Description Проверяет одноразовый state и cookie oauth_state браузера, начавшего вход, устанавливает refresh cookie и перенаправляет на фронтенд. При ошибке добавляет параметр error
+// @Tags auth
+// @Param provider path string true "Провайдер"
+// @Param code query string false "Код авторизации"
+// @Param state query string true "State"
+// @Success 302 "Перенаправление на фронтенд"
+// @Router /auth/{provider}/callback [get]
 func (h *SSOHandler) callbackFromProvider(c *gin.Context) {
 	provider := c.GetString("provider")
 	code := c.GetString("code")
 
-	resp, err := h.s.SSOLogin(
+	// state одноразовый, cookie больше не нужна при любом исходе
+	binding, _ := c.Cookie(helper.OAuthStateCookie)
+	helper.ClearOAuthStateCookie(c)
+	// при привязке сессия вошедшего пользователя должна совпасть с тем, кто её начал
+	session, _ := c.Cookie("refresh_token")
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		h.log.Warnf("SSO login with %s rejected by provider: %s", provider, providerErr)
+		h.redirectWithError(c, ssoErrorDenied)
+		return
+	}
+	if code == "" {
+		h.redirectWithError(c, ssoErrorMissing)
+		return
+	}
+
+	resp, returnURL, err := h.s.CompleteSSO(
 		c.Request.Context(),
 		provider,
-		code,
-		"",
-		"",
+		sso.Callback{
+			Code:     code,
+			State:    c.Query("state"),
+			DeviceID: c.Query("device_id"),
+		},
+		binding,
+		session,
 		c.GetHeader("User-Agent"),
 		c.ClientIP(),
 	)
 	if err != nil {
 		h.log.Warnf("SSO login failed for %s: %v", provider, err)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, service.ErrInvalidOAuthState):
+			h.redirectWithError(c, ssoErrorState)
+		case errors.Is(err, service.ErrSSOEmailTaken):
+			h.redirectWithError(c, ssoErrorExists)
+		case errors.Is(err, service.ErrIdentityLinked):
+			h.redirectWithError(c, ssoErrorLinked)
+		case errors.Is(err, service.ErrUserBlocked):
+			h.redirectWithError(c, ssoErrorBlocked)
+		default:
+			h.redirectWithError(c, ssoErrorFailed)
+		}
+		return
+	}
+
+	// привязка к уже вошедшему пользователю — новые токены не нужны
+	if resp == nil {
+		c.Redirect(http.StatusFound, withQuery(returnURL, "linked", provider))
 		return
 	}
 
 	helper.SetRefreshCookie(c, resp.RefreshToken, h.t.RefreshTTL())
-	c.JSON(http.StatusOK, model.TokenResponse{AccessToken: resp.AccessToken})
-	c.Redirect(http.StatusFound, "http://localhost:3000/auth/callback")
+	c.Redirect(http.StatusFound, returnURL)
+}
+
+// telegramLogin вход через Telegram Login Widget
+// @Summary Вход через Telegram
+// @Description Принимает данные, полученные фронтендом от Telegram Login Widget, проверяет подпись и выдаёт токены
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param input body sso.TelegramAuthData true "Данные виджета Telegram"
+// @Success 200 {object} model.TokenResponse "Успешный вход"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Подпись не прошла проверку или данные устарели"
+// @Failure 403 {object} apperrors.Problem "Аккаунт заблокирован"
+// @Failure 404 {object} apperrors.Problem "Вход через Telegram отключён"
+// @Failure 409 {object} apperrors.Problem "Аккаунт уже существует"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /auth/telegram [post]
+func (h *SSOHandler) telegramLogin(c *gin.Context) {
+	var req sso.TelegramAuthData
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warnf("invalid telegram auth body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	tokens, err := h.s.TelegramLogin(c.Request.Context(), req, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		h.log.Warnf("telegram login failed: %v", err)
+		helper.Abort(c, err)
+		return
+	}
+
+	helper.SetRefreshCookie(c, tokens.RefreshToken, h.t.RefreshTTL())
+	c.JSON(http.StatusOK, model.TokenResponse{AccessToken: tokens.AccessToken})
+}
+
+// Identities возвращает привязанные внешние аккаунты текущего пользователя
+// @Summary Привязанные SSO-аккаунты
+// @Description Возвращает список внешних провайдеров, привязанных к аккаунту
+// @Tags users
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} model.UserIdentityResponse "Привязанные аккаунты"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/identities [get]
+func (h *SSOHandler) Identities(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	identities, err := h.s.Identities(c.Request.Context(), userID)
+	if err != nil {
+		h.log.Errorf("failed to get identities of user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	out := make([]*model.UserIdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		out = append(out, identity.ToResponse())
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Link начинает привязку провайдера к текущему аккаунту
+// @Summary Привязать SSO-провайдера
+// @Description Возвращает URL авторизации у провайдера и устанавливает cookie oauth_state: открыть URL нужно в этом же браузере с действующей сессией. После подтверждения провайдер вернёт пользователя на return_to с параметром linked или error
+// @Tags users
+// @Produce json
+// @Security Bearer
+// @Param provider path string true "Провайдер (google, yandex, vk, ...)"
+// @Param return_to query string false "Адрес возврата после привязки"
+// @Success 200 {object} model.SSOLinkResponse "URL для перехода"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Недоступно при входе от имени пользователя"
+// @Failure 404 {object} apperrors.Problem "Провайдер не поддерживается"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/identities/{provider} [post]
+func (h *SSOHandler) Link(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	authURL, binding, err := h.s.BeginLink(c.Request.Context(), userID, c.Param("provider"), c.Query("return_to"))
+	if err != nil {
+		h.log.Errorf("failed to start linking for user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	// переход по URL должен произойти в этом же браузере: callback проверит cookie
+	helper.SetOAuthStateCookie(c, binding, h.cfg.StateTTL)
+
+	c.JSON(http.StatusOK, model.SSOLinkResponse{URL: authURL})
+}
+
+// Unlink отвязывает внешний аккаунт
+// @Summary Отвязать SSO-провайдера
+// @Description Удаляет привязку внешнего аккаунта. Нельзя удалить последний способ входа
+// @Tags users
+// @Produce json
+// @Security Bearer
+// @Param id path string true "ID привязки"
+// @Success 204 "Привязка удалена"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Недоступно при входе от имени пользователя"
+// @Failure 404 {object} apperrors.Problem "Привязка не найдена"
+// @Failure 409 {object} apperrors.Problem "Это последний способ входа"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me/identities/{id} [delete]
+func (h *SSOHandler) Unlink(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	if err := h.s.Unlink(c.Request.Context(), userID, c.Param("id")); err != nil {
+		h.log.Warnf("failed to unlink identity for user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func withQuery(target, key, value string) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (h *SSOHandler) redirectWithError(c *gin.Context, code string) {
+	c.Redirect(http.StatusFound, withQuery(h.cfg.FrontendRedirectURL, "error", code))
 }
 
 func (h *SSOHandler) RegisterRoutes(r *gin.RouterGroup) {
+	// Telegram не OAuth2: данные виджета приходят от фронтенда одним запросом
+	r.POST("/auth/telegram", h.telegramLogin)
+
 	ssoGroup := r.Group("/auth/:provider")
 
 	ssoGroup.GET("", h.redirectToProvider)
 	ssoGroup.GET("/callback", middleware.SSOMiddleware(h.registry), h.callbackFromProvider)
 }
+
+// RegisterLinkRoutes маршруты привязки, требуют авторизации
+func (h *SSOHandler) RegisterLinkRoutes(r *gin.RouterGroup) {
+	identities := r.Group("/me/identities")
+
+	identities.GET("", h.Identities)
+	// привязка способа входа под чужим токеном оставила бы администратору постоянный доступ
+	identities.POST("/:provider", middleware.ForbidImpersonation(h.log), h.Link)
+	identities.DELETE("/:id", middleware.ForbidImpersonation(h.log), h.Unlink)
+}
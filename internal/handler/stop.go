@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
@@ -14,7 +16,11 @@ type Stop interface {
 	ByID(c *gin.Context)
 	Create(c *gin.Context)
 	Update(c *gin.Context)
+	BulkCreate(c *gin.Context)
+	BulkUpdate(c *gin.Context)
+	Patch(c *gin.Context)
 	Delete(c *gin.Context)
+	Restore(c *gin.Context)
 }
 
 type stop struct {
@@ -34,16 +40,30 @@ func NewStop(logger *logger.Logger, s service.Stop) Stop {
 // @Description Возвращает все остановки в системе
 // @Tags stops
 // @Produce json
-// @Success 200 {object} model.Stop "Модель остановок"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Param limit query int false "Размер страницы, по умолчанию 20, не больше 100"
+// @Param page query int false "Номер страницы, в ответе появится total"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Поле сортировки: id, name, created_at. С '-' — по убыванию"
+// @Param filter query string false "Фильтр вида filter[поле][оператор]=значение, например filter[name][contains]=вокзал. Поля: id, name, address, latitude, longitude, created_at. Операторы: eq (по умолчанию), ne, gt, gte, lt, lte, in (через запятую), contains, null"
+// @Success 200 {object} paging.Page[model.Stop] "Страница остановок"
+// @Failure 400 {object} apperrors.Problem "Некорректные параметры списка"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /stops [get]
+// @Router /admin/stops [get]
 func (s *stop) All(c *gin.Context) {
-	output, err := s.s.All(c.Request.Context())
+	p, ok := helper.PagingParams(c)
+	if !ok {
+		return
+	}
+	f, ok := helper.FilterParams(c)
+	if !ok {
+		return
+	}
+	output, err := s.s.All(c.Request.Context(), f, p)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, output)
@@ -56,25 +76,24 @@ func (s *stop) All(c *gin.Context) {
 // @Tags stops
 // @Produce json
 // @Success 200 {object} model.TripStop "Модель пути"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /stops/{id} [get]
+// @Router /admin/stops/{id} [get]
 func (s *stop) ByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
 		return
 	}
 	output, err := s.s.ByID(c.Request.Context(), id)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
+	helper.SetETag(c, output.UpdatedAt)
 	c.JSON(http.StatusOK, output)
 }
 
@@ -87,26 +106,22 @@ func (s *stop) ByID(c *gin.Context) {
 // @Produce json
 // @Param input body model.TripStop true "Данные остановки"
 // @Success 201 {object} apperrors.SuccessResponse "Остановка успешно создана"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/stops [post]
 func (s *stop) Create(c *gin.Context) {
 	var input model.Stop
 	if err := c.ShouldBindJSON(&input); err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 	err := s.s.Create(c.Request.Context(), &input)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{
@@ -124,36 +139,30 @@ func (s *stop) Create(c *gin.Context) {
 // @Param id path int true "ID остановки"
 // @Param input body model.StopUpdate true "Обновленные данные остановки"
 // @Success 200 {object} apperrors.SuccessResponse "Данные остановки обновлены"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Водитель не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Водитель не найден"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/stops/{id} [put]
 func (s *stop) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
 		return
 	}
 	var input model.StopUpdate
 	if err = c.ShouldBindJSON(&input); err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 	input.ID = id
 	err = s.s.Update(c.Request.Context(), &input)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -161,6 +170,46 @@ func (s *stop) Update(c *gin.Context) {
 	})
 }
 
+// Patch частично обновляет остановку (JSON Merge Patch)
+// @Summary Частично обновить остановку (только админ)
+// @Description Применяет JSON Merge Patch (RFC 7396), null очищает адрес и координаты. Версию из ETag можно передать в If-Match: если остановку уже изменили, вернётся 412
+// @Security Bearer
+// @Tags admin/stops
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "ID остановки"
+// @Param If-Match header string false "ETag из ответа GET"
+// @Param input body model.StopPatch true "Изменяемые поля"
+// @Success 200 {object} model.StopPatch "Остановка после изменения, новая версия в ETag"
+// @Failure 400 {object} apperrors.Problem "Некорректный патч"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Остановка не найдена"
+// @Failure 412 {object} apperrors.Problem "Остановка изменена другим пользователем"
+// @Failure 415 {object} apperrors.Problem "Неподдерживаемый тип содержимого"
+// @Failure 422 {object} apperrors.Problem "Ошибка валидации"
+// @Router /admin/stops/{id} [patch]
+func (s *stop) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
+		return
+	}
+	patch, ok := helper.MergePatchBody(c)
+	if !ok {
+		return
+	}
+
+	output, err := s.s.Patch(c.Request.Context(), id, patch, helper.IfMatch(c))
+	if err != nil {
+		s.logger.Warnf("failed to patch stop %d: %v", id, err)
+		helper.Abort(c, err)
+		return
+	}
+	helper.SetETag(c, output.UpdatedAt)
+	c.JSON(http.StatusOK, output)
+}
+
 // Delete removes a stop by ID (Admin only)
 // @Summary Удалить остановки (только админ)
 // @Description Удаляет запись об остановке по ID (требуются права администратора)
@@ -169,29 +218,108 @@ func (s *stop) Update(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID остановки"
 // @Success 204 "Остановка успешно удалена"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Остановка не найдена"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Остановка не найдена"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/stops/{id} [delete]
 func (s *stop) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
 		return
 	}
 	err = s.s.Delete(c.Request.Context(), id)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
+		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"message": "success",
 	})
 }
+
+// Restore восстанавливает удалённую запись
+// @Summary Восстановить остановку
+// @Description Снимает отметку об удалении, пока запись не удалена окончательно
+// @Tags admin/stops
+// @Security Bearer
+// @Param id path int true "ID остановки"
+// @Success 204 "Запись восстановлена"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Запись не найдена среди удалённых"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/stops/{id}/restore [post]
+func (s *stop) Restore(c *gin.Context) {
+	restoreByID(c, s.logger, s.s.Restore, service.ErrStopNotFound, "Остановка не найдена среди удалённых")
+}
+
+// BulkCreate creates several stops at once (Admin only)
+// @Summary Создать несколько остановок (только админ)
+// @Description Проверяет все остановки и создаёт их в одной транзакции: при ошибке не создаётся ни одна. Индексы элементов с ошибками — в поле failed
+// @Security Bearer
+// @Tags admin/stops
+// @Accept json
+// @Produce json
+// @Param input body []model.Stop true "Остановки, не больше 500"
+// @Success 201 {object} model.BulkResult "ID созданных остановок в порядке запроса"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 409 {object} apperrors.Problem "Элемент конфликтует с существующими данными"
+// @Failure 422 {object} apperrors.Problem "Ошибки валидации элементов"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
+// @Router /admin/stops/bulk [post]
+func (s *stop) BulkCreate(c *gin.Context) {
+	input, ok := helper.BindBulk[*model.Stop](c)
+	if !ok {
+		return
+	}
+	ids, err := s.s.CreateMany(c.Request.Context(), input)
+	if err != nil {
+		s.logger.Error(err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, model.NewBulkResult(ids))
+}
+
+// BulkUpdate updates several stops at once (Admin only)
+// @Summary Обновить несколько остановок (только админ)
+// @Description Проверяет все остановки и обновляет их в одной транзакции: при ошибке не меняется ни одна. Индексы элементов с ошибками — в поле failed
+// @Security Bearer
+// @Tags admin/stops
+// @Accept json
+// @Produce json
+// @Param input body []model.BulkUpdate[model.StopUpdate] true "ID остановок и изменяемые поля, не больше 500"
+// @Success 200 {object} model.BulkResult "ID обновлённых остановок в порядке запроса"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Остановка не найдена"
+// @Failure 422 {object} apperrors.Problem "Ошибки валидации элементов"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
+// @Router /admin/stops/bulk [put]
+func (s *stop) BulkUpdate(c *gin.Context) {
+	input, ok := helper.BindBulk[model.BulkUpdate[model.StopUpdate]](c)
+	if !ok {
+		return
+	}
+	stops := make([]*model.StopUpdate, len(input))
+	ids := make([]int, len(input))
+	for i := range input {
+		input[i].Data.ID = input[i].ID
+		stops[i], ids[i] = &input[i].Data, input[i].ID
+	}
+	if err := s.s.UpdateMany(c.Request.Context(), stops); err != nil {
+		s.logger.Error(err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.NewBulkResult(ids))
+}
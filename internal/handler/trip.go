@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
@@ -26,17 +28,29 @@ func NewTrip(logger *logger.Logger, s service.Trip) *Trip {
 // @Description Возвращает список всех маршрутов в системе
 // @Tags trips
 // @Produce json
-// @Success 200 {array} model.Trip "Список маршрутов"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /trips [get]
+// @Param limit query int false "Размер страницы, по умолчанию 20, не больше 100"
+// @Param page query int false "Номер страницы, в ответе появится total"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Поле сортировки: id, start_time, end_time, base_price. С '-' — по убыванию"
+// @Param filter query string false "Фильтр вида filter[поле][оператор]=значение, например filter[bus_id]=3. Поля: id, bus_id, driver_id, status, start_time, end_time, base_price. Операторы: eq (по умолчанию), ne, gt, gte, lt, lte, in (через запятую), contains, null"
+// @Success 200 {object} paging.Page[model.TripResponse] "Страница маршрутов"
+// @Failure 400 {object} apperrors.Problem "Некорректные параметры списка"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /trips/all [get]
 func (h *Trip) All(c *gin.Context) {
 	h.logger.Debug("trips all")
-	trips, err := h.s.All(c.Request.Context())
+	p, ok := helper.PagingParams(c)
+	if !ok {
+		return
+	}
+	f, ok := helper.FilterParams(c)
+	if !ok {
+		return
+	}
+	trips, err := h.s.All(c.Request.Context(), f, p)
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, trips)
@@ -47,17 +61,29 @@ func (h *Trip) All(c *gin.Context) {
 // @Description Возвращает онимичную модель для отображения пользователю информации о маршрутах и их остановках
 // @Tags trips
 // @Produce json
-// @Success 200 {object} model.TripShortInfo "Модель остановок"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Param limit query int false "Размер страницы, по умолчанию 20, не больше 100"
+// @Param page query int false "Номер страницы, в ответе появится total"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Поле сортировки: trip_id, start_time, base_price. С '-' — по убыванию"
+// @Param filter query string false "Фильтр вида filter[поле][оператор]=значение, например filter[bus_id]=3. Поля: trip_id, bus_id, driver_id, status, start_time, end_time, base_price. Операторы: eq (по умолчанию), ne, gt, gte, lt, lte, in (через запятую), contains, null"
+// @Success 200 {object} paging.Page[model.TripShortInfo] "Страница маршрутов"
+// @Failure 400 {object} apperrors.Problem "Некорректные параметры списка"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /trips [get]
 func (h *Trip) AllShort(c *gin.Context) {
 	h.logger.Debug("TripStops AllShort")
-	all, err := h.s.AllShort(c.Request.Context())
+	p, ok := helper.PagingParams(c)
+	if !ok {
+		return
+	}
+	f, ok := helper.FilterParams(c)
+	if !ok {
+		return
+	}
+	all, err := h.s.AllShort(c.Request.Context(), f, p)
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, all)
@@ -70,26 +96,24 @@ func (h *Trip) AllShort(c *gin.Context) {
 // @Tags trips
 // @Produce json
 // @Success 200 {object} model.Trip "Модель пути"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /trips/{id} [get]
 func (h *Trip) ByID(c *gin.Context) {
 	h.logger.Debug("trips by id")
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
 		return
 	}
 	trip, err := h.s.ById(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
+	// ответ включает автобус, водителя и остановки, поэтому updated_at рейса
+	// не версия ответа: ETag по телу выставит кеш
 	c.JSON(http.StatusOK, trip)
 }
 
@@ -102,10 +126,10 @@ func (h *Trip) ByID(c *gin.Context) {
 // @Produce json
 // @Param input body model.Trip true "Данные маршрута"
 // @Success 201 {object} apperrors.SuccessResponse "Маршрут успешно создан"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/trips [post]
 func (h *Trip) Create(c *gin.Context) {
 	h.logger.Debug("trips create")
@@ -113,17 +137,13 @@ func (h *Trip) Create(c *gin.Context) {
 	err := c.ShouldBindJSON(&trip)
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 	err = h.s.Create(c.Request.Context(), &trip)
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, trip)
@@ -137,40 +157,80 @@ func (h *Trip) Create(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "ID маршрута"
+// @Param If-Match header string false "ETag из ответа PUT или PATCH"
 // @Param input body model.TripUpdate true "Обновленные данные маршрута"
-// @Success 200 {object} apperrors.SuccessResponse "Данные маршрута обновлены"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Водитель не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Success 200 {object} apperrors.SuccessResponse "Данные маршрута обновлены, новая версия в ETag"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Рейс не найден"
+// @Failure 412 {object} apperrors.Problem "Рейс изменён другим пользователем"
+// @Failure 422 {object} apperrors.Problem "Время окончания не позже времени начала с учётом сохранённых значений"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/trips/{id} [put]
 func (h *Trip) Update(c *gin.Context) {
 	h.logger.Debug("trips update")
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
+		return
 	}
 	var trip model.TripUpdate
 	err = c.ShouldBindJSON(&trip)
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 	trip.ID = id
-	err = h.s.Update(c.Request.Context(), &trip)
+	version, err := h.s.Update(c.Request.Context(), &trip, helper.IfMatch(c))
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
+		return
+	}
+	helper.SetETag(c, version)
+	c.JSON(http.StatusOK, trip)
+}
+
+// Patch частично обновляет рейс (JSON Merge Patch)
+// @Summary Частично обновить маршрут (только админ)
+// @Description Применяет JSON Merge Patch (RFC 7396), null в end_time снимает время окончания. Версию из ETag ответа PUT или PATCH можно передать в If-Match: если рейс уже изменили, вернётся 412. ETag ответа GET /trips/{id} включает автобус, водителя и остановки и для If-Match не подходит
+// @Security Bearer
+// @Tags admin/trips
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "ID маршрута"
+// @Param If-Match header string false "ETag из ответа PUT или PATCH"
+// @Param input body model.TripPatch true "Изменяемые поля"
+// @Success 200 {object} model.TripPatch "Рейс после изменения, новая версия в ETag"
+// @Failure 400 {object} apperrors.Problem "Некорректный патч"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Рейс не найден"
+// @Failure 412 {object} apperrors.Problem "Рейс изменён другим пользователем"
+// @Failure 415 {object} apperrors.Problem "Неподдерживаемый тип содержимого"
+// @Failure 422 {object} apperrors.Problem "Ошибка валидации"
+// @Router /admin/trips/{id} [patch]
+func (h *Trip) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
+		return
+	}
+	patch, ok := helper.MergePatchBody(c)
+	if !ok {
+		return
+	}
+
+	trip, err := h.s.Patch(c.Request.Context(), id, patch, helper.IfMatch(c))
+	if err != nil {
+		h.logger.Warnf("failed to patch trip %d: %v", id, err)
+		helper.Abort(c, err)
+		return
 	}
+	helper.SetETag(c, trip.UpdatedAt)
 	c.JSON(http.StatusOK, trip)
 }
 
@@ -182,31 +242,25 @@ func (h *Trip) Update(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID маршрута"
 // @Success 204 "Маршрут успешно удален"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Маршрут не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Маршрут не найден"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/trips/{id} [delete]
 func (h *Trip) Delete(c *gin.Context) {
 	h.logger.Debug("trips delete")
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
 		return
 	}
 	err = h.s.Delete(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
-	c.JSON(http.StatusNoContent, gin.H{
-		"message": "ok",
-	})
+	c.Status(http.StatusNoContent)
 }
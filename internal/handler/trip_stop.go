@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
@@ -14,6 +16,8 @@ type TripStop interface {
 	ByID(c *gin.Context)
 	Create(c *gin.Context)
 	Update(c *gin.Context)
+	BulkCreate(c *gin.Context)
+	BulkUpdate(c *gin.Context)
 	Delete(c *gin.Context)
 }
 
@@ -34,17 +38,29 @@ func NewTripStop(logger *logger.Logger, s service.TripStop) TripStop {
 // @Description Возвращает все остановки в системе
 // @Tags trip_stops
 // @Produce json
-// @Success 200 {object} model.TripStop "Модель остановок"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Param limit query int false "Размер страницы, по умолчанию 20, не больше 100"
+// @Param page query int false "Номер страницы, в ответе появится total"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Поле сортировки: id, trip_id, arrival_time. С '-' — по убыванию"
+// @Param filter query string false "Фильтр вида filter[поле][оператор]=значение, например filter[trip_id]=3. Поля: id, trip_id, stop_id, stop_order, arrival_time, departure_time, price_to_next. Операторы: eq (по умолчанию), ne, gt, gte, lt, lte, in (через запятую), null"
+// @Success 200 {object} paging.Page[model.TripStop] "Страница остановок маршрутов"
+// @Failure 400 {object} apperrors.Problem "Некорректные параметры списка"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /trip_stops [get]
 func (s *tripStop) All(c *gin.Context) {
 	s.logger.Debug("TripStops All")
-	all, err := s.s.All(c.Request.Context())
+	p, ok := helper.PagingParams(c)
+	if !ok {
+		return
+	}
+	f, ok := helper.FilterParams(c)
+	if !ok {
+		return
+	}
+	all, err := s.s.All(c.Request.Context(), f, p)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, all)
@@ -57,23 +73,19 @@ func (s *tripStop) All(c *gin.Context) {
 // @Tags trip_stops
 // @Produce json
 // @Success 200 {object} model.TripStop "Модель пути"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /trip_stops/{id} [get]
 func (s *tripStop) ByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
 		return
 	}
 	output, err := s.s.ByID(c.Request.Context(), id)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -89,27 +101,23 @@ func (s *tripStop) ByID(c *gin.Context) {
 // @Produce json
 // @Param input body model.TripStop true "Данные остановки"
 // @Success 201 {object} apperrors.SuccessResponse "Остановка успешно создана"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/trip_stops [post]
 func (s *tripStop) Create(c *gin.Context) {
 	s.logger.Debug("TripStops Create")
 	var stop model.TripStop
 	if err := c.ShouldBindJSON(&stop); err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 	err := s.s.Create(c.Request.Context(), &stop)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -127,37 +135,31 @@ func (s *tripStop) Create(c *gin.Context) {
 // @Param id path int true "ID остановки"
 // @Param input body model.TripStopUpdate true "Обновленные данные остановки"
 // @Success 200 {object} apperrors.SuccessResponse "Данные остановки обновлены"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Водитель не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Водитель не найден"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/trip_stops/{id} [put]
 func (s *tripStop) Update(c *gin.Context) {
 	s.logger.Debug("TripStops Update")
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
 		return
 	}
 	var stop model.TripStopUpdate
 	if err = c.ShouldBindJSON(&stop); err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 	stop.ID = id
 	err = s.s.Update(c.Request.Context(), &stop)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -173,31 +175,92 @@ func (s *tripStop) Update(c *gin.Context) {
 // @Produce json
 // @Param id path int true "ID остановки"
 // @Success 204 "Остановка успешно удалена"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Остановка не найдена"
-// @Failure 500 {object} apperrors.ErrorResponse "Ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Остановка не найдена"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
 // @Router /admin/trip_stops/{id} [delete]
 func (s *tripStop) Delete(c *gin.Context) {
 	s.logger.Debug("TripStops Delete")
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID"))
 		return
 	}
 	err = s.s.Delete(c.Request.Context(), id)
 	if err != nil {
 		s.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		helper.Abort(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"message": "success",
 	})
 }
+
+// BulkCreate creates several trip stops at once (Admin only)
+// @Summary Создать несколько остановок рейсов (только админ)
+// @Description Проверяет все остановки рейсов и создаёт их в одной транзакции: при ошибке не создаётся ни одна. Индексы элементов с ошибками — в поле failed
+// @Security Bearer
+// @Tags admin/trip_stops
+// @Accept json
+// @Produce json
+// @Param input body []model.TripStop true "Остановки рейсов, не больше 500"
+// @Success 201 {object} model.BulkResult "ID созданных остановок рейсов в порядке запроса"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 409 {object} apperrors.Problem "Элемент ссылается на несуществующий рейс или остановку"
+// @Failure 422 {object} apperrors.Problem "Ошибки валидации элементов"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
+// @Router /admin/trip_stops/bulk [post]
+func (t *tripStop) BulkCreate(c *gin.Context) {
+	input, ok := helper.BindBulk[*model.TripStop](c)
+	if !ok {
+		return
+	}
+	ids, err := t.s.CreateMany(c.Request.Context(), input)
+	if err != nil {
+		t.logger.Error(err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, model.NewBulkResult(ids))
+}
+
+// BulkUpdate updates several trip stops at once (Admin only)
+// @Summary Обновить несколько остановок рейсов (только админ)
+// @Description Проверяет все остановки рейсов и обновляет их в одной транзакции: при ошибке не меняется ни одна. Индексы элементов с ошибками — в поле failed
+// @Security Bearer
+// @Tags admin/trip_stops
+// @Accept json
+// @Produce json
+// @Param input body []model.BulkUpdate[model.TripStopUpdate] true "ID остановок рейсов и изменяемые поля, не больше 500"
+// @Success 200 {object} model.BulkResult "ID обновлённых остановок рейсов в порядке запроса"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Остановка рейса не найдена"
+// @Failure 422 {object} apperrors.Problem "Ошибки валидации элементов"
+// @Failure 500 {object} apperrors.Problem "Ошибка сервера"
+// @Router /admin/trip_stops/bulk [put]
+func (t *tripStop) BulkUpdate(c *gin.Context) {
+	input, ok := helper.BindBulk[model.BulkUpdate[model.TripStopUpdate]](c)
+	if !ok {
+		return
+	}
+	tripStops := make([]*model.TripStopUpdate, len(input))
+	ids := make([]int, len(input))
+	for i := range input {
+		input[i].Data.ID = input[i].ID
+		tripStops[i], ids[i] = &input[i].Data, input[i].ID
+	}
+	if err := t.s.UpdateMany(c.Request.Context(), tripStops); err != nil {
+		t.logger.Error(err)
+		helper.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, model.NewBulkResult(ids))
+}
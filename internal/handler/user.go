@@ -2,6 +2,7 @@ package handler
 
 import (
 	"corpord-api/internal/apperrors"
+	"corpord-api/internal/handler/helper"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/service"
 	"corpord-api/model"
@@ -25,68 +26,80 @@ func NewUser(logger *logger.Logger, s service.User) *UserHandler {
 	}
 }
 
-// All возвращает список всех пользователей
-// @Summary Получить всех пользователей
-// @Description Возвращает список всех зарегистрированных пользователей (только для администраторов)
-// @Tags users
+// Search ищет пользователей
+// @Summary Поиск пользователей
+// @Description Ищет пользователей по подстроке email или имени, роли и признаку блокировки. Результаты отдаются постранично (только для администраторов)
+// @Tags admin
 // @Produce json
 // @Security Bearer
-// @Success 200 {array} model.UserResponse "Список пользователей"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /users [get]
-func (h *UserHandler) All(c *gin.Context) {
-	start := time.Now()
-	h.logger.Info("handling get all users request")
+// @Param q query string false "Подстрока email или имени"
+// @Param email query string false "Подстрока email"
+// @Param name query string false "Подстрока имени"
+// @Param role query string false "Имя роли"
+// @Param blocked query bool false "Только заблокированные (true) или только активные (false)"
+// @Param limit query int false "Размер страницы, по умолчанию 20, не больше 100"
+// @Param page query int false "Номер страницы, в ответе появится total"
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Param sort query string false "Поле сортировки: id, email, name, created_at. С '-' — по убыванию"
+// @Param filter query string false "Фильтр вида filter[поле][оператор]=значение, например filter[role_id]=2 или filter[blocked_at][null]=false. Поля: id, role_id, email_verified, is_service_account, preferred_language, created_at, blocked_at. Операторы: eq (по умолчанию), ne, gt, gte, lt, lte, in (через запятую), contains, null"
+// @Param include_deleted query bool false "Вернуть и удалённых пользователей"
+// @Success 200 {object} paging.Page[model.UserResponse] "Страница пользователей"
+// @Failure 400 {object} apperrors.Problem "Некорректные параметры"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users [get]
+func (h *UserHandler) Search(c *gin.Context) {
+	var search model.UserSearch
+	if err := c.ShouldBindQuery(&search); err != nil {
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+	p, ok := helper.PagingParams(c)
+	if !ok {
+		return
+	}
+	f, ok := helper.FilterParams(c)
+	if !ok {
+		return
+	}
 
-	users, err := h.s.GetAll(c.Request.Context())
+	list, err := h.s.Search(c.Request.Context(), &search, f, p)
 	if err != nil {
-		h.logger.Errorf("failed to get users: %v", err)
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось получить список пользователей",
-		})
+		h.logger.Errorf("failed to search users: %v", err)
+		helper.Abort(c, err)
 		return
 	}
 
-	h.logger.Infof("retrieved %d users in %v", len(users), time.Since(start))
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, list)
 }
 
 // Get возвращает пользователя по ID
 // @Summary Получить пользователя по ID
-// @Description Возвращает информацию о пользователе по его идентификатору
+// @Description Возвращает информацию о пользователе по его идентификатору. Пользователь может получить только свои данные, чужие доступны с правом users:read
 // @Tags users
 // @Produce json
 // @Security Bearer
 // @Param id path int true "ID пользователя"
 // @Success 200 {object} model.UserResponse "Данные пользователя"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Пользователь не найден"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Param include_deleted query bool false "Вернуть и удалённые записи (учитывается только в /admin)"
 // @Router /users/{id} [get]
+// @Router /admin/users/{id} [get]
 func (h *UserHandler) Get(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID пользователя",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
 		return
 	}
 
 	user, err := h.s.GetByID(c.Request.Context(), id)
 	if err != nil {
 		h.logger.Errorf("failed to get user %d: %v", id, err)
-		if errors.Is(err, service.ErrUserNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{
-				Error: "Пользователь не найден",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось получить данные пользователя",
-		})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -102,33 +115,25 @@ func (h *UserHandler) Get(c *gin.Context) {
 // @Security Bearer
 // @Param input body model.UserCreate true "Данные пользователя"
 // @Success 201 {object} model.UserResponse "Пользователь успешно создан"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 409 {object} apperrors.ErrorResponse "Пользователь с таким email уже существует"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 409 {object} apperrors.Problem "Пользователь с таким email уже существует"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/users [post]
+// @Router /users [post]
 func (h *UserHandler) Create(c *gin.Context) {
 	var input model.UserCreate
 	if err := c.ShouldBindJSON(&input); err != nil {
 		h.logger.Warnf("invalid request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректные данные",
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	createdUser, err := h.s.Create(c.Request.Context(), &input)
 	if err != nil {
 		h.logger.Errorf("failed to create user: %v", err)
-		if errors.Is(err, service.ErrEmailExists) {
-			c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-				Error: "Пользователь с таким email уже существует",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось создать пользователя",
-		})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -145,42 +150,30 @@ func (h *UserHandler) Create(c *gin.Context) {
 // @Param id path int true "ID пользователя"
 // @Param input body model.UserUpdate true "Обновленные данные пользователя"
 // @Success 200 {object} model.UserResponse "Данные пользователя обновлены"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректные данные"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Пользователь не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /admin/users/{id} [put]
 func (h *UserHandler) Update(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID пользователя",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
 		return
 	}
 
 	var update model.UserUpdate
 	if err := c.ShouldBindJSON(&update); err != nil {
 		h.logger.Warnf("invalid request body: %v", err)
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректные данные для обновления",
-		})
+		helper.Abort(c, helper.BindingError(err))
 		return
 	}
 
 	updatedUser, err := h.s.Update(c.Request.Context(), id, &update)
 	if err != nil {
 		h.logger.Errorf("failed to update user %d: %v", id, err)
-		if errors.Is(err, service.ErrUserNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{
-				Error: "Пользователь не найден",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось обновить данные пользователя",
-		})
+		helper.Abort(c, err)
 		return
 	}
 
@@ -189,44 +182,68 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 // Delete удаляет пользователя
 // @Summary Удалить пользователя
-// @Description Удаляет учетную запись пользователя по ID (только для администраторов)
+// @Description Удаляет учетную запись пользователя по ID. Пользователь может удалить только собственный аккаунт, чужие удаляются с правом users:manage, если у роли пользователя нет прав сверх прав администратора
 // @Tags users
 // @Produce json
 // @Security Bearer
 // @Param id path int true "ID пользователя"
 // @Success 204 "Пользователь успешно удален"
-// @Failure 400 {object} apperrors.ErrorResponse "Некорректный ID"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 403 {object} apperrors.ErrorResponse "Доступ запрещен"
-// @Failure 404 {object} apperrors.ErrorResponse "Пользователь не найден"
-// @Failure 500 {object} apperrors.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
 // @Router /users/{id} [delete]
 func (h *UserHandler) Delete(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(apperrors.ErrBadRequest.Status, apperrors.ErrorResponse{
-			Error: "Некорректный ID пользователя",
-		})
+		helper.Abort(c, apperrors.ErrBadRequest.WithDetail("Некорректный ID пользователя"))
+		return
+	}
+	claims, ok := helper.CurrentClaims(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
 		return
 	}
 
-	if err := h.s.Delete(c.Request.Context(), id); err != nil {
+	if err := h.s.Remove(c.Request.Context(), id, claims); err != nil {
 		h.logger.Errorf("failed to delete user %d: %v", id, err)
-		if errors.Is(err, service.ErrUserNotFound) {
-			c.JSON(apperrors.ErrNotFound.Status, apperrors.ErrorResponse{
-				Error: "Пользователь не найден",
-			})
-			return
-		}
-		c.JSON(apperrors.ErrInternal.Status, apperrors.ErrorResponse{
-			Error: "Не удалось удалить пользователя",
-		})
+		helper.Abort(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+// DeleteMe удаляет аккаунт текущего пользователя
+// @Summary Удалить свой аккаунт
+// @Description Помечает аккаунт удалённым и завершает все сессии. Персональные данные обезличиваются позже фоновой задачей
+// @Tags users
+// @Security Bearer
+// @Success 204 "Аккаунт удален"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Недоступно при входе от имени пользователя"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me [delete]
+func (h *UserHandler) DeleteMe(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	if err := h.s.Delete(c.Request.Context(), userID); err != nil {
+		h.logger.Errorf("failed to delete own account %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	// Убираем cookie
+	c.SetCookie("refresh_token", "", -1, "/", "", true, true)
+	c.Status(http.StatusNoContent)
+}
+
 // Me возвращает информацию о текущем пользователе
 // @Summary Получить информацию о текущем пользователе
 // @Description Возвращает информацию о текущем аутентифицированном пользователе
@@ -234,8 +251,8 @@ func (h *UserHandler) Delete(c *gin.Context) {
 // @Produce json
 // @Security Bearer
 // @Success 200 {object} model.UserResponse "Данные пользователя"
-// @Failure 401 {object} apperrors.ErrorResponse "Не авторизован"
-// @Failure 404 {object} apperrors.ErrorResponse "Пользователь не найден"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
 // @Router /me [get]
 func (h *UserHandler) Me(c *gin.Context) {
 	start := time.Now()
@@ -245,9 +262,7 @@ func (h *UserHandler) Me(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
 		h.logger.Error("user ID not found in context")
-		c.JSON(http.StatusUnauthorized, apperrors.ErrorResponse{
-			Error: "Требуется аутентификация",
-		})
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
 		return
 	}
 
@@ -255,9 +270,7 @@ func (h *UserHandler) Me(c *gin.Context) {
 	id, ok := userID.(int)
 	if !ok {
 		h.logger.Error("invalid user ID type in context")
-		c.JSON(http.StatusInternalServerError, apperrors.ErrorResponse{
-			Error: "Ошибка сервера",
-		})
+		helper.Abort(c, apperrors.ErrInternal)
 		return
 	}
 
@@ -266,17 +279,68 @@ func (h *UserHandler) Me(c *gin.Context) {
 	if err != nil {
 		h.logger.Errorf("failed to get user: %v", err)
 		if errors.Is(err, apperrors.ErrNotFound) {
-			c.JSON(http.StatusNotFound, apperrors.ErrorResponse{
-				Error: "Пользователь не найден",
-			})
+			helper.Abort(c, apperrors.ErrNotFound.WithDetail("Пользователь не найден"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, apperrors.ErrorResponse{
-			Error: "Не удалось получить информацию о пользователе",
-		})
+		helper.Abort(c, err)
 		return
 	}
 
 	h.logger.Infof("user data retrieved in %v", time.Since(start))
 	c.JSON(http.StatusOK, user)
 }
+
+// UpdateMe обновляет профиль текущего пользователя
+// @Summary Обновить профиль
+// @Description Обновляет имя и предпочитаемый язык текущего пользователя. Телефон меняется через подтверждение кодом
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param input body model.ProfileUpdate true "Данные профиля"
+// @Success 200 {object} model.UserResponse "Профиль обновлен"
+// @Failure 400 {object} apperrors.Problem "Некорректные данные"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 404 {object} apperrors.Problem "Пользователь не найден"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /users/me [patch]
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	userID, ok := helper.CurrentUserID(c)
+	if !ok {
+		helper.Abort(c, apperrors.ErrUnauthorized.WithDetail("Требуется аутентификация"))
+		return
+	}
+
+	var input model.ProfileUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		h.logger.Warnf("invalid request body: %v", err)
+		helper.Abort(c, helper.BindingError(err))
+		return
+	}
+
+	user, err := h.s.UpdateProfile(c.Request.Context(), userID, &input)
+	if err != nil {
+		h.logger.Errorf("failed to update profile of user %d: %v", userID, err)
+		helper.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// Restore восстанавливает удалённую запись
+// @Summary Восстановить пользователя
+// @Description Снимает отметку об удалении, пока запись не удалена окончательно
+// @Tags admin
+// @Security Bearer
+// @Param id path int true "ID пользователя"
+// @Success 204 "Запись восстановлена"
+// @Failure 400 {object} apperrors.Problem "Некорректный ID"
+// @Failure 401 {object} apperrors.Problem "Не авторизован"
+// @Failure 403 {object} apperrors.Problem "Доступ запрещен"
+// @Failure 404 {object} apperrors.Problem "Запись не найдена среди удалённых"
+// @Failure 500 {object} apperrors.Problem "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/restore [post]
+func (h *UserHandler) Restore(c *gin.Context) {
+	restoreByID(c, h.logger, h.s.Restore, service.ErrUserNotFound, "Пользователь не найден среди удалённых")
+}
@@ -0,0 +1,58 @@
+// Package httpcache хранит готовые ответы публичных GET-маршрутов.
+// Записи помечаются тегами ресурсов и сбрасываются по тегу при изменении данных.
+package httpcache
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Entry сохранённый ответ
+type Entry struct {
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+	Body        []byte `json:"body"`
+}
+
+// Store хранит ответы. Get возвращает nil без ошибки, если ответа нет.
+//
+// У каждого тега есть поколение, Invalidate его увеличивает. Generation читается
+// до того, как обработчик пойдёт в базу, и передаётся в Set: если теги за это
+// время сбросили, ответ мог собраться из старых данных и не сохраняется
+type Store interface {
+	Get(ctx context.Context, key string) (*Entry, error)
+	Generation(ctx context.Context, tags ...string) (string, error)
+	Set(ctx context.Context, key string, e *Entry, ttl time.Duration, gen string, tags ...string) error
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+// New создаёт хранилище по настройкам: redis для нескольких инстансов, memory для одного
+func New(cfg *config.HTTPCache, rdb *redis.Client) (Store, error) {
+	switch cfg.Store {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "", "redis":
+		if rdb == nil {
+			return nil, fmt.Errorf("httpcache: redis store requires redis client")
+		}
+		return NewRedisStore(rdb), nil
+	default:
+		return nil, fmt.Errorf("httpcache: unknown store %q", cfg.Store)
+	}
+}
+
+// joinGeneration собирает поколение набора тегов в одну строку.
+// Формат общий для хранилищ: счётчики тегов через запятую
+func joinGeneration(counters []int64) string {
+	parts := make([]string, len(counters))
+	for i, n := range counters {
+		parts[i] = strconv.FormatInt(n, 10)
+	}
+	return strings.Join(parts, ",")
+}
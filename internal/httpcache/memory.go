@@ -0,0 +1,132 @@
+package httpcache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	entry   *Entry
+	expires time.Time
+	tags    []string
+}
+
+// MemoryStore хранит ответы в памяти процесса. Подходит для одного инстанса
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	tags      map[string]map[string]struct{}
+	gens      map[string]int64
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:   make(map[string]*memoryEntry),
+		tags:      make(map[string]map[string]struct{}),
+		gens:      make(map[string]int64),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	if !s.now().Before(e.expires) {
+		s.remove(key)
+		return nil, nil
+	}
+	return e.entry, nil
+}
+
+func (s *MemoryStore) Generation(_ context.Context, tags ...string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.generation(tags), nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, e *Entry, ttl time.Duration, gen string, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.generation(tags) != gen {
+		return nil
+	}
+
+	now := s.now()
+	s.sweep(now)
+
+	s.remove(key)
+	s.entries[key] = &memoryEntry{entry: e, expires: now.Add(ttl), tags: tags}
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			s.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Invalidate(_ context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		s.gens[tag]++
+		for key := range s.tags[tag] {
+			s.remove(key)
+		}
+		delete(s.tags, tag)
+	}
+	return nil
+}
+
+func (s *MemoryStore) generation(tags []string) string {
+	counters := make([]int64, len(tags))
+	for i, tag := range tags {
+		counters[i] = s.gens[tag]
+	}
+	return joinGeneration(counters)
+}
+
+// remove удаляет ответ и его упоминания в тегах
+func (s *MemoryStore) remove(key string) {
+	e, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	delete(s.entries, key)
+	for _, tag := range e.tags {
+		delete(s.tags[tag], key)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
+
+// sweep удаляет просроченные ответы, чтобы карта не росла бесконечно
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			s.remove(key)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package httpcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix = "httpcache:"
+	redisTagPrefix = "httpcache:tag:"
+	redisGenPrefix = "httpcache:gen:"
+)
+
+// Ответ сохраняется, только если поколение тегов не изменилось с чтения.
+// Ключ добавляется в множество каждого тега, множество живёт не меньше самого
+// долгого ответа в нём. KEYS: ответ, n счётчиков поколений, n множеств тегов.
+// ARGV: поколение, тело, ttl в мс, ключ ответа без префикса
+var setScript = redis.NewScript(`
+local n = (#KEYS - 1) / 2
+local gen = {}
+for i = 1, n do
+	gen[i] = redis.call('GET', KEYS[1 + i]) or '0'
+end
+if table.concat(gen, ',') ~= ARGV[1] then
+	return 0
+end
+local ttl = tonumber(ARGV[3])
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ttl)
+for i = 1, n do
+	local tag = KEYS[1 + n + i]
+	redis.call('SADD', tag, ARGV[4])
+	if redis.call('PTTL', tag) < ttl then
+		redis.call('PEXPIRE', tag, ttl)
+	end
+end
+return 1
+`)
+
+// Сброс тега одним скриптом: поколение растёт, ответы из множества удаляются
+// вместе с ним. KEYS: пары счётчик поколения и множество тега. ARGV: префикс ответов
+var invalidateScript = redis.NewScript(`
+for i = 1, #KEYS, 2 do
+	redis.call('INCR', KEYS[i])
+	for _, key in ipairs(redis.call('SMEMBERS', KEYS[i + 1])) do
+		redis.call('DEL', ARGV[1] .. key)
+	end
+	redis.call('DEL', KEYS[i + 1])
+end
+return 1
+`)
+
+// RedisStore хранит ответы в Redis и разделяет кеш между инстансами.
+// Для каждого тега ведётся множество ключей, которые сбрасываются вместе с ним,
+// и счётчик поколения без срока жизни
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (*Entry, error) {
+	raw, err := s.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var e Entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (s *RedisStore) Generation(ctx context.Context, tags ...string) (string, error) {
+	if len(tags) == 0 {
+		return "", nil
+	}
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = redisGenPrefix + tag
+	}
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return "", err
+	}
+
+	counters := make([]int64, len(values))
+	for i, v := range values {
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if counters[i], err = strconv.ParseInt(str, 10, 64); err != nil {
+			return "", err
+		}
+	}
+	return joinGeneration(counters), nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, e *Entry, ttl time.Duration, gen string, tags ...string) error {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 1, 1+2*len(tags))
+	keys[0] = redisKeyPrefix + key
+	for _, tag := range tags {
+		keys = append(keys, redisGenPrefix+tag)
+	}
+	for _, tag := range tags {
+		keys = append(keys, redisTagPrefix+tag)
+	}
+	return setScript.Run(ctx, s.client, keys, gen, raw, ttl.Milliseconds(), key).Err()
+}
+
+func (s *RedisStore) Invalidate(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, 2*len(tags))
+	for _, tag := range tags {
+		keys = append(keys, redisGenPrefix+tag, redisTagPrefix+tag)
+	}
+	return invalidateScript.Run(ctx, s.client, keys, redisKeyPrefix).Err()
+}
@@ -0,0 +1,186 @@
+package httpcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func testStores(t *testing.T) map[string]Store {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"redis":  NewRedisStore(client),
+	}
+}
+
+func mustSet(t *testing.T, s Store, key string, e *Entry, tags ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+	gen, err := s.Generation(ctx, tags...)
+	if err != nil {
+		t.Fatalf("Generation: %v", err)
+	}
+	if err := s.Set(ctx, key, e, time.Minute, gen, tags...); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+}
+
+func mustGet(t *testing.T, s Store, key string) *Entry {
+	t.Helper()
+
+	e, err := s.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	return e
+}
+
+func TestStoreInvalidate(t *testing.T) {
+	ctx := context.Background()
+
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			mustSet(t, s, "trips:/trips/1", &Entry{ContentType: "application/json", ETag: `"a"`, Body: []byte(`{}`)}, "trips")
+			mustSet(t, s, "stops:/stops", &Entry{ETag: `"b"`, Body: []byte(`[]`)}, "stops", "trips")
+
+			if e := mustGet(t, s, "trips:/trips/1"); e == nil || e.ETag != `"a"` || e.ContentType != "application/json" {
+				t.Fatalf("Get = %+v, want stored entry", e)
+			}
+
+			if err := s.Invalidate(ctx, "catalog"); err != nil {
+				t.Fatalf("Invalidate: %v", err)
+			}
+			if mustGet(t, s, "trips:/trips/1") == nil {
+				t.Fatal("unrelated tag dropped the entry")
+			}
+
+			if err := s.Invalidate(ctx, "trips"); err != nil {
+				t.Fatalf("Invalidate: %v", err)
+			}
+			for _, key := range []string{"trips:/trips/1", "stops:/stops"} {
+				if e := mustGet(t, s, key); e != nil {
+					t.Fatalf("%s survived invalidation: %+v", key, e)
+				}
+			}
+		})
+	}
+}
+
+func TestStoreRejectsFillStartedBeforeInvalidate(t *testing.T) {
+	ctx := context.Background()
+
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			// обработчик прочитал поколение и старые данные, затем админ изменил рейс
+			stale, err := s.Generation(ctx, "trips")
+			if err != nil {
+				t.Fatalf("Generation: %v", err)
+			}
+			if err := s.Invalidate(ctx, "trips"); err != nil {
+				t.Fatalf("Invalidate: %v", err)
+			}
+			if err := s.Set(ctx, "trips:/trips/1", &Entry{Body: []byte("old")}, time.Minute, stale, "trips"); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			if e := mustGet(t, s, "trips:/trips/1"); e != nil {
+				t.Fatalf("stale fill stored: %q", e.Body)
+			}
+
+			mustSet(t, s, "trips:/trips/1", &Entry{Body: []byte("new")}, "trips")
+			if e := mustGet(t, s, "trips:/trips/1"); e == nil || string(e.Body) != "new" {
+				t.Fatalf("Get = %+v, want fresh fill", e)
+			}
+		})
+	}
+}
+
+func TestStoreGenerationPerTag(t *testing.T) {
+	ctx := context.Background()
+
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			before, err := s.Generation(ctx, "stops", "trips")
+			if err != nil {
+				t.Fatalf("Generation: %v", err)
+			}
+			if err := s.Invalidate(ctx, "catalog"); err != nil {
+				t.Fatalf("Invalidate: %v", err)
+			}
+			if after, _ := s.Generation(ctx, "stops", "trips"); after != before {
+				t.Fatalf("generation changed by unrelated tag: %q -> %q", before, after)
+			}
+			if err := s.Invalidate(ctx, "trips"); err != nil {
+				t.Fatalf("Invalidate: %v", err)
+			}
+			if after, _ := s.Generation(ctx, "stops", "trips"); after == before {
+				t.Fatalf("generation %q not bumped by invalidation", after)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	if err := s.Set(ctx, "k", &Entry{Body: []byte("x")}, time.Minute, ""); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	now = now.Add(59 * time.Second)
+	if mustGet(t, s, "k") == nil {
+		t.Fatal("entry expired early")
+	}
+	now = now.Add(time.Second)
+	if e := mustGet(t, s, "k"); e != nil {
+		t.Fatalf("expired entry returned: %+v", e)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	if err := s.Set(ctx, "old", &Entry{}, time.Second, "", "trips"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	now = now.Add(memorySweepInterval)
+	if err := s.Set(ctx, "new", &Entry{}, time.Minute, ""); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	if _, ok := s.entries["old"]; ok {
+		t.Fatal("expired entry kept after sweep")
+	}
+	if _, ok := s.tags["trips"]; ok {
+		t.Fatal("tag index kept keys of swept entry")
+	}
+}
+
+func TestRedisStoreExpiry(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	s := NewRedisStore(client)
+
+	mustSet(t, s, "k", &Entry{Body: []byte("x")}, "trips")
+	mr.FastForward(time.Minute)
+	if e := mustGet(t, s, "k"); e != nil {
+		t.Fatalf("expired entry returned: %+v", e)
+	}
+	if mr.Exists(redisTagPrefix + "trips") {
+		t.Fatal("tag set outlived its entries")
+	}
+}
@@ -0,0 +1,169 @@
+package i18n
+
+var enTitles = map[string]string{
+	"bad_request":            "Bad request",
+	"unauthorized":           "Unauthorized",
+	"forbidden":              "Access denied",
+	"not_found":              "Resource not found",
+	"method_not_allowed":     "Method not allowed",
+	"conflict":               "Conflict with the current state of the resource",
+	"validation_failed":      "Validation failed",
+	"too_many_requests":      "Too many requests",
+	"precondition_failed":    "Precondition failed",
+	"unsupported_media_type": "Unsupported media type",
+	"internal_error":         "Internal server error",
+
+	"no_fields":               "No fields to update",
+	"user_not_found":          "User not found",
+	"invalid_credentials":     "Invalid email or password",
+	"use_sso_login":           "Sign in with your SSO provider",
+	"email_taken":             "A user with this email already exists",
+	"phone_taken":             "The phone number is linked to another account",
+	"user_blocked":            "The account is blocked",
+	"impersonation_forbidden": "Signing in as this user is not allowed",
+	"privilege_escalation":    "The role or user has permissions you do not have",
+	"self_action":             "The action is not available for your own account",
+	"invalid_refresh_token":   "The session is invalid, please sign in again",
+	"refresh_token_expired":   "The session has expired, please sign in again",
+	"invalid_token":           "The link is invalid or has expired",
+	"too_many_attempts":       "Too many attempts. Try again later",
+	"invalid_phone":           "Invalid phone number",
+	"invalid_otp":             "Invalid or expired code",
+	"otp_attempts_exceeded":   "Too many wrong attempts. Request a new code",
+	"provider_not_supported":  "The provider is not supported",
+	"invalid_oauth_state":     "The sign-in session has expired, start over",
+	"sso_email_taken":         "The account already exists, sign in and link the provider",
+	"identity_linked":         "The provider account is already linked",
+	"identity_not_found":      "Linked account not found",
+	"last_login_method":       "Cannot unlink the last sign-in method. Set a password or link another account first",
+	"invalid_telegram_auth":   "Could not verify Telegram data",
+	"invalid_api_key":         "Invalid API key",
+	"api_key_not_found":       "The key is not found or revoked",
+	"api_key_forbidden":       "Keys can only be issued for yourself or a service account",
+	"invalid_expiry":          "The key expiry must be in the future",
+	"role_not_found":          "Role not found",
+	"role_exists":             "A role with this name already exists",
+	"unknown_permission":      "Unknown permission",
+	"passenger_not_found":     "Passenger not found",
+	"invalid_document":        "Invalid document number",
+	"invalid_birth_date":      "Invalid date of birth",
+	"erasure_not_requested":   "Erasure request not found",
+	"bus_not_found":           "Bus not found",
+	"bus_category_not_found":  "Category not found",
+	"bus_category_exists":     "A category with this name already exists",
+	"bus_status_not_found":    "Bus status not found",
+	"bus_status_exists":       "A bus status with this name already exists",
+	"driver_not_found":        "Driver not found",
+	"driver_status_not_found": "Driver status not found",
+	"stop_not_found":          "Stop not found",
+	"trip_stop_not_found":     "Trip stop not found",
+	"trip_not_found":          "Trip not found",
+	"version_conflict":        "The record was changed by another user, reload it",
+}
+
+var enTexts = map[string]string{
+	// уточнения ошибок
+	"Требуется аутентификация":                                "Authentication required",
+	"Необходима авторизация":                                  "Authorization required",
+	"Недостаточно прав для выполнения операции":               "Insufficient permissions for this operation",
+	"Недостаточно прав. Требуется одна из ролей":              "Insufficient permissions. One of the roles is required",
+	"Не удалось определить данные пользователя":               "Could not determine the user",
+	"Действие недоступно при входе от имени пользователя":     "The action is not available while signed in as another user",
+	"Просроченный или недействительный токен":                 "Expired or invalid token",
+	"Сессия недействительна, войдите снова":                   "The session is invalid, please sign in again",
+	"Сессия истекла, войдите снова":                           "The session has expired, please sign in again",
+	"Refresh token не найден":                                 "Refresh token not found",
+	"Ошибка авторизации через SSO":                            "SSO authorization failed",
+	"Провайдер не поддерживается":                             "The provider is not supported",
+	"Пользователь не найден":                                  "User not found",
+	"Слишком много запросов. Попробуйте позже":                "Too many requests. Try again later",
+	"Слишком много неудачных попыток входа. Попробуйте позже": "Too many failed sign-in attempts. Try again later",
+	"Код уже отправлен. Повторить можно позже":                "The code has already been sent. Try again later",
+	"Некорректный ID":                                         "Invalid ID",
+	"Некорректный ID пользователя":                            "Invalid user ID",
+	"Некорректный ID статуса":                                 "Invalid status ID",
+	"Некорректный ID категории":                               "Invalid category ID",
+	"Некорректный ID водителя":                                "Invalid driver ID",
+	"Некорректный ID автобуса":                                "Invalid bus ID",
+	"Некорректный ID ключа":                                   "Invalid key ID",
+	"Некорректный ID роли":                                    "Invalid role ID",
+	"Некорректный ID пассажира":                               "Invalid passenger ID",
+	"Тело запроса не является корректным JSON":                "The request body is not valid JSON",
+	"Пустое тело запроса":                                     "The request body is empty",
+	"Некорректные параметры запроса":                          "Invalid request parameters",
+	"Некорректные параметры постраничной выдачи":              "Invalid pagination parameters",
+	"Сортировка по этому полю не поддерживается":              "Sorting by this field is not supported",
+	"Некорректный курсор":                                     "Invalid cursor",
+	"Некорректный фильтр":                                     "Invalid filter",
+	"Тело запроса не является корректным merge patch":         "The request body is not a valid merge patch",
+	"Тело запроса слишком большое":                            "The request body is too large",
+	"Используйте application/merge-patch+json":                "Use application/merge-patch+json",
+	"Тело запроса должно быть массивом":                       "The request body must be an array",
+	"Допустимое количество элементов":                         "Allowed number of items",
+	"Некорректный Idempotency-Key":                            "Invalid Idempotency-Key",
+	"Idempotency-Key уже использован для другого запроса":     "The Idempotency-Key has already been used for a different request",
+	"Запрос с этим Idempotency-Key ещё выполняется":           "A request with this Idempotency-Key is still in progress",
+	"Запись с такими данными уже существует":                  "A record with this data already exists",
+	"Операция нарушает связь с другими записями":              "The operation breaks a reference to other records",
+	"Категория не найдена среди удалённых":                    "Category not found among deleted records",
+	"Пользователь не найден среди удалённых":                  "User not found among deleted records",
+	"Автобус не найден среди удалённых":                       "Bus not found among deleted records",
+	"Статус не найден среди удалённых":                        "Status not found among deleted records",
+	"Остановка не найдена среди удалённых":                    "Stop not found among deleted records",
+	"Водитель не найден среди удалённых":                      "Driver not found among deleted records",
+
+	// ошибки проверки моделей в сервисах
+	"не указаны поля для обновления":                          "No fields to update",
+	"название не может быть пустым":                           "The name cannot be empty",
+	"имя не может быть пустым":                                "The name cannot be empty",
+	"email не может быть пустым":                              "The email cannot be empty",
+	"некорректный формат email":                               "Invalid email format",
+	"время окончания должно быть позже времени начала":        "The end time must be after the start time",
+	"время отправления не может быть раньше времени прибытия": "The departure time cannot be before the arrival time",
+
+	// сообщения об успехе
+	"Автобус успешно создан":            "Bus created",
+	"Данные автобуса успешно обновлены": "Bus updated",
+	"Водитель успешно создан":           "Driver created",
+	"Данные водителя обновлены":         "Driver updated",
+	"Статус водителя создан":            "Driver status created",
+	"Статус водителя обновлён":          "Driver status updated",
+	"Категория успешно создана":         "Category created",
+	"Статус успешно создан":             "Status created",
+	"Роль назначена":                    "Role assigned",
+	"Ключ отозван":                      "Key revoked",
+	"Пароль успешно изменён":            "Password changed",
+	"Email подтверждён":                 "Email confirmed",
+	"Телефон подтвержден":               "Phone confirmed",
+	"Блокировка снята":                  "Lockout cleared",
+	"Пользователь заблокирован":         "User blocked",
+	"Пользователь разблокирован":        "User unblocked",
+	"Запрос на удаление отменен":        "Erasure request cancelled",
+	"Если адрес требует подтверждения, на него отправлено письмо":      "If the address needs confirmation, an email has been sent to it",
+	"Если аккаунт с таким email существует, на него отправлено письмо": "If an account with this email exists, an email has been sent to it",
+}
+
+var enFields = map[string]string{
+	"required": "This field is required",
+	"email":    "Invalid email",
+	"min":      "The value must be at least %s",
+	"max":      "The value must be at most %s",
+	"gt":       "The value must be greater than %s",
+	"lt":       "The value must be less than %s",
+	"len":      "The length must be %s",
+	"oneof":    "Allowed values: %s",
+	"type":     "The value must be of type %s",
+	"plate":    "The plate must look like A123BC77",
+	"lat":      "Latitude must be between -90 and 90",
+	"lon":      "Longitude must be between -180 and 180",
+	"phone":    "The phone must be in E.164 format, for example +79991234567",
+	"after":    "The time must be after %s",
+	"gtefield": "The time cannot be before %s",
+	"price":    "The price must be greater than zero with at most two decimal places",
+	"default":  "Invalid value",
+
+	"filter_syntax": "The parameter must look like filter[field] or filter[field][operator]",
+	"filter_field":  "Filtering by this field is not supported",
+	"filter_op":     "The %s operator is not supported for this field",
+	"filter_value":  "Invalid value for the %s operator",
+}
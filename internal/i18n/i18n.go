@@ -0,0 +1,126 @@
+// Package i18n переводит сообщения API на язык клиента.
+// Исходный язык сообщений — русский: заголовки ошибок берутся из apperrors и переводятся
+// по коду ошибки, уточнения и сообщения об успехе — по исходному тексту,
+// ошибки полей — по коду правила валидации
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang код языка
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+)
+
+// Default язык, если клиент не указал поддерживаемый
+const Default = RU
+
+// catalog переводы одного языка
+type catalog struct {
+	titles map[string]string // код ошибки -> заголовок
+	texts  map[string]string // исходный текст -> перевод
+	fields map[string]string // правило валидации -> сообщение, %s заменяется параметром правила
+}
+
+var catalogs = map[Lang]catalog{
+	RU: {fields: ruFields},
+	EN: {titles: enTitles, texts: enTexts, fields: enFields},
+}
+
+// Parse приводит тег языка (en-US, RU) к поддерживаемому языку
+func Parse(tag string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	lang := Lang(base)
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// Negotiate выбирает язык по заголовку Accept-Language с учётом весов q
+func Negotiate(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil || parsed <= 0 {
+				continue
+			}
+			q = parsed
+		}
+		candidates = append(candidates, candidate{lang: lang, q: q})
+	}
+	if len(candidates) == 0 {
+		return Default
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
+
+// Title заголовок ошибки с кодом code. source — исходный заголовок, он же ответ без перевода
+func Title(lang Lang, code, source string) string {
+	if t, ok := catalogs[lang].titles[code]; ok {
+		return t
+	}
+	return source
+}
+
+// Text переводит исходный текст. Для текста вида "сообщение: подробности"
+// переводится сообщение, подробности остаются как есть
+func Text(lang Lang, source string) string {
+	texts := catalogs[lang].texts
+	if t, ok := texts[source]; ok {
+		return t
+	}
+	if msg, rest, ok := strings.Cut(source, ": "); ok {
+		if t, ok := texts[msg]; ok {
+			return t + ": " + rest
+		}
+	}
+	return source
+}
+
+// Field сообщение об ошибке поля по правилу валидации code и его параметру
+func Field(lang Lang, code, param string) string {
+	fields := catalogs[lang].fields
+	if fields == nil {
+		fields = catalogs[Default].fields
+	}
+
+	switch code {
+	case "required_if", "required_with", "required_without":
+		code = "required"
+	case "gte":
+		code = "min"
+	case "lte":
+		code = "max"
+	case "oneof":
+		param = strings.ReplaceAll(param, " ", ", ")
+	}
+
+	format, ok := fields[code]
+	if !ok {
+		return fields["default"]
+	}
+	if strings.Contains(format, "%s") {
+		return fmt.Sprintf(format, param)
+	}
+	return format
+}
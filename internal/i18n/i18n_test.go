@@ -0,0 +1,77 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+	}{
+		{header: "", want: Default},
+		{header: "en", want: EN},
+		{header: "en-US,en;q=0.9", want: EN},
+		{header: "RU-ru", want: RU},
+		{header: "de-DE, fr;q=0.8", want: Default},
+		{header: "ru;q=0.5, en;q=0.8", want: EN},
+		{header: "de, en;q=0.3, ru;q=0.7", want: RU},
+		{header: "en;q=0, ru;q=0.1", want: RU},
+		{header: "en;q=abc, ru;q=0.1", want: RU},
+		{header: "ru, en", want: RU},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := Negotiate(tt.header); got != tt.want {
+				t.Fatalf("Negotiate(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		lang        Lang
+		code, param string
+		want        string
+	}{
+		{lang: RU, code: "required", want: "Обязательное поле"},
+		{lang: EN, code: "required_with", param: "StartTime", want: "This field is required"},
+		{lang: RU, code: "gte", param: "1", want: "Значение должно быть не меньше 1"},
+		{lang: EN, code: "lte", param: "10", want: "The value must be at most 10"},
+		{lang: EN, code: "oneof", param: "scheduled cancelled", want: "Allowed values: scheduled, cancelled"},
+		{lang: RU, code: "after", param: "start_time", want: "Время должно быть позже start_time"},
+		{lang: EN, code: "filter_op", param: "contains", want: "The contains operator is not supported for this field"},
+		{lang: EN, code: "unknown_rule", want: "Invalid value"},
+		{lang: "de", code: "required", want: "Обязательное поле"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang)+"/"+tt.code, func(t *testing.T) {
+			if got := Field(tt.lang, tt.code, tt.param); got != tt.want {
+				t.Fatalf("Field(%s, %s, %q) = %q, want %q", tt.lang, tt.code, tt.param, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		lang   Lang
+		source string
+		want   string
+	}{
+		{lang: RU, source: "Некорректный курсор", want: "Некорректный курсор"},
+		{lang: EN, source: "Некорректный курсор", want: "Invalid cursor"},
+		{lang: EN, source: "время окончания должно быть позже времени начала", want: "The end time must be after the start time"},
+		{lang: EN, source: "Допустимое количество элементов: 1-500", want: "Allowed number of items: 1-500"},
+		{lang: EN, source: "нет в каталоге", want: "нет в каталоге"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang)+"/"+tt.source, func(t *testing.T) {
+			if got := Text(tt.lang, tt.source); got != tt.want {
+				t.Fatalf("Text(%s, %q) = %q, want %q", tt.lang, tt.source, got, tt.want)
+			}
+		})
+	}
+}
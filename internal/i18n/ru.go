@@ -0,0 +1,26 @@
+package i18n
+
+var ruFields = map[string]string{
+	"required": "Обязательное поле",
+	"email":    "Некорректный email",
+	"min":      "Значение должно быть не меньше %s",
+	"max":      "Значение должно быть не больше %s",
+	"gt":       "Значение должно быть больше %s",
+	"lt":       "Значение должно быть меньше %s",
+	"len":      "Длина должна быть равна %s",
+	"oneof":    "Допустимые значения: %s",
+	"type":     "Значение должно иметь тип %s",
+	"plate":    "Госномер в формате А123ВС77",
+	"lat":      "Широта должна быть от -90 до 90",
+	"lon":      "Долгота должна быть от -180 до 180",
+	"phone":    "Телефон в формате E.164, например +79991234567",
+	"after":    "Время должно быть позже %s",
+	"gtefield": "Время не может быть раньше %s",
+	"price":    "Цена должна быть больше нуля, не больше двух знаков после запятой",
+	"default":  "Некорректное значение",
+
+	"filter_syntax": "Параметр должен иметь вид filter[поле] или filter[поле][оператор]",
+	"filter_field":  "Фильтр по этому полю не поддерживается",
+	"filter_op":     "Оператор %s не поддерживается для этого поля",
+	"filter_value":  "Некорректное значение для оператора %s",
+}
@@ -0,0 +1,48 @@
+// Package idempotency хранит ответы на запросы с заголовком Idempotency-Key,
+// чтобы повтор того же запроса получил исходный ответ, а не выполнился дважды.
+package idempotency
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Record запись о запросе с ключом. Пока запрос выполняется, Done == false и ответа нет
+type Record struct {
+	Fingerprint string            `json:"fingerprint"` // хеш метода, пути с query и тела запроса
+	Done        bool              `json:"done"`
+	Status      int               `json:"status,omitempty"`
+	ContentType string            `json:"content_type,omitempty"`
+	Header      map[string]string `json:"header,omitempty"` // Location, ETag и другие заголовки ответа для повтора
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// Store хранит записи. Begin атомарно занимает ключ: если ключ свободен,
+// сохраняет запись «выполняется» на lockTTL с меткой владельца token и возвращает nil;
+// иначе возвращает существующую запись. Complete и Release меняют запись, только
+// если ключ всё ещё занят с той же меткой: после истечения lockTTL ключ мог занять
+// другой запрос, и его запись трогать нельзя
+type Store interface {
+	Begin(ctx context.Context, key, fingerprint, token string, lockTTL time.Duration) (*Record, error)
+	Complete(ctx context.Context, key, token string, rec *Record, ttl time.Duration) error
+	Release(ctx context.Context, key, token string) error
+}
+
+// New создаёт хранилище по настройкам: redis для нескольких инстансов, memory для одного
+func New(cfg *config.Idempotency, rdb *redis.Client) (Store, error) {
+	switch cfg.Store {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "", "redis":
+		if rdb == nil {
+			return nil, fmt.Errorf("idempotency: redis store requires redis client")
+		}
+		return NewRedisStore(rdb), nil
+	default:
+		return nil, fmt.Errorf("idempotency: unknown store %q", cfg.Store)
+	}
+}
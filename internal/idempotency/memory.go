@@ -0,0 +1,90 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+type memoryRecord struct {
+	rec     *Record
+	token   string // метка владельца, пока запрос выполняется
+	expires time.Time
+}
+
+// MemoryStore хранит записи в памяти процесса. Подходит для одного инстанса
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]*memoryRecord
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records:   make(map[string]*memoryRecord),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Begin(_ context.Context, key, fingerprint, token string, lockTTL time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if r, ok := s.records[key]; ok && now.Before(r.expires) {
+		return r.rec, nil
+	}
+	s.records[key] = &memoryRecord{
+		rec:     &Record{Fingerprint: fingerprint},
+		token:   token,
+		expires: now.Add(lockTTL),
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key, token string, rec *Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.owns(key, token) {
+		return nil
+	}
+	s.records[key] = &memoryRecord{rec: rec, expires: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.owns(key, token) {
+		delete(s.records, key)
+	}
+	return nil
+}
+
+// owns сообщает, что ключ занят выполняющимся запросом с меткой token
+func (s *MemoryStore) owns(key, token string) bool {
+	r, ok := s.records[key]
+	return ok && !r.rec.Done && r.token == token && s.now().Before(r.expires)
+}
+
+// sweep удаляет просроченные записи, чтобы карта не росла бесконечно
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, r := range s.records {
+		if !now.Before(r.expires) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix   = "idempotency:"
+	redisTokenSuffix = ":token"
+)
+
+// Ключ занимается и существующая запись читается одной командой,
+// поэтому из одновременных запросов с одним ключом выполнится только один.
+// Рядом с записью «выполняется» хранится метка владельца с тем же сроком.
+// KEYS: запись, метка. ARGV: запись «выполняется», метка, lockTTL в мс
+var beginScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current then
+	return current
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+return false
+`)
+
+// Готовый ответ сохраняется, только если ключ всё ещё занят этим запросом.
+// KEYS: запись, метка. ARGV: метка, ответ, ttl в мс
+var completeScript = redis.NewScript(`
+if redis.call('GET', KEYS[2]) ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+redis.call('DEL', KEYS[2])
+return 1
+`)
+
+// Ключ освобождается, только если его занимает этот запрос. KEYS: запись, метка. ARGV: метка
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[2]) ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1], KEYS[2])
+return 1
+`)
+
+// RedisStore хранит записи в Redis и разделяет их между инстансами
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func redisKeys(key string) []string {
+	return []string{redisKeyPrefix + key, redisKeyPrefix + key + redisTokenSuffix}
+}
+
+func (s *RedisStore) Begin(ctx context.Context, key, fingerprint, token string, lockTTL time.Duration) (*Record, error) {
+	pending, err := json.Marshal(&Record{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := beginScript.Run(ctx, s.client, redisKeys(key), pending, token, lockTTL.Milliseconds()).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec Record
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (s *RedisStore) Complete(ctx context.Context, key, token string, rec *Record, ttl time.Duration) error {
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return completeScript.Run(ctx, s.client, redisKeys(key), token, raw, ttl.Milliseconds()).Err()
+}
+
+func (s *RedisStore) Release(ctx context.Context, key, token string) error {
+	return releaseScript.Run(ctx, s.client, redisKeys(key), token).Err()
+}
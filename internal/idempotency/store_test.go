@@ -0,0 +1,130 @@
+package idempotency
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func testStores(t *testing.T) map[string]Store {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"redis":  NewRedisStore(client),
+	}
+}
+
+func TestStoreBeginComplete(t *testing.T) {
+	ctx := context.Background()
+
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			rec, err := s.Begin(ctx, "user:1:k", "fp", "owner", time.Minute)
+			if err != nil || rec != nil {
+				t.Fatalf("Begin on free key = %+v, %v", rec, err)
+			}
+
+			rec, err = s.Begin(ctx, "user:1:k", "fp", "other", time.Minute)
+			if err != nil || rec == nil || rec.Done || rec.Fingerprint != "fp" {
+				t.Fatalf("Begin on busy key = %+v, %v, want pending record", rec, err)
+			}
+
+			done := &Record{
+				Fingerprint: "fp",
+				Done:        true,
+				Status:      201,
+				ContentType: "application/json",
+				Header:      map[string]string{"Location": "/api/v1/orders/7"},
+				Body:        []byte(`{"id":7}`),
+			}
+			if err := s.Complete(ctx, "user:1:k", "owner", done, time.Hour); err != nil {
+				t.Fatalf("Complete: %v", err)
+			}
+
+			rec, err = s.Begin(ctx, "user:1:k", "fp", "later", time.Minute)
+			if err != nil || !reflect.DeepEqual(rec, done) {
+				t.Fatalf("Begin after Complete = %+v, %v, want %+v", rec, err, done)
+			}
+		})
+	}
+}
+
+func TestStoreReleaseOnlyByOwner(t *testing.T) {
+	ctx := context.Background()
+
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, err := s.Begin(ctx, "k", "fp", "owner", time.Minute); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.Release(ctx, "k", "stranger"); err != nil {
+				t.Fatalf("Release: %v", err)
+			}
+			if rec, _ := s.Begin(ctx, "k", "fp", "next", time.Minute); rec == nil {
+				t.Fatal("foreign token released the key")
+			}
+
+			if err := s.Release(ctx, "k", "owner"); err != nil {
+				t.Fatalf("Release: %v", err)
+			}
+			if rec, _ := s.Begin(ctx, "k", "fp", "next", time.Minute); rec != nil {
+				t.Fatalf("owner release kept the key: %+v", rec)
+			}
+		})
+	}
+}
+
+// Запрос выполнялся дольше lockTTL, ключ занял повтор: старый запрос не должен
+// ни удалить чужую запись, ни записать поверх неё свой ответ
+func TestStoreExpiredLockKeepsNewOwner(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	memory := NewMemoryStore()
+	memory.now = func() time.Time { return now }
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	stores := map[string]struct {
+		store   Store
+		advance func(time.Duration)
+	}{
+		"memory": {memory, func(d time.Duration) { now = now.Add(d) }},
+		"redis":  {NewRedisStore(client), mr.FastForward},
+	}
+
+	for name, tt := range stores {
+		t.Run(name, func(t *testing.T) {
+			s := tt.store
+			if _, err := s.Begin(ctx, "k", "fp", "slow", time.Second); err != nil {
+				t.Fatal(err)
+			}
+			tt.advance(2 * time.Second)
+			if rec, err := s.Begin(ctx, "k", "fp", "retry", time.Minute); err != nil || rec != nil {
+				t.Fatalf("Begin after lock expiry = %+v, %v", rec, err)
+			}
+
+			if err := s.Release(ctx, "k", "slow"); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Complete(ctx, "k", "slow", &Record{Fingerprint: "fp", Done: true, Status: 201}, time.Hour); err != nil {
+				t.Fatal(err)
+			}
+
+			rec, err := s.Begin(ctx, "k", "fp", "third", time.Minute)
+			if err != nil || rec == nil || rec.Done {
+				t.Fatalf("record = %+v, %v, want the retry still pending", rec, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogMailer — mailer для локальной разработки: пишет письма в лог
+// и, если указан каталог, сохраняет их в .eml файлы
+type LogMailer struct {
+	log *logger.Logger
+	dir string
+}
+
+func NewLog(log *logger.Logger, dir string) Mailer {
+	return &LogMailer{log: log, dir: dir}
+}
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	m.log.Infof("mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+
+	if m.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), msg.To)
+	return os.WriteFile(filepath.Join(m.dir, name), build("dev@localhost", msg), 0644)
+}
@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/logger"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Message — текстовое письмо
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New создает mailer в зависимости от выбранного драйвера
+func New(cfg *config.Mailer, log *logger.Logger) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTP(cfg), nil
+	case "log", "":
+		return NewLog(log, cfg.Directory), nil
+	default:
+		return nil, fmt.Errorf("unsupported mailer driver: %s", cfg.Driver)
+	}
+}
+
+// build формирует RFC 5322 сообщение
+func build(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,74 @@
+package mailer
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPMailer отправляет письма через SMTP сервер (STARTTLS, если поддерживается)
+type SMTPMailer struct {
+	cfg *config.Mailer
+}
+
+func NewSMTP(cfg *config.Mailer) Mailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("smtp dial failed: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp client failed: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+
+	if m.cfg.Username != "" {
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := c.Mail(m.cfg.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(build(m.cfg.From, msg)); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("smtp write failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,64 @@
+// Package policy решает, может ли пользователь из claims действовать над ресурсом:
+// по правам роли или как владелец ресурса
+package policy
+
+import (
+	"context"
+	"corpord-api/model"
+	"errors"
+)
+
+// ErrForbidden действие запрещено политикой
+var ErrForbidden = errors.New("forbidden")
+
+// Permissions проверяет права роли (реализуется service.Role)
+type Permissions interface {
+	HasPermission(ctx context.Context, roleName string, codes ...string) (bool, error)
+}
+
+// Allowed сообщает, есть ли у пользователя хотя бы одно из прав codes.
+// Если claims ограничены scopes (API-ключ), учитываются только права из этого списка
+func Allowed(ctx context.Context, perms Permissions, claims *model.Claims, codes ...string) (bool, error) {
+	required := codes
+	if len(claims.Scopes) > 0 {
+		required = intersect(codes, claims.Scopes)
+	}
+	if len(required) == 0 {
+		return false, nil
+	}
+	return perms.HasPermission(ctx, claims.Role, required...)
+}
+
+// OwnerOrPermission разрешает действие владельцу ресурса ownerID или пользователю
+// с одним из прав codes (администратору). Возвращает ErrForbidden, если нельзя ни то, ни другое.
+// Claims со scopes (API-ключ) должны содержать одно из прав codes и для своего ресурса
+func OwnerOrPermission(ctx context.Context, perms Permissions, claims *model.Claims, ownerID int, codes ...string) error {
+	if len(claims.Scopes) > 0 && len(intersect(codes, claims.Scopes)) == 0 {
+		return ErrForbidden
+	}
+	if claims.UserID != 0 && claims.UserID == ownerID {
+		return nil
+	}
+
+	ok, err := Allowed(ctx, perms, claims, codes...)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func intersect(codes, scopes []string) []string {
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		for _, scope := range scopes {
+			if code == scope {
+				result = append(result, code)
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package policy
+
+import (
+	"context"
+	"corpord-api/internal/policy/policytest"
+	"corpord-api/model"
+	"errors"
+	"testing"
+)
+
+var testPermissions = policytest.Permissions{
+	model.RoleAdmin: {model.PermUsersRead, model.PermUsersManage, model.PermOrdersRead},
+	"support":       {model.PermUsersRead},
+	"user":          {},
+}
+
+func TestAllowedScopesNarrowRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims *model.Claims
+		codes  []string
+		want   bool
+	}{
+		{
+			name:   "role without scopes",
+			claims: &model.Claims{UserID: 1, Role: model.RoleAdmin},
+			codes:  []string{model.PermUsersManage},
+			want:   true,
+		},
+		{
+			name:   "scope granted by role",
+			claims: &model.Claims{UserID: 1, Role: model.RoleAdmin, Scopes: []string{model.PermUsersManage}},
+			codes:  []string{model.PermUsersManage},
+			want:   true,
+		},
+		{
+			name:   "scope narrows admin role",
+			claims: &model.Claims{UserID: 1, Role: model.RoleAdmin, Scopes: []string{model.PermOrdersRead}},
+			codes:  []string{model.PermUsersManage},
+		},
+		{
+			name:   "scope does not widen role",
+			claims: &model.Claims{UserID: 2, Role: "support", Scopes: []string{model.PermUsersManage}},
+			codes:  []string{model.PermUsersManage},
+		},
+		{
+			name:   "one of several codes within scopes",
+			claims: &model.Claims{UserID: 1, Role: model.RoleAdmin, Scopes: []string{model.PermUsersRead}},
+			codes:  []string{model.PermUsersManage, model.PermUsersRead},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Allowed(context.Background(), testPermissions, tt.claims, tt.codes...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Allowed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnerOrPermission(t *testing.T) {
+	const ownerID = 10
+
+	tests := []struct {
+		name   string
+		claims *model.Claims
+		codes  []string
+		want   error
+	}{
+		{name: "owner", claims: &model.Claims{UserID: ownerID, Role: "user"}},
+		{name: "other user", claims: &model.Claims{UserID: 11, Role: "user"}, want: ErrForbidden},
+		{name: "anonymous claims", claims: &model.Claims{Role: "user"}, want: ErrForbidden},
+		{name: "role with permission", claims: &model.Claims{UserID: 11, Role: "support"}},
+		{
+			name:   "api key scopes exclude permission",
+			claims: &model.Claims{UserID: 11, Role: model.RoleAdmin, Scopes: []string{model.PermOrdersRead}},
+			want:   ErrForbidden,
+		},
+		{
+			name:   "owner with api key scoped to the action",
+			claims: &model.Claims{UserID: ownerID, Role: "user", Scopes: []string{model.PermUsersRead}},
+		},
+		{
+			name:   "owner with api key without the scope",
+			claims: &model.Claims{UserID: ownerID, Role: "user", Scopes: []string{model.PermOrdersRead}},
+			want:   ErrForbidden,
+		},
+		{
+			name:   "owner with read-only key deletes own account",
+			claims: &model.Claims{UserID: ownerID, Role: "user", Scopes: []string{model.PermUsersRead}},
+			codes:  []string{model.PermUsersManage},
+			want:   ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := tt.codes
+			if codes == nil {
+				codes = []string{model.PermUsersRead}
+			}
+			err := OwnerOrPermission(context.Background(), testPermissions, tt.claims, ownerID, codes...)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
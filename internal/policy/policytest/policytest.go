@@ -0,0 +1,18 @@
+// Package policytest заглушки policy для тестов других пакетов
+package policytest
+
+import "context"
+
+// Permissions права ролей: имя роли — список кодов прав
+type Permissions map[string][]string
+
+func (p Permissions) HasPermission(_ context.Context, roleName string, codes ...string) (bool, error) {
+	for _, code := range codes {
+		for _, perm := range p[roleName] {
+			if perm == code {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
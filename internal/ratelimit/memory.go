@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	idle    time.Duration // через сколько полная корзина может быть удалена
+}
+
+// MemoryStore хранит корзины в памяти процесса. Подходит для одного инстанса
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Allow(_ context.Context, key string, rule Rule) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	burst := float64(rule.burst())
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		if rate := rule.rate(); rate > 0 {
+			b.idle = time.Duration(burst/rate) * time.Millisecond
+		}
+		s.buckets[key] = b
+	}
+
+	elapsed := float64(now.Sub(b.updated).Milliseconds())
+	if elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*rule.rate())
+		b.updated = now
+	}
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	return result(allowed, b.tokens, rule), nil
+}
+
+// sweep удаляет давно не использованные корзины, чтобы карта не росла бесконечно
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) > b.idle {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package ratelimit реализует ограничение частоты запросов по алгоритму token bucket.
+package ratelimit
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Rule описывает корзину: Burst токенов, пополняемых со скоростью Requests за Per
+type Rule struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// rate возвращает скорость пополнения в токенах за миллисекунду
+func (r Rule) rate() float64 {
+	if r.Per <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / float64(r.Per.Milliseconds())
+}
+
+func (r Rule) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Requests
+}
+
+// Result итог проверки одного запроса
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // через сколько появится следующий токен (если запрос отклонён)
+	ResetAfter time.Duration // через сколько корзина наполнится полностью
+}
+
+// Store хранит состояние корзин
+type Store interface {
+	Allow(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// New создаёт хранилище по настройкам: redis для нескольких инстансов, memory для одного
+func New(cfg *config.RateLimit, rdb *redis.Client) (Store, error) {
+	switch cfg.Store {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "redis":
+		if rdb == nil {
+			return nil, fmt.Errorf("ratelimit: redis store requires redis client")
+		}
+		return NewRedisStore(rdb), nil
+	default:
+		return nil, fmt.Errorf("ratelimit: unknown store %q", cfg.Store)
+	}
+}
+
+// result собирает Result по остатку токенов после списания
+func result(allowed bool, tokens float64, rule Rule) Result {
+	rate := rule.rate()
+	burst := rule.burst()
+	res := Result{
+		Allowed:   allowed,
+		Limit:     burst,
+		Remaining: int(math.Floor(tokens)),
+	}
+	if rate > 0 {
+		res.ResetAfter = time.Duration((float64(burst)-tokens)/rate) * time.Millisecond
+		if !allowed {
+			res.RetryAfter = time.Duration(math.Ceil((1-tokens)/rate)) * time.Millisecond
+		}
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+// Пополнение и списание выполняются атомарно на стороне Redis по его часам,
+// поэтому результат одинаков для всех инстансов API.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+if rate > 0 then
+	redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate))
+end
+
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore хранит корзины в Redis и разделяет лимиты между инстансами
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	res, err := tokenBucketScript.Run(ctx, s.client,
+		[]string{redisKeyPrefix + key},
+		strconv.FormatFloat(rule.rate(), 'f', -1, 64),
+		rule.burst(),
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, _ := res[0].(int64)
+	raw, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return result(allowed == 1, tokens, rule), nil
+}
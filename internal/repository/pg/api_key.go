@@ -0,0 +1,191 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"corpord-api/pkg/dbx"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+var apiKeyColumns = []string{
+	"id", "user_id", "name", "prefix", "key_hash", "scopes",
+	"expires_at", "last_used_at", "revoked_at", "created_by", "created_at",
+}
+
+// APIKeyRepository ключи доступа к API
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	ByID(ctx context.Context, id uuid.UUID) (*model.APIKey, error)
+	ByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	ByUser(ctx context.Context, userID int) ([]*model.APIKey, error)
+	// Rotate отзывает ключ oldID и сохраняет newKey в одной транзакции
+	Rotate(ctx context.Context, oldID uuid.UUID, newKey *model.APIKey) error
+	Revoke(ctx context.Context, id uuid.UUID) error
+	// Touch обновляет last_used_at не чаще раза в минуту, чтобы не писать в базу на каждый запрос
+	Touch(ctx context.Context, id uuid.UUID) error
+}
+
+type apiKeyRepo struct {
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func NewAPIKeyRepo(logger *logger.Logger, qb *dbx.QueryBuilder) APIKeyRepository {
+	return &apiKeyRepo{
+		logger: logger,
+		qb:     qb,
+	}
+}
+
+func (r *apiKeyRepo) insert(key *model.APIKey) (string, []any, error) {
+	return r.qb.Sq.Insert(TableAPIKeys).
+		Columns("id", "user_id", "name", "prefix", "key_hash", "scopes", "expires_at", "created_by").
+		Values(key.ID, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt, key.CreatedBy).
+		Suffix("RETURNING created_at").
+		ToSql()
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, key *model.APIKey) error {
+	query, args, err := r.insert(key)
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	if err = r.qb.DB.QueryRowxContext(ctx, query, args...).Scan(&key.CreatedAt); err != nil {
+		if IsPgError(err, ErrorCodeForeignKeyViolation) {
+			return ErrForeignKeyViolation
+		}
+		r.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (r *apiKeyRepo) get(ctx context.Context, where sq.Eq) (*model.APIKey, error) {
+	query, args, err := r.qb.Sq.Select(apiKeyColumns...).
+		From(TableAPIKeys).
+		Where(where).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	var key model.APIKey
+	if err = r.qb.DB.GetContext(ctx, &key, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		r.logger.Error(err)
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepo) ByID(ctx context.Context, id uuid.UUID) (*model.APIKey, error) {
+	return r.get(ctx, sq.Eq{"id": id})
+}
+
+func (r *apiKeyRepo) ByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	return r.get(ctx, sq.Eq{"prefix": prefix})
+}
+
+func (r *apiKeyRepo) ByUser(ctx context.Context, userID int) ([]*model.APIKey, error) {
+	query, args, err := r.qb.Sq.Select(apiKeyColumns...).
+		From(TableAPIKeys).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at DESC").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	keys := make([]*model.APIKey, 0)
+	if err = r.qb.DB.SelectContext(ctx, &keys, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepo) Rotate(ctx context.Context, oldID uuid.UUID, newKey *model.APIKey) error {
+	return r.qb.Tx.Do(ctx, func(ctx context.Context) error {
+		query, args, err := r.revoke(oldID)
+		if err != nil {
+			r.logger.Error(err)
+			return err
+		}
+		res, err := r.qb.DB.ExecContext(ctx, query, args...)
+		if err != nil {
+			r.logger.Error(err)
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrAPIKeyNotFound
+		}
+
+		query, args, err = r.insert(newKey)
+		if err != nil {
+			r.logger.Error(err)
+			return err
+		}
+		if err = r.qb.DB.QueryRowxContext(ctx, query, args...).Scan(&newKey.CreatedAt); err != nil {
+			r.logger.Error(err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *apiKeyRepo) revoke(id uuid.UUID) (string, []any, error) {
+	return r.qb.Sq.Update(TableAPIKeys).
+		Set("revoked_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "revoked_at": nil}).
+		ToSql()
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	query, args, err := r.revoke(id)
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	res, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *apiKeyRepo) Touch(ctx context.Context, id uuid.UUID) error {
+	query, args, err := r.qb.Sq.Update(TableAPIKeys).
+		Set("last_used_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id}).
+		Where("(last_used_at IS NULL OR last_used_at < now() - interval '1 minute')").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	if _, err = r.qb.DB.ExecContext(ctx, query, args...); err != nil {
+		r.logger.Error(err)
+		return err
+	}
+	return nil
+}
@@ -19,6 +19,20 @@ type AuthRepository interface {
 	GetUserByEmail(ctx context.Context, email string) (*model.UserDB, error)
 	// GetUserByID retrieves a user by ID
 	GetUserByID(ctx context.Context, id int) (*model.UserDB, error)
+	// GetUserByPhone retrieves a user by phone in E.164 format
+	GetUserByPhone(ctx context.Context, phone string) (*model.UserDB, error)
+	// CreatePhoneUser creates a passwordless user identified by a verified phone
+	CreatePhoneUser(ctx context.Context, phone string) (int, error)
+	// SetPhone sets a verified phone for the user
+	SetPhone(ctx context.Context, id int, phone string) error
+	// CreateServiceAccount creates a user without login credentials that authenticates with API keys
+	CreateServiceAccount(ctx context.Context, name string, roleID int) (int, error)
+	// UpdatePassword sets a new password hash for the user
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	// SetEmailVerified marks the user's email as verified
+	SetEmailVerified(ctx context.Context, id int) error
+	// SetBlocked blocks or unblocks the user, the reason is kept only while blocked
+	SetBlocked(ctx context.Context, id int, blocked bool, reason *string) error
 }
 
 type authRepository struct {
@@ -39,7 +53,7 @@ func (r *authRepository) CreateUser(ctx context.Context, user *model.UserCreate)
 	query, args, err := r.qb.Sq.Insert(TableUsers).
 		Columns("email", "password_hash", "name").
 		Values(
-			user.Email,
+			nullIfEmpty(user.Email),
 			user.Password,
 			user.Name,
 		).
@@ -65,10 +79,17 @@ func (r *authRepository) CreateUser(ctx context.Context, user *model.UserCreate)
 func (r *authRepository) GetUserByEmail(ctx context.Context, email string) (*model.UserDB, error) {
 	query, args, err := r.qb.Sq.Select(
 		"u.id",
-		"u.email",
+		"COALESCE(u.email, '') AS email",
 		"u.password_hash",
 		"u.name",
 		"r.name as role_name",
+		"u.email_verified",
+		"COALESCE(u.phone, '') AS phone",
+		"u.phone_verified",
+		"u.is_service_account",
+		"u.preferred_language",
+		"u.blocked_at",
+		"u.blocked_reason",
 		"u.created_at",
 		"u.updated_at",
 		"u.deleted_at",
@@ -100,10 +121,17 @@ func (r *authRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 func (r *authRepository) GetUserByID(ctx context.Context, id int) (*model.UserDB, error) {
 	query, args, err := r.qb.Sq.Select(
 		"u.id",
-		"u.email",
+		"COALESCE(u.email, '') AS email",
 		"u.password_hash",
 		"u.name",
 		"r.name as role_name",
+		"u.email_verified",
+		"COALESCE(u.phone, '') AS phone",
+		"u.phone_verified",
+		"u.is_service_account",
+		"u.preferred_language",
+		"u.blocked_at",
+		"u.blocked_reason",
 		"u.created_at",
 		"u.updated_at",
 		"u.deleted_at",
@@ -130,3 +158,202 @@ func (r *authRepository) GetUserByID(ctx context.Context, id int) (*model.UserDB
 
 	return &user, nil
 }
+
+// GetUserByPhone retrieves a user by phone with role name
+func (r *authRepository) GetUserByPhone(ctx context.Context, phone string) (*model.UserDB, error) {
+	query, args, err := r.qb.Sq.Select(
+		"u.id",
+		"COALESCE(u.email, '') AS email",
+		"u.password_hash",
+		"u.name",
+		"r.name as role_name",
+		"u.email_verified",
+		"COALESCE(u.phone, '') AS phone",
+		"u.phone_verified",
+		"u.is_service_account",
+		"u.preferred_language",
+		"u.blocked_at",
+		"u.blocked_reason",
+		"u.created_at",
+		"u.updated_at",
+		"u.deleted_at",
+	).
+		From("users u").
+		Join("roles r ON u.role_id = r.id").
+		Where(sq.Eq{"u.phone": phone, "u.deleted_at": nil}).
+		ToSql()
+
+	if err != nil {
+		r.logger.Error("Failed to build get user by phone query", "error", err)
+		return nil, err
+	}
+
+	var user model.UserDB
+	err = r.qb.DB.GetContext(ctx, &user, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get user by phone", "error", err)
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// CreatePhoneUser creates a passwordless user identified by a verified phone
+func (r *authRepository) CreatePhoneUser(ctx context.Context, phone string) (int, error) {
+	query, args, err := r.qb.Sq.Insert(TableUsers).
+		Columns("phone", "phone_verified", "name").
+		Values(phone, true, "").
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		r.logger.Error("Failed to build create phone user query", "error", err)
+		return 0, err
+	}
+
+	var id int
+	if err := r.qb.DB.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		if IsPgError(err, ErrorCodeUniqueViolation) {
+			return 0, ErrAlreadyExists
+		}
+		r.logger.Error("Failed to create phone user", "error", err)
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// CreateServiceAccount creates a user without login credentials that authenticates with API keys
+func (r *authRepository) CreateServiceAccount(ctx context.Context, name string, roleID int) (int, error) {
+	query, args, err := r.qb.Sq.Insert(TableUsers).
+		Columns("name", "role_id", "is_service_account").
+		Values(name, roleID, true).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		r.logger.Error("Failed to build create service account query", "error", err)
+		return 0, err
+	}
+
+	var id int
+	if err := r.qb.DB.QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+		if IsPgError(err, ErrorCodeForeignKeyViolation) {
+			return 0, ErrForeignKeyViolation
+		}
+		r.logger.Error("Failed to create service account", "error", err)
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// SetPhone sets a verified phone for the user
+func (r *authRepository) SetPhone(ctx context.Context, id int, phone string) error {
+	query, args, err := r.qb.Sq.Update(TableUsers).
+		Set("phone", phone).
+		Set("phone_verified", true).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		r.logger.Error("Failed to build set phone query", "error", err)
+		return err
+	}
+
+	result, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		if IsPgError(err, ErrorCodeUniqueViolation) {
+			return ErrAlreadyExists
+		}
+		r.logger.Error("Failed to set phone", "error", err, "user_id", id)
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdatePassword sets a new password hash for the user
+func (r *authRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	query, args, err := r.qb.Sq.Update(TableUsers).
+		Set("password_hash", passwordHash).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		r.logger.Error("Failed to build update password query", "error", err)
+		return err
+	}
+
+	result, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to update password", "error", err, "user_id", id)
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetEmailVerified marks the user's email as verified
+func (r *authRepository) SetEmailVerified(ctx context.Context, id int) error {
+	query, args, err := r.qb.Sq.Update(TableUsers).
+		Set("email_verified", true).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		r.logger.Error("Failed to build set email verified query", "error", err)
+		return err
+	}
+
+	result, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to set email verified", "error", err, "user_id", id)
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// SetBlocked blocks or unblocks the user, the reason is kept only while blocked
+func (r *authRepository) SetBlocked(ctx context.Context, id int, blocked bool, reason *string) error {
+	update := r.qb.Sq.Update(TableUsers).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil})
+	if blocked {
+		update = update.Set("blocked_at", sq.Expr("NOW()")).Set("blocked_reason", reason)
+	} else {
+		update = update.Set("blocked_at", nil).Set("blocked_reason", nil)
+	}
+
+	query, args, err := update.ToSql()
+	if err != nil {
+		r.logger.Error("Failed to build set blocked query", "error", err)
+		return err
+	}
+
+	result, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to set blocked", "error", err, "user_id", id)
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/pkg/dbx"
+	"errors"
+	"strconv"
+)
+
+// ItemError ошибка элемента пакетной операции. Index — номер элемента в запросе
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return "item " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// bulk записывает элементы в одной транзакции: ошибка любого элемента откатывает все.
+// Каждый элемент пишется в своей точке сохранения, поэтому после ошибки остальные
+// всё равно проверяются базой, и клиент получает ошибки всех элементов сразу —
+// *ItemError с индексом для каждого, объединённые errors.Join
+func bulk[T any](ctx context.Context, qb *dbx.QueryBuilder, items []T, write func(ctx context.Context, item T) error) error {
+	return qb.Tx.Do(ctx, func(ctx context.Context) error {
+		var errs []error
+		for i, item := range items {
+			err := qb.Tx.Do(ctx, func(ctx context.Context) error {
+				return write(ctx, item)
+			})
+			if err == nil {
+				continue
+			}
+			errs = append(errs, &ItemError{Index: i, Err: err})
+			// без соединения или после отмены запроса остальные элементы проверить нельзя
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
@@ -0,0 +1,70 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/pkg/dbx"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestBulkReportsEveryFailedItem(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	db := sqlx.NewDb(conn, "pgx")
+	qb := &dbx.QueryBuilder{DB: dbx.NewDB(db), Tx: dbx.NewTxManager(db)}
+
+	exec := func(q string) *sqlmock.ExpectedExec {
+		return mock.ExpectExec("^" + regexp.QuoteMeta(q) + "$")
+	}
+	mock.ExpectBegin()
+	for i, fail := range []bool{false, true, false, true} {
+		exec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+		update := exec(fmt.Sprintf("UPDATE stops %d", i))
+		if fail {
+			update.WillReturnResult(sqlmock.NewResult(0, 0))
+			exec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+			continue
+		}
+		update.WillReturnResult(sqlmock.NewResult(0, 1))
+		exec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectRollback()
+
+	err = bulk(context.Background(), qb, []int{0, 1, 2, 3}, func(ctx context.Context, i int) error {
+		res, err := qb.DB.ExecContext(ctx, fmt.Sprintf("UPDATE stops %d", i))
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+
+	if err == nil {
+		t.Fatal("bulk with failed items returned nil")
+	}
+	var failed []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var item *ItemError
+		if !errors.As(e, &item) || !errors.Is(item, ErrNotFound) {
+			t.Fatalf("unexpected error %v", e)
+		}
+		failed = append(failed, item.Index)
+	}
+	if !reflect.DeepEqual(failed, []int{1, 3}) {
+		t.Fatalf("failed items = %v, want [1 3]", failed)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,57 +5,88 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/model"
 	"corpord-api/pkg/dbx"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
+	"database/sql"
+	"errors"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 )
 
 type BusRepository interface {
-	CreateBus(ctx context.Context, bus model.Bus) error
+	CreateBus(ctx context.Context, bus model.BusCreate) error
 	GetBus(ctx context.Context, id int) (*model.ViewBus, error)
-	GetAllBuses(ctx context.Context) ([]model.ViewBus, error)
+	GetAllBuses(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[model.ViewBus], error)
+	CreateMany(ctx context.Context, buses []model.BusCreate) ([]int, error)
 	UpdateBus(ctx context.Context, bus *model.BusUpdate) error
+	UpdateMany(ctx context.Context, buses []*model.BusUpdate) error
+	ForPatch(ctx context.Context, id int) (*model.BusPatch, error)
+	Patch(ctx context.Context, bus *model.BusPatch) (time.Time, error)
 	DeleteBus(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type busRepository struct {
+	softDelete
 	qb     *dbx.QueryBuilder
 	logger *logger.Logger
 }
 
 func NewBusRepository(logger *logger.Logger, qb *dbx.QueryBuilder) BusRepository {
 	return &busRepository{
-		logger: logger,
-		qb:     qb,
+		softDelete: newSoftDelete(TableBus, logger, qb),
+		logger:     logger,
+		qb:         qb,
 	}
 }
 
-func (b *busRepository) CreateBus(ctx context.Context, bus model.Bus) error {
+func (b *busRepository) CreateBus(ctx context.Context, bus model.BusCreate) error {
+	if _, err := b.insert(ctx, bus); err != nil {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+// CreateMany создаёт автобусы в одной транзакции и возвращает их ID в порядке buses
+func (b *busRepository) CreateMany(ctx context.Context, buses []model.BusCreate) ([]int, error) {
+	ids := make([]int, 0, len(buses))
+	err := bulk(ctx, b.qb, buses, func(ctx context.Context, bus model.BusCreate) error {
+		id, err := b.insert(ctx, bus)
+		ids = append(ids, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (b *busRepository) insert(ctx context.Context, bus model.BusCreate) (int, error) {
 	query, args, err := b.qb.Sq.Insert("bus").
-		Columns("license_plate", "brand", "capacity", "category_id", "status_id", "created_at", "updated_at").
+		Columns("license_plate", "brand", "capacity", "category_id", "status_id").
 		Values(
 			bus.LicensePlate,
 			bus.Brand,
 			bus.Capacity,
 			bus.CategoryID,
 			bus.StatusID,
-			bus.CreatedAt,
-			bus.UpdatedAt,
 		).
+		Suffix("RETURNING id").
 		ToSql()
 
 	if err != nil {
 		b.logger.Error("Failed to build create bus query", "error", err)
-		return err
+		return 0, err
 	}
 
-	_, err = b.qb.DB.ExecContext(ctx, query, args...)
-	if err != nil {
+	var id int
+	if err := b.qb.DB.GetContext(ctx, &id, query, args...); err != nil {
 		b.logger.Error("Failed to create bus", "error", err, "license_plate", bus.LicensePlate)
-		return ErrAlreadyExists
+		return 0, err
 	}
 
-	return nil
+	return id, nil
 }
 
 func (b *busRepository) GetBus(ctx context.Context, id int) (*model.ViewBus, error) {
@@ -65,11 +96,14 @@ func (b *busRepository) GetBus(ctx context.Context, id int) (*model.ViewBus, err
 		"brand",
 		"capacity",
 		"bus_categories.name as category_name",
-		"bus_statuses.name as status_name").
+		"bus_statuses.name as status_name",
+		"bus.updated_at",
+		"bus.deleted_at").
 		From("bus").
 		Join("bus_categories ON bus.category_id = bus_categories.id").
 		Join("bus_statuses ON bus.status_id = bus_statuses.id").
 		Where(sq.Eq{"bus.id": id}).
+		Where(dbx.NotDeleted(ctx, "bus.deleted_at")).
 		ToSql()
 
 	if err != nil {
@@ -87,43 +121,75 @@ func (b *busRepository) GetBus(ctx context.Context, id int) (*model.ViewBus, err
 	return &bus, nil
 }
 
-func (b *busRepository) GetAllBuses(ctx context.Context) ([]model.ViewBus, error) {
-	query, args, err := b.qb.Sq.Select(
+var busPaging = paging.Spec{
+	Fields: map[string]string{
+		"id":            "bus.id",
+		"license_plate": "bus.license_plate",
+		"brand":         "bus.brand",
+		"capacity":      "bus.capacity",
+	},
+	Default: "id",
+	ID:      "bus.id",
+}
+
+// busFilter поля, по которым администратор может фильтровать список автобусов
+var busFilter = filter.Spec{
+	"id":            {Column: "bus.id", Kind: filter.Int},
+	"license_plate": {Column: "bus.license_plate", Kind: filter.String},
+	"brand":         {Column: "bus.brand", Kind: filter.String},
+	"capacity":      {Column: "bus.capacity", Kind: filter.Int},
+	"category_id":   {Column: "bus.category_id", Kind: filter.Int},
+	"status_id":     {Column: "bus.status_id", Kind: filter.Int},
+	"created_at":    {Column: "bus.created_at", Kind: filter.Time},
+}
+
+func (b *busRepository) GetAllBuses(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[model.ViewBus], error) {
+	where, err := busFilter.Build(f)
+	if err != nil {
+		return nil, err
+	}
+
+	query := b.qb.Sq.Select(
 		"bus.id",
 		"license_plate",
 		"brand",
 		"capacity",
 		"bus_categories.name as category_name",
-		"bus_statuses.name as status_name").
+		"bus_statuses.name as status_name",
+		"bus.updated_at",
+		"bus.deleted_at").
 		From("bus").
 		Join("bus_categories ON bus.category_id = bus_categories.id").
 		Join("bus_statuses ON bus.status_id = bus_statuses.id").
-		ToSql()
+		Where(dbx.NotDeleted(ctx, "bus.deleted_at")).
+		Where(where)
 
-	if err != nil {
-		b.logger.Error("Failed to build get all buses query", "error", err)
-		return nil, err
-	}
-
-	var buses []model.ViewBus
-	err = b.qb.DB.SelectContext(ctx, &buses, query, args...)
+	buses, err := paging.Select[model.ViewBus](ctx, b.qb, query, busPaging, p)
 	if err != nil {
 		b.logger.Error("Failed to get all buses", "error", err)
-		return nil, ErrNotFound
+		return nil, err
 	}
 
 	return buses, nil
 }
 
 func (b *busRepository) UpdateBus(ctx context.Context, bus *model.BusUpdate) error {
+	return b.update(ctx, bus)
+}
+
+// UpdateMany обновляет автобусы в одной транзакции
+func (b *busRepository) UpdateMany(ctx context.Context, buses []*model.BusUpdate) error {
+	return bulk(ctx, b.qb, buses, func(ctx context.Context, bus *model.BusUpdate) error {
+		return b.update(ctx, bus)
+	})
+}
+
+// update меняет только переданные поля. Если автобуса нет, возвращает ErrNotFound
+func (b *busRepository) update(ctx context.Context, bus *model.BusUpdate) error {
 	query, args, err := b.qb.Sq.Update("bus").
-		Set("license_plate", bus.LicensePlate).
-		Set("brand", bus.Brand).
-		Set("capacity", bus.Capacity).
-		Set("category_id", bus.CategoryID).
-		Set("status_id", bus.StatusID).
+		SetMap(bus.ToMap()).
 		Set("updated_at", time.Now()).
-		Where(sq.Eq{"bus.id": bus.ID}).
+		Where(sq.Eq{"bus.id": bus.ID, "bus.deleted_at": nil}).
 		ToSql()
 
 	if err != nil {
@@ -131,30 +197,75 @@ func (b *busRepository) UpdateBus(ctx context.Context, bus *model.BusUpdate) err
 		return err
 	}
 
-	_, err = b.qb.DB.ExecContext(ctx, query, args...)
+	res, err := b.qb.DB.ExecContext(ctx, query, args...)
 	if err != nil {
 		b.logger.Error("Failed to update bus", "error", err, "id", bus.ID)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
 		return ErrNotFound
 	}
 
 	return nil
 }
 
-func (b *busRepository) DeleteBus(ctx context.Context, id int) error {
-	query, args, err := b.qb.Sq.Delete("bus").
-		Where(sq.Eq{"bus.id": id}).
+// ForPatch возвращает документ автобуса для PATCH вместе с его версией
+func (b *busRepository) ForPatch(ctx context.Context, id int) (*model.BusPatch, error) {
+	query, args, err := b.qb.Sq.Select(
+		"id",
+		"license_plate",
+		"brand",
+		"capacity",
+		"category_id",
+		"status_id",
+		"updated_at").
+		From(TableBus).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
 		ToSql()
-
 	if err != nil {
-		b.logger.Error("Failed to build delete bus query", "error", err)
-		return err
+		b.logger.Error("Failed to build bus patch source query", "error", err)
+		return nil, err
 	}
 
-	_, err = b.qb.DB.ExecContext(ctx, query, args...)
+	var bus model.BusPatch
+	if err := b.qb.DB.GetContext(ctx, &bus, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &bus, nil
+}
+
+// Patch сохраняет документ, только если запись не менялась с версии bus.UpdatedAt.
+// Возвращает новую версию или ErrVersionConflict
+func (b *busRepository) Patch(ctx context.Context, bus *model.BusPatch) (time.Time, error) {
+	query, args, err := b.qb.Sq.Update(TableBus).
+		Set("license_plate", bus.LicensePlate).
+		Set("brand", bus.Brand).
+		Set("capacity", bus.Capacity).
+		Set("category_id", bus.CategoryID).
+		Set("status_id", bus.StatusID).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": bus.ID, "updated_at": bus.UpdatedAt, "deleted_at": nil}).
+		Suffix("RETURNING updated_at").
+		ToSql()
 	if err != nil {
-		b.logger.Error("Failed to delete bus", "error", err, "id", id)
-		return ErrNotFound
+		b.logger.Error("Failed to build patch bus query", "error", err)
+		return time.Time{}, err
 	}
 
-	return nil
+	var version time.Time
+	if err := b.qb.DB.GetContext(ctx, &version, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrVersionConflict
+		}
+		return time.Time{}, err
+	}
+	return version, nil
+}
+
+// DeleteBus помечает автобус удалённым. Рейсы продолжают ссылаться на него
+func (b *busRepository) DeleteBus(ctx context.Context, id int) error {
+	return b.markDeleted(ctx, id)
 }
@@ -15,23 +15,28 @@ type BusCategory interface {
 	Create(ctx context.Context, category model.BusCategory) error
 	Update(ctx context.Context, category model.BusCategory) (model.BusCategory, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type busCategory struct {
+	softDelete
 	qb     *dbx.QueryBuilder
 	logger *logger.Logger
 }
 
 func NewBusCategory(logger *logger.Logger, qb *dbx.QueryBuilder) BusCategory {
 	return &busCategory{
-		qb:     qb,
-		logger: logger,
+		softDelete: newSoftDelete(TableBusCategories, logger, qb),
+		qb:         qb,
+		logger:     logger,
 	}
 }
 
 func (b *busCategory) GetAll(ctx context.Context) ([]model.BusCategory, error) {
-	query, args, err := b.qb.Sq.Select("*").
+	query, args, err := b.qb.Sq.Select("id", "name", "deleted_at").
 		From(TableBusCategories).
+		Where(dbx.NotDeleted(ctx, "deleted_at")).
+		OrderBy("id").
 		ToSql()
 
 	if err != nil {
@@ -52,7 +57,7 @@ func (b *busCategory) GetAll(ctx context.Context) ([]model.BusCategory, error) {
 	var busCategories []model.BusCategory
 	for rows.Next() {
 		var busCategory model.BusCategory
-		if err := rows.Scan(&busCategory.ID, &busCategory.Name); err != nil {
+		if err := rows.Scan(&busCategory.ID, &busCategory.Name, &busCategory.DeletedAt); err != nil {
 			b.logger.Error("Failed to scan bus category", "error", err)
 			return nil, err
 		}
@@ -63,9 +68,10 @@ func (b *busCategory) GetAll(ctx context.Context) ([]model.BusCategory, error) {
 }
 
 func (b *busCategory) GetById(ctx context.Context, id int) (*model.BusCategory, error) {
-	query, args, err := b.qb.Sq.Select("*").
+	query, args, err := b.qb.Sq.Select("id", "name", "deleted_at").
 		From(TableBusCategories).
 		Where(sq.Eq{"id": id}).
+		Where(dbx.NotDeleted(ctx, "deleted_at")).
 		ToSql()
 
 	if err != nil {
@@ -103,7 +109,7 @@ func (b *busCategory) Create(ctx context.Context, category model.BusCategory) er
 func (b *busCategory) Update(ctx context.Context, category model.BusCategory) (model.BusCategory, error) {
 	query, args, err := b.qb.Sq.Update(TableBusCategories).
 		Set("name", category.Name).
-		Where(sq.Eq{"id": category.ID}).
+		Where(sq.Eq{"id": category.ID, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		b.logger.Errorf("failed to create query: %s \n err: %v", query, err)
@@ -119,14 +125,7 @@ func (b *busCategory) Update(ctx context.Context, category model.BusCategory) (m
 	return category, nil
 }
 
+// Delete помечает запись удалённой
 func (b *busCategory) Delete(ctx context.Context, id int) error {
-	query, args, err := b.qb.Sq.Delete(TableBusCategories).
-		Where(sq.Eq{"id": id}).
-		ToSql()
-	if err != nil {
-		return err
-	}
-
-	_, err = b.qb.DB.ExecContext(ctx, query, args...)
-	return err
+	return b.markDeleted(ctx, id)
 }
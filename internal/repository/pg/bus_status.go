@@ -15,23 +15,28 @@ type BusStatus interface {
 	Create(ctx context.Context, status model.BusStatus) error
 	Update(ctx context.Context, status model.BusStatus) (model.BusStatus, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type busStatus struct {
+	softDelete
 	logger *logger.Logger
 	qb     *dbx.QueryBuilder
 }
 
 func NewBusStatus(logger *logger.Logger, qb *dbx.QueryBuilder) BusStatus {
 	return &busStatus{
-		logger: logger,
-		qb:     qb,
+		softDelete: newSoftDelete(TableBusStatuses, logger, qb),
+		logger:     logger,
+		qb:         qb,
 	}
 }
 
 func (b *busStatus) All(ctx context.Context) ([]model.BusStatus, error) {
-	query, args, err := b.qb.Sq.Select("*").
+	query, args, err := b.qb.Sq.Select("id", "name", "deleted_at").
 		From(TableBusStatuses).
+		Where(dbx.NotDeleted(ctx, "deleted_at")).
+		OrderBy("id").
 		ToSql()
 	if err != nil {
 		b.logger.Error("Failed to build select bus category query", "error", err)
@@ -51,7 +56,7 @@ func (b *busStatus) All(ctx context.Context) ([]model.BusStatus, error) {
 	var busCategories []model.BusStatus
 	for rows.Next() {
 		var busStatus model.BusStatus
-		if err := rows.Scan(&busStatus.ID, &busStatus.Name); err != nil {
+		if err := rows.Scan(&busStatus.ID, &busStatus.Name, &busStatus.DeletedAt); err != nil {
 			b.logger.Error("Failed to scan bus category", "error", err)
 			return nil, err
 		}
@@ -62,9 +67,10 @@ func (b *busStatus) All(ctx context.Context) ([]model.BusStatus, error) {
 }
 
 func (b *busStatus) ByID(ctx context.Context, id int) (model.BusStatus, error) {
-	query, args, err := b.qb.Sq.Select("*").
+	query, args, err := b.qb.Sq.Select("id", "name", "deleted_at").
 		From(TableBusStatuses).
 		Where(sq.Eq{"id": id}).
+		Where(dbx.NotDeleted(ctx, "deleted_at")).
 		ToSql()
 	if err != nil {
 		b.logger.Error("Failed to build select bus category query", "error", err)
@@ -103,7 +109,7 @@ func (b *busStatus) Create(ctx context.Context, status model.BusStatus) error {
 func (b *busStatus) Update(ctx context.Context, status model.BusStatus) (model.BusStatus, error) {
 	query, args, err := b.qb.Sq.Update(TableBusStatuses).
 		Set("name", status.Name).
-		Where(sq.Eq{"id": status.ID}).
+		Where(sq.Eq{"id": status.ID, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		b.logger.Error("Failed to build update bus category query", "error", err)
@@ -119,14 +125,7 @@ func (b *busStatus) Update(ctx context.Context, status model.BusStatus) (model.B
 	return status, nil
 }
 
+// Delete помечает запись удалённой
 func (b *busStatus) Delete(ctx context.Context, id int) error {
-	query, args, err := b.qb.Sq.Delete(TableBusStatuses).
-		Where(sq.Eq{"id": id}).
-		ToSql()
-	if err != nil {
-		return err
-	}
-
-	_, err = b.qb.DB.ExecContext(ctx, query, args...)
-	return err
+	return b.markDeleted(ctx, id)
 }
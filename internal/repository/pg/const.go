@@ -1,14 +1,25 @@
 package pg
 
 const (
-	TableUsers         = "users"
-	TableBus           = "bus"
-	TableBusCategories = "bus_categories"
-	TableBusStatuses   = "bus_statuses"
-	TableDriver        = "drivers"
-	TableDriverStatus  = "driver_status"
-	TableTrip          = "trips"
-	TableTripStop      = "trip_stops"
-	TableStop          = "stops"
-	TableRefreshToken  = "refresh_tokens"
+	TableUsers           = "users"
+	TableBus             = "bus"
+	TableBusCategories   = "bus_categories"
+	TableBusStatuses     = "bus_statuses"
+	TableDriver          = "drivers"
+	TableDriverStatus    = "driver_status"
+	TableTrip            = "trips"
+	TableTripStop        = "trip_stops"
+	TableStop            = "stops"
+	TableRefreshToken    = "refresh_tokens"
+	TableUserTokens      = "user_tokens"
+	TableSecurityEvents  = "security_events"
+	TableRoles           = "roles"
+	TablePermissions     = "permissions"
+	TableRolePermissions = "role_permissions"
+	TableAPIKeys         = "api_keys"
+	TablePassengers      = "passengers"
+	TableUserIdentities  = "user_identities"
+	TableOrders          = "orders"
+	TableOrderItems      = "order_items"
+	TableOrderStatuses   = "order_statuses"
 )
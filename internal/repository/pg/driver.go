@@ -4,47 +4,75 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/model"
 	"corpord-api/pkg/dbx"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
 	sq "github.com/Masterminds/squirrel"
 	"golang.org/x/net/context"
 )
 
 type Driver interface {
-	All(ctx context.Context) ([]model.DriverOutput, error)
+	All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[model.DriverOutput], error)
 	ByID(ctx context.Context, id int) (model.DriverOutput, error)
 	Create(ctx context.Context, driver model.DriverInput) error
+	CreateMany(ctx context.Context, drivers []model.DriverInput) ([]int, error)
 	Update(ctx context.Context, driver model.DriverInput) error
+	UpdateMany(ctx context.Context, drivers []model.DriverInput) error
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type driver struct {
+	softDelete
 	logger *logger.Logger
 	qb     *dbx.QueryBuilder
 }
 
 func NewDriver(logger *logger.Logger, qb *dbx.QueryBuilder) Driver {
 	return &driver{
-		logger: logger,
-		qb:     qb,
+		softDelete: newSoftDelete(TableDriver, logger, qb),
+		logger:     logger,
+		qb:         qb,
 	}
 }
 
-func (d *driver) All(ctx context.Context) ([]model.DriverOutput, error) {
+var driverPaging = paging.Spec{
+	Fields: map[string]string{
+		"id":         "drivers.id",
+		"last_name":  "drivers.last_name",
+		"first_name": "drivers.first_name",
+	},
+	Default: "id",
+	ID:      "drivers.id",
+}
+
+var driverFilter = filter.Spec{
+	"id":           {Column: "drivers.id", Kind: filter.Int},
+	"first_name":   {Column: "drivers.first_name", Kind: filter.String},
+	"last_name":    {Column: "drivers.last_name", Kind: filter.String},
+	"phone_number": {Column: "drivers.phone_number", Kind: filter.String},
+	"status_id":    {Column: "drivers.status", Kind: filter.Int},
+}
+
+func (d *driver) All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[model.DriverOutput], error) {
 	d.logger.Debug("All Repository")
-	query, args, err := d.qb.Sq.Select(
+	where, err := driverFilter.Build(f)
+	if err != nil {
+		return nil, err
+	}
+	query := d.qb.Sq.Select(
+		"drivers.id",
 		"first_name",
 		"last_name",
 		"middle_name",
 		"phone_number",
-		"ds.name as driver_status").
+		"ds.name as driver_status",
+		"drivers.deleted_at").
 		From(TableDriver).
 		Join("driver_status ds ON ds.id = drivers.status").
-		ToSql()
-	if err != nil {
-		d.logger.Error("Failed to build query", err)
-		return nil, err
-	}
-	var drivers []model.DriverOutput
-	err = d.qb.DB.SelectContext(ctx, &drivers, query, args...)
+		Where(dbx.NotDeleted(ctx, "drivers.deleted_at")).
+		Where(where)
+
+	drivers, err := paging.Select[model.DriverOutput](ctx, d.qb, query, driverPaging, p)
 	if err != nil {
 		d.logger.Error("Failed to execute query", err)
 		return nil, err
@@ -58,10 +86,13 @@ func (d *driver) ByID(ctx context.Context, id int) (model.DriverOutput, error) {
 		"last_name",
 		"middle_name",
 		"phone_number",
-		"ds.name as driver_status").
+		"ds.name as driver_status",
+		"drivers.deleted_at").
 		From(TableDriver).
 		Join(`driver_status ds ON ds.id = drivers.status`).
-		Where(sq.Eq{"drivers.id": id}).ToSql()
+		Where(sq.Eq{"drivers.id": id}).
+		Where(dbx.NotDeleted(ctx, "drivers.deleted_at")).
+		ToSql()
 	if err != nil {
 		d.logger.Error("Failed to build query", err)
 		return model.DriverOutput{}, err
@@ -76,6 +107,25 @@ func (d *driver) ByID(ctx context.Context, id int) (model.DriverOutput, error) {
 }
 
 func (d *driver) Create(ctx context.Context, driver model.DriverInput) error {
+	_, err := d.insert(ctx, driver)
+	return err
+}
+
+// CreateMany создаёт водителей в одной транзакции и возвращает их ID в порядке drivers
+func (d *driver) CreateMany(ctx context.Context, drivers []model.DriverInput) ([]int, error) {
+	ids := make([]int, 0, len(drivers))
+	err := bulk(ctx, d.qb, drivers, func(ctx context.Context, driver model.DriverInput) error {
+		id, err := d.insert(ctx, driver)
+		ids = append(ids, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (d *driver) insert(ctx context.Context, driver model.DriverInput) (int, error) {
 	query, args, err := d.qb.Sq.Insert(TableDriver).Columns(
 		"first_name",
 		"last_name",
@@ -88,54 +138,59 @@ func (d *driver) Create(ctx context.Context, driver model.DriverInput) error {
 			driver.MiddleName,
 			driver.PhoneNumber,
 			driver.Status).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
 		d.logger.Error("Failed to build query", err)
-		return err
+		return 0, err
 	}
-	_, err = d.qb.DB.ExecContext(ctx, query, args...)
-	if err != nil {
+	var id int
+	if err := d.qb.DB.GetContext(ctx, &id, query, args...); err != nil {
 		d.logger.Error("Failed to execute query", err)
-		return err
+		return 0, err
 	}
 
-	return nil
+	return id, nil
 }
 
+// Update обновляет водителя. Если водителя нет, возвращает ErrNotFound
 func (d *driver) Update(ctx context.Context, driver model.DriverInput) error {
+	return d.update(ctx, driver)
+}
+
+// UpdateMany обновляет водителей в одной транзакции
+func (d *driver) UpdateMany(ctx context.Context, drivers []model.DriverInput) error {
+	return bulk(ctx, d.qb, drivers, func(ctx context.Context, driver model.DriverInput) error {
+		return d.update(ctx, driver)
+	})
+}
+
+func (d *driver) update(ctx context.Context, driver model.DriverInput) error {
 	query, args, err := d.qb.Sq.Update(TableDriver).
 		Set("first_name", driver.FirstName).
 		Set("last_name", driver.LastName).
 		Set("middle_name", driver.MiddleName).
 		Set("phone_number", driver.PhoneNumber).
 		Set("status", driver.Status).
-		Where(sq.Eq{"id": driver.ID}).
+		Where(sq.Eq{"id": driver.ID, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		d.logger.Error("Failed to build query", err)
 		return err
 	}
 
-	_, err = d.qb.DB.ExecContext(ctx, query, args...)
+	res, err := d.qb.DB.ExecContext(ctx, query, args...)
 	if err != nil {
 		d.logger.Error("Failed to execute query", err)
 		return err
 	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
 	return nil
 }
 
+// Delete помечает водителя удалённым. В прошедших рейсах он остаётся
 func (d *driver) Delete(ctx context.Context, id int) error {
-	query, args, err := d.qb.Sq.Delete(TableDriver).
-		Where(sq.Eq{"id": id}).
-		ToSql()
-	if err != nil {
-		d.logger.Error("Failed to build query", err)
-		return err
-	}
-	_, err = d.qb.DB.ExecContext(ctx, query, args...)
-	if err != nil {
-		d.logger.Error("Failed to execute query", err)
-		return err
-	}
-	return nil
+	return d.markDeleted(ctx, id)
 }
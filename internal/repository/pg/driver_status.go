@@ -14,23 +14,30 @@ type DriverStatus interface {
 	Create(ctx context.Context, status *model.DriverStatus) error
 	Update(ctx context.Context, status *model.DriverStatus) error
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type driverStatus struct {
+	softDelete
 	logger *logger.Logger
 	qb     *dbx.QueryBuilder
 }
 
 func NewDriverStatus(logger *logger.Logger, qb *dbx.QueryBuilder) DriverStatus {
 	return &driverStatus{
-		logger: logger,
-		qb:     qb,
+		softDelete: newSoftDelete(TableDriverStatus, logger, qb),
+		logger:     logger,
+		qb:         qb,
 	}
 }
 
 func (d *driverStatus) All(ctx context.Context) []model.DriverStatus {
 	var results []model.DriverStatus
-	query, args, err := d.qb.Sq.Select("id, name").From(TableDriverStatus).ToSql()
+	query, args, err := d.qb.Sq.Select("id", "name", "deleted_at").
+		From(TableDriverStatus).
+		Where(dbx.NotDeleted(ctx, "deleted_at")).
+		OrderBy("id").
+		ToSql()
 	if err != nil {
 		d.logger.Error(err)
 		return []model.DriverStatus{}
@@ -44,9 +51,10 @@ func (d *driverStatus) All(ctx context.Context) []model.DriverStatus {
 }
 
 func (d *driverStatus) ById(ctx context.Context, id int) (model.DriverStatus, error) {
-	query, args, err := d.qb.Sq.Select("id, name").
+	query, args, err := d.qb.Sq.Select("id", "name", "deleted_at").
 		From(TableDriverStatus).
 		Where(sq.Eq{"id": id}).
+		Where(dbx.NotDeleted(ctx, "deleted_at")).
 		ToSql()
 	if err != nil {
 		d.logger.Error(err)
@@ -81,7 +89,7 @@ func (d *driverStatus) Create(ctx context.Context, status *model.DriverStatus) e
 func (d *driverStatus) Update(ctx context.Context, status *model.DriverStatus) error {
 	query, args, err := d.qb.Sq.Update(TableDriverStatus).
 		Set("name", status.Name).
-		Where(sq.Eq{"id": status.ID}).
+		Where(sq.Eq{"id": status.ID, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		d.logger.Error(err)
@@ -95,15 +103,7 @@ func (d *driverStatus) Update(ctx context.Context, status *model.DriverStatus) e
 	return nil
 }
 
+// Delete помечает статус водителя удалённым
 func (d *driverStatus) Delete(ctx context.Context, id int) error {
-	query, args, err := d.qb.Sq.Delete(TableDriverStatus).Where(sq.Eq{"id": id}).ToSql()
-	if err != nil {
-		d.logger.Error(err)
-		return err
-	}
-	_, err = d.qb.DB.ExecContext(ctx, query, args...)
-	if err != nil {
-		d.logger.Error(err)
-	}
-	return err
+	return d.markDeleted(ctx, id)
 }
@@ -0,0 +1,170 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"corpord-api/pkg/dbx"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Erasure запросы на удаление персональных данных и их обезличивание
+type Erasure interface {
+	// Request ставит пользователя в очередь на обезличивание. Повторный запрос не сдвигает срок
+	Request(ctx context.Context, userID int) (time.Time, error)
+	// Cancel отменяет запрос, пока данные ещё не обезличены
+	Cancel(ctx context.Context, userID int) error
+	// Due возвращает пользователей, запросивших удаление раньше before
+	Due(ctx context.Context, before time.Time, limit uint64) ([]int, error)
+	// Deleted возвращает пользователей, мягко удалённых раньше before и ещё не обезличенных
+	Deleted(ctx context.Context, before time.Time, limit uint64) ([]int, error)
+	// Anonymize обезличивает пользователя в одной транзакции. Суммы заказов и ссылки на рейсы сохраняются
+	Anonymize(ctx context.Context, userID int) error
+}
+
+type erasure struct {
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func NewErasure(logger *logger.Logger, qb *dbx.QueryBuilder) Erasure {
+	return &erasure{
+		logger: logger,
+		qb:     qb,
+	}
+}
+
+func (r *erasure) Request(ctx context.Context, userID int) (time.Time, error) {
+	query, args, err := r.qb.Sq.Update(TableUsers).
+		Set("erasure_requested_at", sq.Expr("COALESCE(erasure_requested_at, now())")).
+		Where(sq.Eq{"id": userID, "deleted_at": nil, "anonymized_at": nil}).
+		Suffix("RETURNING erasure_requested_at").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return time.Time{}, err
+	}
+
+	var requestedAt time.Time
+	if err = r.qb.DB.QueryRowxContext(ctx, query, args...).Scan(&requestedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrNotFound
+		}
+		r.logger.Error(err)
+		return time.Time{}, err
+	}
+	return requestedAt, nil
+}
+
+func (r *erasure) Cancel(ctx context.Context, userID int) error {
+	query, args, err := r.qb.Sq.Update(TableUsers).
+		Set("erasure_requested_at", nil).
+		Where(sq.Eq{"id": userID, "anonymized_at": nil}).
+		Where(sq.NotEq{"erasure_requested_at": nil}).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	res, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *erasure) Due(ctx context.Context, before time.Time, limit uint64) ([]int, error) {
+	return r.pending(ctx, sq.LtOrEq{"erasure_requested_at": before}, limit)
+}
+
+func (r *erasure) Deleted(ctx context.Context, before time.Time, limit uint64) ([]int, error) {
+	return r.pending(ctx, sq.LtOrEq{"deleted_at": before}, limit)
+}
+
+// pending выбирает ещё не обезличенных пользователей по условию cond
+func (r *erasure) pending(ctx context.Context, cond sq.Sqlizer, limit uint64) ([]int, error) {
+	query, args, err := r.qb.Sq.Select("id").
+		From(TableUsers).
+		Where(sq.Eq{"anonymized_at": nil}).
+		Where(cond).
+		OrderBy("id").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	var ids []int
+	if err = r.qb.DB.SelectContext(ctx, &ids, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *erasure) Anonymize(ctx context.Context, userID int) error {
+	return r.qb.Tx.Do(ctx, func(ctx context.Context) error {
+		statements := []sq.Sqlizer{
+			r.qb.Sq.Update(TableUsers).
+				Set("email", nil).
+				Set("email_verified", false).
+				Set("name", model.AnonymizedName).
+				Set("password_hash", nil).
+				Set("phone", nil).
+				Set("phone_verified", false).
+				Set("anonymized_at", sq.Expr("now()")).
+				Set("deleted_at", sq.Expr("COALESCE(deleted_at, now())")).
+				Set("updated_at", sq.Expr("now()")).
+				Where(sq.Eq{"id": userID, "anonymized_at": nil}),
+			r.qb.Sq.Delete(TableUserIdentities).Where(sq.Eq{"user_id": userID}),
+			r.qb.Sq.Delete(TableRefreshToken).Where(sq.Eq{"user_id": userID}),
+			r.qb.Sq.Delete(TableUserTokens).Where(sq.Eq{"user_id": userID}),
+			r.qb.Sq.Delete(TablePassengers).Where(sq.Eq{"user_id": userID}),
+			r.qb.Sq.Update(TableAPIKeys).
+				Set("revoked_at", sq.Expr("COALESCE(revoked_at, now())")).
+				Where(sq.Eq{"user_id": userID}),
+			r.qb.Sq.Update(TableSecurityEvents).
+				Set("email", nil).
+				Set("ip", nil).
+				Set("user_agent", nil).
+				Where(sq.Eq{"user_id": userID}),
+			// заказы остаются для бухгалтерии: суммы, статусы и рейсы не трогаем
+			r.qb.Sq.Update(TableOrders).
+				Set("contact_name", model.AnonymizedName).
+				Set("contact_phone", "").
+				Set("contact_email", nil).
+				Set("notes", nil).
+				Set("ip_address", nil).
+				Set("user_agent", nil).
+				Where(sq.Eq{"user_id": userID}),
+			r.qb.Sq.Update(TableOrderItems).
+				Set("passenger_name", model.AnonymizedName).
+				Set("passenger_document_number", nil).
+				Where(sq.Expr("order_id IN (SELECT id FROM "+TableOrders+" WHERE user_id = ?)", userID)),
+		}
+
+		for _, stmt := range statements {
+			query, args, err := stmt.ToSql()
+			if err != nil {
+				r.logger.Error(err)
+				return err
+			}
+			if _, err = r.qb.DB.ExecContext(ctx, query, args...); err != nil {
+				r.logger.Errorf("failed to anonymize user %d: %v", userID, err)
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -31,6 +31,9 @@ var (
 
 	// ErrNoRowsAffected возвращается, когда операция не затронула ни одной строки.
 	ErrNoRowsAffected = errors.New("no rows affected")
+
+	// ErrVersionConflict возвращается, когда запись изменили после того, как её прочитали.
+	ErrVersionConflict = errors.New("version conflict")
 )
 
 // IsPgError проверяет, является ли ошибка ошибкой PostgreSQL с указанным кодом.
@@ -49,3 +52,11 @@ func IsPgError(err error, code string) bool {
 
 	return pgErr.Code == code
 }
+
+// nullIfEmpty превращает пустую строку в NULL (например, email пользователя из Telegram)
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"corpord-api/pkg/dbx"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Order заказы и билеты пользователей
+type Order interface {
+	// ByUser возвращает заказы пользователя вместе с позициями
+	ByUser(ctx context.Context, userID int) ([]*model.Order, error)
+}
+
+type order struct {
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func NewOrder(logger *logger.Logger, qb *dbx.QueryBuilder) Order {
+	return &order{
+		logger: logger,
+		qb:     qb,
+	}
+}
+
+func (r *order) ByUser(ctx context.Context, userID int) ([]*model.Order, error) {
+	query, args, err := r.qb.Sq.Select(
+		"o.id", "o.order_number", "o.user_id", "o.contact_name", "o.contact_phone", "o.contact_email",
+		"s.code AS status", "o.total_amount", "o.payment_method", "o.payment_status", "o.notes",
+		"o.created_at", "o.updated_at",
+	).
+		From(TableOrders + " o").
+		Join(TableOrderStatuses + " s ON s.id = o.status_id").
+		Where(sq.Eq{"o.user_id": userID}).
+		OrderBy("o.id").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	orders := make([]*model.Order, 0)
+	if err = r.qb.DB.SelectContext(ctx, &orders, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	ids := make([]int, 0, len(orders))
+	byID := make(map[int]*model.Order, len(orders))
+	for _, o := range orders {
+		o.Items = make([]*model.OrderItem, 0)
+		ids = append(ids, o.ID)
+		byID[o.ID] = o
+	}
+
+	query, args, err = r.qb.Sq.Select(
+		"id", "order_id", "trip_id", "departure_stop_id", "arrival_stop_id", "passenger_name",
+		"passenger_document_number", "seat_number", "price", "created_at",
+	).
+		From(TableOrderItems).
+		Where(sq.Eq{"order_id": ids}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	var items []*model.OrderItem
+	if err = r.qb.DB.SelectContext(ctx, &items, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+	for _, item := range items {
+		o := byID[item.OrderID]
+		o.Items = append(o.Items, item)
+	}
+
+	return orders, nil
+}
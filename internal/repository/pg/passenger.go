@@ -0,0 +1,144 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"corpord-api/pkg/dbx"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+var passengerColumns = []string{
+	"id", "user_id", "first_name", "last_name", "middle_name", "birth_date",
+	"document_type", "document_number", "created_at", "updated_at",
+}
+
+// Passenger сохранённые пассажиры пользователя. Все операции ограничены владельцем
+type Passenger interface {
+	ByUser(ctx context.Context, userID int) ([]*model.Passenger, error)
+	ByID(ctx context.Context, userID, id int) (*model.Passenger, error)
+	Create(ctx context.Context, p *model.Passenger) (int, error)
+	Update(ctx context.Context, p *model.Passenger) error
+	Delete(ctx context.Context, userID, id int) error
+}
+
+type passenger struct {
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func NewPassenger(logger *logger.Logger, qb *dbx.QueryBuilder) Passenger {
+	return &passenger{
+		logger: logger,
+		qb:     qb,
+	}
+}
+
+func (r *passenger) ByUser(ctx context.Context, userID int) ([]*model.Passenger, error) {
+	query, args, err := r.qb.Sq.Select(passengerColumns...).
+		From(TablePassengers).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	out := make([]*model.Passenger, 0)
+	if err = r.qb.DB.SelectContext(ctx, &out, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *passenger) ByID(ctx context.Context, userID, id int) (*model.Passenger, error) {
+	query, args, err := r.qb.Sq.Select(passengerColumns...).
+		From(TablePassengers).
+		Where(sq.Eq{"id": id, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	var out model.Passenger
+	if err = r.qb.DB.GetContext(ctx, &out, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		r.logger.Error(err)
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *passenger) Create(ctx context.Context, p *model.Passenger) (int, error) {
+	query, args, err := r.qb.Sq.Insert(TablePassengers).
+		Columns("user_id", "first_name", "last_name", "middle_name", "birth_date", "document_type", "document_number").
+		Values(p.UserID, p.FirstName, p.LastName, p.MiddleName, p.BirthDate, p.DocumentType, p.DocumentNumber).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return 0, err
+	}
+
+	var id int
+	if err = r.qb.DB.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+		r.logger.Error(err)
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *passenger) Update(ctx context.Context, p *model.Passenger) error {
+	query, args, err := r.qb.Sq.Update(TablePassengers).
+		Set("first_name", p.FirstName).
+		Set("last_name", p.LastName).
+		Set("middle_name", p.MiddleName).
+		Set("birth_date", p.BirthDate).
+		Set("document_type", p.DocumentType).
+		Set("document_number", p.DocumentNumber).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": p.ID, "user_id": p.UserID}).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	res, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *passenger) Delete(ctx context.Context, userID, id int) error {
+	query, args, err := r.qb.Sq.Delete(TablePassengers).
+		Where(sq.Eq{"id": id, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	res, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -5,7 +5,9 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/model"
 	"corpord-api/pkg/dbx"
+	"database/sql"
 	"errors"
+	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
@@ -18,6 +20,7 @@ type RefreshTokenRepository interface {
 	FindByHash(ctx context.Context, hash string) (*model.RefreshSession, error)
 	Revoke(ctx context.Context, id uuid.UUID) error
 	RevokeAllByUser(ctx context.Context, userID int) error
+	ByUser(ctx context.Context, userID int) ([]*model.RefreshSession, error)
 	RefreshToken(ctx context.Context, oldHash string, newSession *model.RefreshSession) error
 	CleanupExpired(ctx context.Context) error
 }
@@ -89,8 +92,11 @@ func (r *refreshTokenRepo) FindByHash(ctx context.Context, hash string) (*model.
 
 	err = r.qb.DB.GetContext(ctx, rt, query, args...)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRefreshTokenNotFound
+		}
 		r.logger.Error(err)
-		return nil, ErrRefreshTokenNotFound
+		return nil, err
 	}
 
 	return rt, nil
@@ -115,6 +121,33 @@ func (r *refreshTokenRepo) Revoke(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+// ByUser возвращает все сессии пользователя, включая отозванные
+func (r *refreshTokenRepo) ByUser(ctx context.Context, userID int) ([]*model.RefreshSession, error) {
+	query, args, err := r.qb.Sq.Select(
+		"id",
+		"user_id",
+		"expires_at",
+		"created_at",
+		"revoked",
+		"host(ip) AS ip",
+		"user_agent",
+	).From(TableRefreshToken).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	sessions := make([]*model.RefreshSession, 0)
+	if err = r.qb.DB.SelectContext(ctx, &sessions, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+	return sessions, nil
+}
+
 // RevokeAllByUser отзывает все токены пользователя
 func (r *refreshTokenRepo) RevokeAllByUser(ctx context.Context, userID int) error {
 	query, args, err := r.qb.Sq.Update(TableRefreshToken).
@@ -139,40 +172,38 @@ func (r *refreshTokenRepo) RefreshToken(
 	oldHash string,
 	newSession *model.RefreshSession,
 ) error {
-	tx, err := r.qb.DB.BeginTxx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	// Найти старый токен
-	oldToken := &model.RefreshSession{}
-	query, args, _ := r.qb.Sq.Select("id", "revoked").
-		From(TableRefreshToken).
-		Where(sq.Eq{"token_hash": oldHash, "revoked": false}).
-		ToSql()
-
-	if err := tx.GetContext(ctx, oldToken, query, args...); err != nil {
-		return ErrRefreshTokenNotFound
-	}
-
-	// Отозвать старый токен
-	_, err = tx.ExecContext(ctx,
-		"UPDATE refresh_tokens SET revoked=true WHERE id=$1", oldToken.ID)
-	if err != nil {
+	return r.qb.Tx.Do(ctx, func(ctx context.Context) error {
+		// Найти старый токен
+		oldToken := &model.RefreshSession{}
+		query, args, _ := r.qb.Sq.Select("id", "revoked").
+			From(TableRefreshToken).
+			Where(sq.Eq{"token_hash": oldHash, "revoked": false}).
+			ToSql()
+
+		// только отсутствие строки означает чужой или отозванный токен; ошибки базы
+		// (в том числе конфликт сериализации) возвращаются как есть, чтобы Tx.Do мог повторить транзакцию
+		if err := r.qb.DB.GetContext(ctx, oldToken, query, args...); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrRefreshTokenNotFound
+			}
+			return fmt.Errorf("failed to find refresh token: %w", err)
+		}
+
+		// Отозвать старый токен
+		_, err := r.qb.DB.ExecContext(ctx,
+			"UPDATE refresh_tokens SET revoked=true WHERE id=$1", oldToken.ID)
+		if err != nil {
+			return err
+		}
+
+		// Сохранить новый токен (только хеш и метаданные)
+		query, args, _ = r.qb.Sq.Insert(TableRefreshToken).
+			Columns("id", "user_id", "token_hash", "expires_at", "ip", "user_agent").
+			Values(newSession.ID, newSession.UserID, newSession.TokenHash, newSession.ExpiresAt, newSession.IP, newSession.UserAgent).
+			ToSql()
+		_, err = r.qb.DB.ExecContext(ctx, query, args...)
 		return err
-	}
-
-	// Сохранить новый токен (только хеш и метаданные)
-	query, args, _ = r.qb.Sq.Insert(TableRefreshToken).
-		Columns("id", "user_id", "token_hash", "expires_at", "ip", "user_agent").
-		Values(newSession.ID, newSession.UserID, newSession.TokenHash, newSession.ExpiresAt, newSession.IP, newSession.UserAgent).
-		ToSql()
-	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
-		return err
-	}
-
-	return tx.Commit()
+	})
 }
 
 // CleanupExpired удаляет все истёкшие refresh-токены
@@ -6,35 +6,53 @@ import (
 )
 
 type PostgresRepository struct {
-	logger       *logger.Logger
-	User         UserRepository
-	Auth         AuthRepository
-	RefreshToken RefreshTokenRepository
-	UserIdentity UserIdentitiesRepository
-	Bus          BusRepository
-	Bc           BusCategory
-	Bs           BusStatus
-	Ds           DriverStatus
-	Driver       Driver
-	Trip         Trip
-	TripStop     TripStop
-	Stop         Stop
+	logger        *logger.Logger
+	Tx            dbx.Transactor
+	User          UserRepository
+	Auth          AuthRepository
+	RefreshToken  RefreshTokenRepository
+	UserToken     UserTokenRepository
+	SecurityEvent SecurityEventRepository
+	UserIdentity  UserIdentitiesRepository
+	Bus           BusRepository
+	Bc            BusCategory
+	Bs            BusStatus
+	Ds            DriverStatus
+	Driver        Driver
+	Trip          Trip
+	TripStop      TripStop
+	Stop          Stop
+	Role          Role
+	APIKey        APIKeyRepository
+	Passenger     Passenger
+	Order         Order
+	Erasure       Erasure
+	Purge         Purge
 }
 
 func New(logger *logger.Logger, qb *dbx.QueryBuilder) *PostgresRepository {
 	return &PostgresRepository{
-		logger:       logger,
-		User:         NewUserRepository(logger, qb),
-		Auth:         NewAuthRepository(logger, qb),
-		RefreshToken: NewRefreshTokenRepo(logger, qb),
-		UserIdentity: NewUserIdentitiesRepo(logger, qb),
-		Bus:          NewBusRepository(logger, qb),
-		Bc:           NewBusCategory(logger, qb),
-		Bs:           NewBusStatus(logger, qb),
-		Ds:           NewDriverStatus(logger, qb),
-		Driver:       NewDriver(logger, qb),
-		Trip:         NewTrip(logger, qb),
-		TripStop:     NewTripStop(logger, qb),
-		Stop:         NewStop(logger, qb),
+		logger:        logger,
+		Tx:            qb.Tx,
+		User:          NewUserRepository(logger, qb),
+		Auth:          NewAuthRepository(logger, qb),
+		RefreshToken:  NewRefreshTokenRepo(logger, qb),
+		UserToken:     NewUserTokenRepo(logger, qb),
+		SecurityEvent: NewSecurityEventRepo(logger, qb),
+		UserIdentity:  NewUserIdentitiesRepo(logger, qb),
+		Bus:           NewBusRepository(logger, qb),
+		Bc:            NewBusCategory(logger, qb),
+		Bs:            NewBusStatus(logger, qb),
+		Ds:            NewDriverStatus(logger, qb),
+		Driver:        NewDriver(logger, qb),
+		Trip:          NewTrip(logger, qb),
+		TripStop:      NewTripStop(logger, qb),
+		Stop:          NewStop(logger, qb),
+		Role:          NewRole(logger, qb),
+		APIKey:        NewAPIKeyRepo(logger, qb),
+		Passenger:     NewPassenger(logger, qb),
+		Order:         NewOrder(logger, qb),
+		Erasure:       NewErasure(logger, qb),
+		Purge:         NewPurge(logger, qb),
 	}
 }
@@ -0,0 +1,308 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"corpord-api/pkg/dbx"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ErrUnknownPermission возвращается, если среди кодов есть отсутствующее в таблице permissions право
+var ErrUnknownPermission = errors.New("unknown permission")
+
+// Role работа с ролями и их правами
+type Role interface {
+	All(ctx context.Context) ([]*model.Role, error)
+	ByID(ctx context.Context, id int) (*model.Role, error)
+	Create(ctx context.Context, role *model.RoleCreate) (int, error)
+	SetPermissions(ctx context.Context, roleID int, codes []string) error
+	PermissionsByRole(ctx context.Context, roleName string) ([]string, error)
+	AllPermissions(ctx context.Context) ([]model.Permission, error)
+	AssignToUser(ctx context.Context, userID, roleID int) error
+}
+
+type role struct {
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func NewRole(logger *logger.Logger, qb *dbx.QueryBuilder) Role {
+	return &role{
+		logger: logger,
+		qb:     qb,
+	}
+}
+
+type rolePermissionRow struct {
+	RoleID int    `db:"role_id"`
+	Code   string `db:"code"`
+}
+
+func (r *role) All(ctx context.Context) ([]*model.Role, error) {
+	query, args, err := r.qb.Sq.Select("id", "name", "description", "created_at", "updated_at").
+		From(TableRoles).
+		Where(sq.Eq{"deleted_at": nil}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	roles := make([]*model.Role, 0)
+	if err = r.qb.DB.SelectContext(ctx, &roles, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	query, args, err = r.qb.Sq.Select("rp.role_id", "p.code").
+		From(TableRolePermissions + " rp").
+		Join(TablePermissions + " p ON p.id = rp.permission_id").
+		OrderBy("p.code").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	var rows []rolePermissionRow
+	if err = r.qb.DB.SelectContext(ctx, &rows, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	byID := make(map[int]*model.Role, len(roles))
+	for _, role := range roles {
+		role.Permissions = []string{}
+		byID[role.ID] = role
+	}
+	for _, row := range rows {
+		if role, ok := byID[row.RoleID]; ok {
+			role.Permissions = append(role.Permissions, row.Code)
+		}
+	}
+
+	return roles, nil
+}
+
+func (r *role) ByID(ctx context.Context, id int) (*model.Role, error) {
+	query, args, err := r.qb.Sq.Select("id", "name", "description", "created_at", "updated_at").
+		From(TableRoles).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	var out model.Role
+	if err = r.qb.DB.GetContext(ctx, &out, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	query, args, err = r.qb.Sq.Select("p.code").
+		From(TableRolePermissions + " rp").
+		Join(TablePermissions + " p ON p.id = rp.permission_id").
+		Where(sq.Eq{"rp.role_id": id}).
+		OrderBy("p.code").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	out.Permissions = []string{}
+	if err = r.qb.DB.SelectContext(ctx, &out.Permissions, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// Create создаёт роль вместе с набором прав
+func (r *role) Create(ctx context.Context, input *model.RoleCreate) (int, error) {
+	var id int
+	err := r.qb.Tx.Do(ctx, func(ctx context.Context) error {
+		query, args, err := r.qb.Sq.Insert(TableRoles).
+			Columns("name", "description").
+			Values(input.Name, input.Description).
+			Suffix("RETURNING id").
+			ToSql()
+		if err != nil {
+			r.logger.Error(err)
+			return err
+		}
+
+		if err = r.qb.DB.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+			if IsPgError(err, ErrorCodeUniqueViolation) {
+				return ErrAlreadyExists
+			}
+			r.logger.Error(err)
+			return err
+		}
+
+		return r.replacePermissions(ctx, id, input.Permissions)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// SetPermissions заменяет набор прав роли целиком
+func (r *role) SetPermissions(ctx context.Context, roleID int, codes []string) error {
+	return r.qb.Tx.Do(ctx, func(ctx context.Context) error {
+		query, args, err := r.qb.Sq.Update(TableRoles).
+			Set("updated_at", sq.Expr("now()")).
+			Where(sq.Eq{"id": roleID, "deleted_at": nil}).
+			ToSql()
+		if err != nil {
+			r.logger.Error(err)
+			return err
+		}
+
+		res, err := r.qb.DB.ExecContext(ctx, query, args...)
+		if err != nil {
+			r.logger.Error(err)
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrNotFound
+		}
+
+		query, args, err = r.qb.Sq.Delete(TableRolePermissions).
+			Where(sq.Eq{"role_id": roleID}).
+			ToSql()
+		if err != nil {
+			r.logger.Error(err)
+			return err
+		}
+		if _, err = r.qb.DB.ExecContext(ctx, query, args...); err != nil {
+			r.logger.Error(err)
+			return err
+		}
+
+		return r.replacePermissions(ctx, roleID, codes)
+	})
+}
+
+func (r *role) replacePermissions(ctx context.Context, roleID int, codes []string) error {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	query, args, err := r.qb.Sq.Insert(TableRolePermissions).
+		Columns("role_id", "permission_id").
+		Select(r.qb.Sq.Select().
+			Column(sq.Expr("?", roleID)).
+			Column("id").
+			From(TablePermissions).
+			Where(sq.Eq{"code": codes})).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	res, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); int(n) != len(unique(codes)) {
+		return ErrUnknownPermission
+	}
+
+	return nil
+}
+
+// PermissionsByRole возвращает коды прав роли по её имени
+func (r *role) PermissionsByRole(ctx context.Context, roleName string) ([]string, error) {
+	query, args, err := r.qb.Sq.Select("p.code").
+		From(TableRoles + " r").
+		Join(TableRolePermissions + " rp ON rp.role_id = r.id").
+		Join(TablePermissions + " p ON p.id = rp.permission_id").
+		Where(sq.Eq{"r.name": roleName, "r.deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	codes := make([]string, 0)
+	if err = r.qb.DB.SelectContext(ctx, &codes, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (r *role) AllPermissions(ctx context.Context) ([]model.Permission, error) {
+	query, args, err := r.qb.Sq.Select("id", "code", "description").
+		From(TablePermissions).
+		OrderBy("code").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	perms := make([]model.Permission, 0)
+	if err = r.qb.DB.SelectContext(ctx, &perms, query, args...); err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	return perms, nil
+}
+
+// AssignToUser меняет роль пользователя
+func (r *role) AssignToUser(ctx context.Context, userID, roleID int) error {
+	query, args, err := r.qb.Sq.Update(TableUsers).
+		Set("role_id", roleID).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": userID, "deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	res, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		if IsPgError(err, ErrorCodeForeignKeyViolation) {
+			return ErrForeignKeyViolation
+		}
+		r.logger.Error(err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func unique(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"corpord-api/pkg/dbx"
+)
+
+// SecurityEventRepository журнал событий безопасности (входы, блокировки)
+type SecurityEventRepository interface {
+	Save(ctx context.Context, event *model.SecurityEvent) error
+}
+
+type securityEventRepo struct {
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func NewSecurityEventRepo(logger *logger.Logger, qb *dbx.QueryBuilder) SecurityEventRepository {
+	return &securityEventRepo{
+		logger: logger,
+		qb:     qb,
+	}
+}
+
+// Save добавляет событие в журнал
+func (r *securityEventRepo) Save(ctx context.Context, event *model.SecurityEvent) error {
+	query, args, err := r.qb.Sq.Insert(TableSecurityEvents).
+		Columns("event_type", "user_id", "actor_id", "email", "ip", "user_agent").
+		Values(event.EventType, event.UserID, event.ActorID, event.Email, event.IP, event.UserAgent).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	_, err = r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error(err)
+	}
+
+	return err
+}
@@ -0,0 +1,137 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/pkg/dbx"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// PurgeTables таблицы, из которых задача очистки окончательно удаляет мягко удалённые записи.
+// Порядок важен: сначала записи, ссылающиеся на справочники, затем сами справочники.
+// Пользователи не удаляются физически — на них ссылаются заказы, их данные обезличиваются
+var PurgeTables = []string{
+	TableBus,
+	TableDriver,
+	TableStop,
+	TableBusCategories,
+	TableBusStatuses,
+	TableDriverStatus,
+}
+
+// purgeGuards дополнительные условия для ссылок, которые внешний ключ не защищает:
+// trip_stops.stop_id объявлен с ON DELETE CASCADE, и удаление остановки молча
+// удалило бы её из маршрутов рейсов
+var purgeGuards = map[string]sq.Sqlizer{
+	TableStop: sq.Expr("NOT EXISTS (SELECT 1 FROM " + TableTripStop + " WHERE " + TableTripStop + ".stop_id = " + TableStop + ".id)"),
+}
+
+// softDelete мягкое удаление и восстановление записей по id для таблицы с колонкой deleted_at
+type softDelete struct {
+	table  string
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func newSoftDelete(table string, logger *logger.Logger, qb *dbx.QueryBuilder) softDelete {
+	return softDelete{table: table, logger: logger, qb: qb}
+}
+
+// markDeleted проставляет deleted_at. Возвращает ErrNotFound, если записи нет или она уже удалена
+func (s softDelete) markDeleted(ctx context.Context, id int) error {
+	return s.exec(ctx, s.qb.Sq.Update(s.table).
+		Set("deleted_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}))
+}
+
+// Restore снимает отметку об удалении. Возвращает ErrNotFound, если запись не была удалена
+func (s softDelete) Restore(ctx context.Context, id int) error {
+	return s.exec(ctx, s.qb.Sq.Update(s.table).
+		Set("deleted_at", nil).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}))
+}
+
+func (s softDelete) exec(ctx context.Context, b sq.UpdateBuilder) error {
+	query, args, err := b.ToSql()
+	if err != nil {
+		s.logger.Error(err)
+		return err
+	}
+
+	res, err := s.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		s.logger.Errorf("failed to update %s: %v", s.table, err)
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Purge окончательное удаление давно удалённых записей
+type Purge interface {
+	// Purge удаляет из table записи, мягко удалённые раньше before. Записи, на которые
+	// ещё ссылаются другие таблицы, пропускаются: по нарушению внешнего ключа
+	// (например, автобус в прошедших рейсах) или по условию из purgeGuards (остановка в маршруте)
+	Purge(ctx context.Context, table string, before time.Time) (int, error)
+}
+
+type purge struct {
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func NewPurge(logger *logger.Logger, qb *dbx.QueryBuilder) Purge {
+	return &purge{
+		logger: logger,
+		qb:     qb,
+	}
+}
+
+func (r *purge) Purge(ctx context.Context, table string, before time.Time) (int, error) {
+	guard := purgeGuards[table]
+
+	query, args, err := r.qb.Sq.Select("id").
+		From(table).
+		Where(sq.LtOrEq{"deleted_at": before}).
+		Where(guard).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return 0, err
+	}
+
+	var ids []int
+	if err = r.qb.DB.SelectContext(ctx, &ids, query, args...); err != nil {
+		r.logger.Errorf("failed to select deleted rows from %s: %v", table, err)
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		// условие повторяется в DELETE: ссылка могла появиться после выборки
+		query, args, err := r.qb.Sq.Delete(table).Where(sq.Eq{"id": id}).Where(guard).ToSql()
+		if err != nil {
+			return purged, err
+		}
+		res, err := r.qb.DB.ExecContext(ctx, query, args...)
+		if err != nil {
+			if IsPgError(err, ErrorCodeForeignKeyViolation) {
+				r.logger.Debugf("skip purging %s %d: still referenced", table, id)
+				continue
+			}
+			r.logger.Errorf("failed to purge %s %d: %v", table, id, err)
+			return purged, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			r.logger.Debugf("skip purging %s %d: still referenced", table, id)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
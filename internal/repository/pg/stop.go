@@ -4,47 +4,82 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/model"
 	"corpord-api/pkg/dbx"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
+	"database/sql"
+	"errors"
+	"time"
+
 	sq "github.com/Masterminds/squirrel"
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
 )
 
 type Stop interface {
-	All(ctx context.Context) ([]*model.Stop, error)
+	All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.Stop], error)
 	ByID(ctx context.Context, id int) (*model.Stop, error)
 	Create(ctx context.Context, stop *model.Stop) error
+	CreateMany(ctx context.Context, stops []*model.Stop) ([]int, error)
 	Update(ctx context.Context, stop *model.StopUpdate) error
+	UpdateMany(ctx context.Context, stops []*model.StopUpdate) error
+	ForPatch(ctx context.Context, id int) (*model.StopPatch, error)
+	Patch(ctx context.Context, stop *model.StopPatch) (time.Time, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type stop struct {
+	softDelete
 	logger *logger.Logger
 	qb     *dbx.QueryBuilder
 }
 
 func NewStop(logger *logger.Logger, qb *dbx.QueryBuilder) Stop {
 	return &stop{
-		logger: logger,
-		qb:     qb,
+		softDelete: newSoftDelete(TableStop, logger, qb),
+		logger:     logger,
+		qb:         qb,
 	}
 }
 
-func (s *stop) All(ctx context.Context) ([]*model.Stop, error) {
-	var stops []*model.Stop
-	query, args, err := s.qb.Sq.Select(
+var stopPaging = paging.Spec{
+	Fields: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"created_at": "created_at",
+	},
+	Default: "id",
+	ID:      "id",
+}
+
+var stopFilter = filter.Spec{
+	"id":         {Column: "id", Kind: filter.Int},
+	"name":       {Column: "name", Kind: filter.String},
+	"address":    {Column: "address", Kind: filter.String},
+	"latitude":   {Column: "latitude", Kind: filter.Float},
+	"longitude":  {Column: "longitude", Kind: filter.Float},
+	"created_at": {Column: "created_at", Kind: filter.Time},
+}
+
+func (s *stop) All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.Stop], error) {
+	where, err := stopFilter.Build(f)
+	if err != nil {
+		return nil, err
+	}
+	query := s.qb.Sq.Select(
+		"id",
 		"name",
 		"address",
 		"latitude",
 		"longitude",
 		"created_at",
-		"updated_at").
+		"updated_at",
+		"deleted_at").
 		From(TableStop).
-		ToSql()
-	if err != nil {
-		s.logger.Error("failed to build query from database", zap.Error(err))
-		return nil, err
-	}
-	err = s.qb.DB.SelectContext(ctx, &stops, query, args...)
+		Where(dbx.NotDeleted(ctx, "deleted_at")).
+		Where(where)
+
+	stops, err := paging.Select[*model.Stop](ctx, s.qb, query, stopPaging, p)
 	if err != nil {
 		s.logger.Error("failed to execute query from database", zap.Error(err))
 		return nil, err
@@ -60,9 +95,11 @@ func (s *stop) ByID(ctx context.Context, id int) (*model.Stop, error) {
 		"latitude",
 		"longitude",
 		"created_at",
-		"updated_at").
+		"updated_at",
+		"deleted_at").
 		From(TableStop).
 		Where(sq.Eq{"id": id}).
+		Where(dbx.NotDeleted(ctx, "deleted_at")).
 		ToSql()
 	if err != nil {
 		s.logger.Error("failed to build query from database", zap.Error(err))
@@ -77,6 +114,32 @@ func (s *stop) ByID(ctx context.Context, id int) (*model.Stop, error) {
 }
 
 func (s *stop) Create(ctx context.Context, stop *model.Stop) error {
+	id, err := s.insert(ctx, stop)
+	if err != nil {
+		return err
+	}
+	stop.ID = id
+	return nil
+}
+
+// CreateMany создаёт остановки в одной транзакции и возвращает их ID в порядке stops
+func (s *stop) CreateMany(ctx context.Context, stops []*model.Stop) ([]int, error) {
+	err := bulk(ctx, s.qb, stops, func(ctx context.Context, stop *model.Stop) error {
+		id, err := s.insert(ctx, stop)
+		stop.ID = id
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(stops))
+	for i, stop := range stops {
+		ids[i] = stop.ID
+	}
+	return ids, nil
+}
+
+func (s *stop) insert(ctx context.Context, stop *model.Stop) (int, error) {
 	query, args, err := s.qb.Sq.Insert(TableStop).Columns(
 		"name",
 		"address",
@@ -87,46 +150,110 @@ func (s *stop) Create(ctx context.Context, stop *model.Stop) error {
 			stop.Address,
 			stop.Latitude,
 			stop.Longitude).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
 		s.logger.Error("failed to build query from database", zap.Error(err))
-		return err
+		return 0, err
 	}
-	_, err = s.qb.DB.ExecContext(ctx, query, args...)
-	if err != nil {
+	var id int
+	if err := s.qb.DB.GetContext(ctx, &id, query, args...); err != nil {
 		s.logger.Error("failed to execute query from database", zap.Error(err))
-		return err
+		return 0, err
 	}
-	return nil
+	return id, nil
 }
 
+// Update обновляет остановку. Если остановки нет, возвращает ErrNotFound
 func (s *stop) Update(ctx context.Context, stop *model.StopUpdate) error {
+	return s.update(ctx, stop)
+}
+
+// UpdateMany обновляет остановки в одной транзакции
+func (s *stop) UpdateMany(ctx context.Context, stops []*model.StopUpdate) error {
+	return bulk(ctx, s.qb, stops, func(ctx context.Context, stop *model.StopUpdate) error {
+		return s.update(ctx, stop)
+	})
+}
+
+func (s *stop) update(ctx context.Context, stop *model.StopUpdate) error {
 	query, args, err := s.qb.Sq.Update(TableStop).
 		SetMap(stop.ToMap()).
-		Where(sq.Eq{"id": stop.ID}).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": stop.ID, "deleted_at": nil}).
 		ToSql()
 	if err != nil {
 		s.logger.Error("failed to build query from database", zap.Error(err))
 		return err
 	}
-	_, err = s.qb.DB.ExecContext(ctx, query, args...)
+	res, err := s.qb.DB.ExecContext(ctx, query, args...)
 	if err != nil {
 		s.logger.Error("failed to execute query from database", zap.Error(err))
 		return err
 	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
 	return nil
 }
 
-func (s *stop) Delete(ctx context.Context, id int) error {
-	query, args, err := s.qb.Sq.Delete(TableStop).Where(sq.Eq{"id": id}).ToSql()
+// ForPatch возвращает документ остановки для PATCH вместе с его версией
+func (s *stop) ForPatch(ctx context.Context, id int) (*model.StopPatch, error) {
+	query, args, err := s.qb.Sq.Select(
+		"id",
+		"name",
+		"address",
+		"latitude",
+		"longitude",
+		"updated_at").
+		From(TableStop).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		ToSql()
 	if err != nil {
 		s.logger.Error("failed to build query from database", zap.Error(err))
-		return err
+		return nil, err
 	}
-	_, err = s.qb.DB.ExecContext(ctx, query, args...)
+
+	var result model.StopPatch
+	if err := s.qb.DB.GetContext(ctx, &result, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		s.logger.Error("failed to execute query from database", zap.Error(err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Patch сохраняет документ, только если запись не менялась с версии stop.UpdatedAt.
+// Возвращает новую версию или ErrVersionConflict
+func (s *stop) Patch(ctx context.Context, stop *model.StopPatch) (time.Time, error) {
+	query, args, err := s.qb.Sq.Update(TableStop).
+		Set("name", stop.Name).
+		Set("address", stop.Address).
+		Set("latitude", stop.Latitude).
+		Set("longitude", stop.Longitude).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": stop.ID, "updated_at": stop.UpdatedAt, "deleted_at": nil}).
+		Suffix("RETURNING updated_at").
+		ToSql()
 	if err != nil {
+		s.logger.Error("failed to build query from database", zap.Error(err))
+		return time.Time{}, err
+	}
+
+	var version time.Time
+	if err := s.qb.DB.GetContext(ctx, &version, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrVersionConflict
+		}
 		s.logger.Error("failed to execute query from database", zap.Error(err))
-		return err
+		return time.Time{}, err
 	}
-	return nil
+	return version, nil
+}
+
+// Delete помечает остановку удалённой. Рейсы и билеты продолжают ссылаться на неё
+func (s *stop) Delete(ctx context.Context, id int) error {
+	return s.markDeleted(ctx, id)
 }
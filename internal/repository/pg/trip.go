@@ -4,16 +4,24 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/model"
 	"corpord-api/pkg/dbx"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
+	"database/sql"
+	"errors"
+	"time"
+
 	sq "github.com/Masterminds/squirrel"
 	"golang.org/x/net/context"
 )
 
 type Trip interface {
-	All(ctx context.Context) ([]*model.TripResponse, error)
-	AllShort(ctx context.Context) ([]*model.TripShortInfo, error)
+	All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripResponse], error)
+	AllShort(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripShortInfo], error)
 	ByID(ctx context.Context, id int) (*model.TripResponse, error)
 	Create(ctx context.Context, trip *model.Trip) error
-	Update(ctx context.Context, trip *model.TripUpdate) error
+	Update(ctx context.Context, trip *model.TripUpdate, version time.Time) (time.Time, error)
+	ForPatch(ctx context.Context, id int) (*model.TripPatch, error)
+	Patch(ctx context.Context, trip *model.TripPatch) (time.Time, error)
 	Delete(ctx context.Context, id int) error
 }
 
@@ -29,8 +37,50 @@ func NewTrip(logger *logger.Logger, qb *dbx.QueryBuilder) Trip {
 	}
 }
 
-func (t *trip) All(ctx context.Context) ([]*model.TripResponse, error) {
-	query, args, err := t.qb.Sq.Select(
+var tripPaging = paging.Spec{
+	Fields: map[string]string{
+		"id":         "trips.id",
+		"start_time": "trips.start_time",
+		"end_time":   "trips.end_time",
+		"base_price": "trips.base_price",
+	},
+	Default: "id",
+	ID:      "trips.id",
+}
+
+var tripFilter = filter.Spec{
+	"id":         {Column: "trips.id", Kind: filter.Int},
+	"bus_id":     {Column: "trips.bus_id", Kind: filter.Int},
+	"driver_id":  {Column: "trips.driver_id", Kind: filter.Int},
+	"status":     {Column: "trips.status", Kind: filter.String},
+	"start_time": {Column: "trips.start_time", Kind: filter.Time},
+	"end_time":   {Column: "trips.end_time", Kind: filter.Time},
+	"base_price": {Column: "trips.base_price", Kind: filter.Float},
+}
+
+// tripStopsColumn собирает остановки рейса в JSON-массив отдельным подзапросом,
+// чтобы рейс оставался одной строкой для пагинации и подсчёта total
+const tripStopsColumn = `(SELECT COALESCE(JSON_AGG(JSON_BUILD_OBJECT(
+		'id', s.id,
+		'name', s.name,
+		'address', s.address,
+		'latitude', s.latitude,
+		'longitude', s.longitude,
+		'arrival_time', ts.arrival_time AT TIME ZONE 'UTC',
+		'departure_time', ts.departure_time AT TIME ZONE 'UTC',
+		'stop_order', ts.stop_order
+	) ORDER BY ts.stop_order), '[]')
+	FROM trip_stops ts
+	JOIN stops s ON s.id = ts.stop_id
+	WHERE ts.trip_id = trips.id) AS stops`
+
+func (t *trip) All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripResponse], error) {
+	where, err := tripFilter.Build(f)
+	if err != nil {
+		return nil, err
+	}
+	query := t.qb.Sq.Select(
+		"trips.id",
 		"b.license_plate",
 		"b.brand",
 		"b.capacity",
@@ -41,11 +91,11 @@ func (t *trip) All(ctx context.Context) ([]*model.TripResponse, error) {
 		"d.middle_name",
 		"d.phone_number",
 		"ds.name as d_status_name",
-		"start_time",
-		"end_time",
+		"trips.start_time",
+		"trips.end_time",
 		"trips.status",
-		"base_price",
-		"(SELECT JSON_AGG(name, address, latitude, longitude) FROM stops WHERE id = 1)",
+		"trips.base_price",
+		tripStopsColumn,
 		"trips.created_at",
 		"trips.updated_at").
 		From(TableTrip).
@@ -54,15 +104,9 @@ func (t *trip) All(ctx context.Context) ([]*model.TripResponse, error) {
 		Join("bus_categories bc ON bc.id = b.category_id").
 		Join("drivers d ON d.id = trips.driver_id").
 		Join("driver_status ds ON ds.id = d.status").
-		Join("trip_stops ts ON trips.id = ts.trip_id").
-		Join("stops s ON s.id = ts.stop_id").
-		ToSql()
-	if err != nil {
-		t.logger.Error(err)
-		return nil, err
-	}
-	var trips []*model.TripResponse
-	err = t.qb.DB.SelectContext(ctx, &trips, query, args...)
+		Where(where)
+
+	trips, err := paging.Select[*model.TripResponse](ctx, t.qb, query, tripPaging, p)
 	if err != nil {
 		t.logger.Error(err)
 		return nil, err
@@ -70,9 +114,35 @@ func (t *trip) All(ctx context.Context) ([]*model.TripResponse, error) {
 	return trips, nil
 }
 
-func (t *trip) AllShort(ctx context.Context) ([]*model.TripShortInfo, error) {
-	var result []*model.TripShortInfo
-	query, args, err := t.qb.Sq.Select(
+// tripShortPaging сортирует краткий список по времени отправления с первой остановки
+var tripShortPaging = paging.Spec{
+	Fields: map[string]string{
+		"trip_id":    "trips.id",
+		"start_time": "ts_start.arrival_time",
+		"base_price": "trips.base_price",
+	},
+	Default: "trip_id",
+	ID:      "trips.id",
+	IDField: "trip_id",
+}
+
+// tripShortFilter фильтрует краткий список, start_time и end_time — по расписанию первой и последней остановки
+var tripShortFilter = filter.Spec{
+	"trip_id":    {Column: "trips.id", Kind: filter.Int},
+	"bus_id":     {Column: "trips.bus_id", Kind: filter.Int},
+	"driver_id":  {Column: "trips.driver_id", Kind: filter.Int},
+	"status":     {Column: "trips.status", Kind: filter.String},
+	"start_time": {Column: "ts_start.arrival_time", Kind: filter.Time},
+	"end_time":   {Column: "ts_end.departure_time", Kind: filter.Time},
+	"base_price": {Column: "trips.base_price", Kind: filter.Float},
+}
+
+func (t *trip) AllShort(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripShortInfo], error) {
+	where, err := tripShortFilter.Build(f)
+	if err != nil {
+		return nil, err
+	}
+	query := t.qb.Sq.Select(
 		"trips.id AS trip_id",
 		"b.license_plate",
 		"b.brand",
@@ -89,12 +159,9 @@ func (t *trip) AllShort(ctx context.Context) ([]*model.TripShortInfo, error) {
 		Join("stops s_end ON s_end.id = ts_end.stop_id").
 		Join("bus b ON b.id = trips.bus_id").
 		Join("drivers d ON d.id = trips.driver_id").
-		ToSql()
-	if err != nil {
-		t.logger.Error(err)
-		return nil, err
-	}
-	err = t.qb.DB.SelectContext(ctx, &result, query, args...)
+		Where(where)
+
+	result, err := paging.Select[*model.TripShortInfo](ctx, t.qb, query, tripShortPaging, p)
 	if err != nil {
 		t.logger.Error(err)
 		return nil, err
@@ -115,10 +182,11 @@ func (t *trip) ByID(ctx context.Context, id int) (*model.TripResponse, error) {
 		"d.middle_name",
 		"d.phone_number",
 		"ds.name as d_status_name",
-		"start_time",
-		"end_time",
+		"trips.start_time",
+		"trips.end_time",
 		"trips.status",
-		"base_price",
+		"trips.base_price",
+		tripStopsColumn,
 		"trips.created_at",
 		"trips.updated_at").
 		From(TableTrip).
@@ -127,6 +195,7 @@ func (t *trip) ByID(ctx context.Context, id int) (*model.TripResponse, error) {
 		Join("bus_categories bc ON bc.id = b.category_id").
 		Join("drivers d ON d.id = trips.driver_id").
 		Join("driver_status ds ON ds.id = d.status").
+		Where(sq.Eq{"trips.id": id}).
 		ToSql()
 	if err != nil {
 		t.logger.Error(err)
@@ -163,22 +232,89 @@ func (t *trip) Create(ctx context.Context, trip *model.Trip) error {
 	return nil
 }
 
-func (t *trip) Update(ctx context.Context, trip *model.TripUpdate) error {
+// Update меняет переданные поля рейса, если его версия всё ещё version,
+// и возвращает новую версию. Иначе возвращает ErrVersionConflict
+func (t *trip) Update(ctx context.Context, trip *model.TripUpdate, version time.Time) (time.Time, error) {
 	query, args, err := t.qb.Sq.Update(TableTrip).
 		SetMap(trip.ToMap()).
 		Set("updated_at", sq.Expr("NOW()")).
-		Where(sq.Eq{"trips.id": trip.ID}).
+		Where(sq.Eq{"trips.id": trip.ID, "trips.updated_at": version}).
+		Suffix("RETURNING updated_at").
 		ToSql()
 	if err != nil {
 		t.logger.Error(err)
-		return err
+		return time.Time{}, err
 	}
-	_, err = t.qb.DB.ExecContext(ctx, query, args...)
+
+	var next time.Time
+	if err := t.qb.DB.GetContext(ctx, &next, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrVersionConflict
+		}
+		t.logger.Error(err)
+		return time.Time{}, err
+	}
+	return next, nil
+}
+
+// ForPatch возвращает документ рейса для PATCH вместе с его версией
+func (t *trip) ForPatch(ctx context.Context, id int) (*model.TripPatch, error) {
+	query, args, err := t.qb.Sq.Select(
+		"id",
+		"bus_id",
+		"driver_id",
+		"start_time",
+		"end_time",
+		"status",
+		"base_price",
+		"updated_at").
+		From(TableTrip).
+		Where(sq.Eq{"id": id}).
+		ToSql()
 	if err != nil {
 		t.logger.Error(err)
-		return err
+		return nil, err
 	}
-	return nil
+
+	var result model.TripPatch
+	if err := t.qb.DB.GetContext(ctx, &result, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		t.logger.Error(err)
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Patch сохраняет документ, только если рейс не менялся с версии trip.UpdatedAt.
+// Возвращает новую версию или ErrVersionConflict
+func (t *trip) Patch(ctx context.Context, trip *model.TripPatch) (time.Time, error) {
+	query, args, err := t.qb.Sq.Update(TableTrip).
+		Set("bus_id", trip.BusID).
+		Set("driver_id", trip.DriverID).
+		Set("start_time", trip.StartTime).
+		Set("end_time", trip.EndTime).
+		Set("status", trip.Status).
+		Set("base_price", trip.BasePrice).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": trip.ID, "updated_at": trip.UpdatedAt}).
+		Suffix("RETURNING updated_at").
+		ToSql()
+	if err != nil {
+		t.logger.Error(err)
+		return time.Time{}, err
+	}
+
+	var version time.Time
+	if err := t.qb.DB.GetContext(ctx, &version, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrVersionConflict
+		}
+		t.logger.Error(err)
+		return time.Time{}, err
+	}
+	return version, nil
 }
 
 func (t *trip) Delete(ctx context.Context, id int) error {
@@ -4,15 +4,19 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/model"
 	"corpord-api/pkg/dbx"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
 	sq "github.com/Masterminds/squirrel"
 	"golang.org/x/net/context"
 )
 
 type TripStop interface {
-	All(ctx context.Context) ([]*model.TripStop, error)
+	All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripStop], error)
 	ByID(ctx context.Context, id int) (*model.TripStop, error)
 	Create(ctx context.Context, tripStop *model.TripStop) error
+	CreateMany(ctx context.Context, tripStops []*model.TripStop) ([]int, error)
 	Update(ctx context.Context, tripStop *model.TripStopUpdate) error
+	UpdateMany(ctx context.Context, tripStops []*model.TripStopUpdate) error
 	Delete(ctx context.Context, id int) error
 }
 
@@ -28,22 +32,43 @@ func NewTripStop(logger *logger.Logger, qb *dbx.QueryBuilder) TripStop {
 	}
 }
 
-func (ts *tripStop) All(ctx context.Context) ([]*model.TripStop, error) {
-	var tripStops []*model.TripStop
-	query, args, err := ts.qb.Sq.Select(
+var tripStopPaging = paging.Spec{
+	Fields: map[string]string{
+		"id":           "id",
+		"trip_id":      "trip_id",
+		"arrival_time": "arrival_time",
+	},
+	Default: "id",
+	ID:      "id",
+}
+
+var tripStopFilter = filter.Spec{
+	"id":             {Column: "id", Kind: filter.Int},
+	"trip_id":        {Column: "trip_id", Kind: filter.Int},
+	"stop_id":        {Column: "stop_id", Kind: filter.Int},
+	"stop_order":     {Column: "stop_order", Kind: filter.Int},
+	"arrival_time":   {Column: "arrival_time", Kind: filter.Time},
+	"departure_time": {Column: "departure_time", Kind: filter.Time},
+	"price_to_next":  {Column: "price_to_next", Kind: filter.Float, Nullable: true},
+}
+
+func (ts *tripStop) All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripStop], error) {
+	where, err := tripStopFilter.Build(f)
+	if err != nil {
+		return nil, err
+	}
+	query := ts.qb.Sq.Select(
+		"id",
 		"trip_id",
 		"stop_id",
 		"arrival_time",
 		"departure_time",
 		"stop_order",
 		"price_to_next",
-	).From(TableTripStop).ToSql()
-	if err != nil {
-		ts.logger.Error(err)
-		return nil, err
-	}
+	).From(TableTripStop).
+		Where(where)
 
-	err = ts.qb.DB.SelectContext(ctx, &tripStops, query, args...)
+	tripStops, err := paging.Select[*model.TripStop](ctx, ts.qb, query, tripStopPaging, p)
 	if err != nil {
 		ts.logger.Error(err)
 		return nil, err
@@ -77,6 +102,32 @@ func (ts *tripStop) ByID(ctx context.Context, id int) (*model.TripStop, error) {
 }
 
 func (ts *tripStop) Create(ctx context.Context, tripStop *model.TripStop) error {
+	id, err := ts.insert(ctx, tripStop)
+	if err != nil {
+		return err
+	}
+	tripStop.ID = id
+	return nil
+}
+
+// CreateMany создаёт остановки рейсов в одной транзакции и возвращает их ID в порядке tripStops
+func (ts *tripStop) CreateMany(ctx context.Context, tripStops []*model.TripStop) ([]int, error) {
+	err := bulk(ctx, ts.qb, tripStops, func(ctx context.Context, tripStop *model.TripStop) error {
+		id, err := ts.insert(ctx, tripStop)
+		tripStop.ID = id
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(tripStops))
+	for i, tripStop := range tripStops {
+		ids[i] = tripStop.ID
+	}
+	return ids, nil
+}
+
+func (ts *tripStop) insert(ctx context.Context, tripStop *model.TripStop) (int, error) {
 	query, args, err := ts.qb.Sq.Insert(TableTripStop).Columns(
 		"trip_id",
 		"stop_id",
@@ -91,20 +142,33 @@ func (ts *tripStop) Create(ctx context.Context, tripStop *model.TripStop) error
 			tripStop.DepartureTime,
 			tripStop.StopOrder,
 			tripStop.PriceToNext).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
 		ts.logger.Error(err)
-		return err
+		return 0, err
 	}
-	_, err = ts.qb.DB.ExecContext(ctx, query, args...)
-	if err != nil {
+	var id int
+	if err := ts.qb.DB.GetContext(ctx, &id, query, args...); err != nil {
 		ts.logger.Error(err)
-		return err
+		return 0, err
 	}
-	return nil
+	return id, nil
 }
 
+// Update обновляет остановку рейса. Если её нет, возвращает ErrNotFound
 func (ts *tripStop) Update(ctx context.Context, tripStop *model.TripStopUpdate) error {
+	return ts.update(ctx, tripStop)
+}
+
+// UpdateMany обновляет остановки рейсов в одной транзакции
+func (ts *tripStop) UpdateMany(ctx context.Context, tripStops []*model.TripStopUpdate) error {
+	return bulk(ctx, ts.qb, tripStops, func(ctx context.Context, tripStop *model.TripStopUpdate) error {
+		return ts.update(ctx, tripStop)
+	})
+}
+
+func (ts *tripStop) update(ctx context.Context, tripStop *model.TripStopUpdate) error {
 	query, args, err := ts.qb.Sq.Update(TableTripStop).
 		SetMap(tripStop.ToMap()).
 		Where(sq.Eq{"id": tripStop.ID}).
@@ -113,11 +177,14 @@ func (ts *tripStop) Update(ctx context.Context, tripStop *model.TripStopUpdate)
 		ts.logger.Error(err)
 		return err
 	}
-	_, err = ts.qb.DB.ExecContext(ctx, query, args...)
+	res, err := ts.qb.DB.ExecContext(ctx, query, args...)
 	if err != nil {
 		ts.logger.Error(err)
 		return err
 	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
 	return nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"context"
 	"corpord-api/internal/logger"
 	"corpord-api/pkg/dbx"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
 	"database/sql"
 	"errors"
 	"time"
@@ -20,18 +22,22 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*model.UserDB, error)
 	Update(ctx context.Context, id int, user *model.UserUpdate) (*model.UserResponse, error)
 	Delete(ctx context.Context, id int) error
-	GetAll(ctx context.Context) ([]*model.UserResponse, error)
+	UpdateProfile(ctx context.Context, id int, profile *model.ProfileUpdate) (*model.UserResponse, error)
+	Search(ctx context.Context, search *model.UserSearch, f filter.Filter, p paging.Params) (*paging.Page[*model.UserResponse], error)
+	Restore(ctx context.Context, id int) error
 }
 
 type userRepository struct {
+	softDelete
 	logger *logger.Logger
 	qb     *dbx.QueryBuilder
 }
 
 func NewUserRepository(logger *logger.Logger, qb *dbx.QueryBuilder) UserRepository {
 	return &userRepository{
-		logger: logger,
-		qb:     qb,
+		softDelete: newSoftDelete(TableUsers, logger, qb),
+		logger:     logger,
+		qb:         qb,
 	}
 }
 
@@ -45,7 +51,7 @@ func (r *userRepository) Create(ctx context.Context, user *model.UserCreate) (*m
 	query, args, err := r.qb.Sq.Insert(TableUsers).
 		Columns("email", "password_hash", "name", "created_at", "updated_at").
 		Values(user.Email, user.Password /* hashedPassword */, user.Name, now, now).
-		Suffix("RETURNING id, email, name, created_at, updated_at").
+		Suffix("RETURNING id, COALESCE(email, '') AS email, name, created_at, updated_at").
 		ToSql()
 	if err != nil {
 		r.logger.Errorf("failed to build create user query: %v", err)
@@ -62,51 +68,120 @@ func (r *userRepository) Create(ctx context.Context, user *model.UserCreate) (*m
 	return userDB.ToResponse(), nil
 }
 
-// GetAll retrieves all users from the database with pagination support
-func (r *userRepository) GetAll(ctx context.Context) ([]*model.UserResponse, error) {
-	r.logger.Info("fetching all users")
+var userPaging = paging.Spec{
+	Fields: map[string]string{
+		"id":         "u.id",
+		"email":      "COALESCE(u.email, '')",
+		"name":       "u.name",
+		"created_at": "u.created_at",
+	},
+	Default: "id",
+	ID:      "u.id",
+}
 
-	// Build the base query
-	query, args, err := r.qb.Sq.Select(
-		"id",
-		"name",
-		"email",
-		"created_at",
-		"updated_at",
-	).
-		From(TableUsers).
-		OrderBy("id ASC").
-		ToSql()
+var userFilter = filter.Spec{
+	"id":                 {Column: "u.id", Kind: filter.Int},
+	"role_id":            {Column: "u.role_id", Kind: filter.Int},
+	"email_verified":     {Column: "u.email_verified", Kind: filter.Bool},
+	"is_service_account": {Column: "u.is_service_account", Kind: filter.Bool},
+	"preferred_language": {Column: "u.preferred_language", Kind: filter.String},
+	"created_at":         {Column: "u.created_at", Kind: filter.Time},
+	"blocked_at":         {Column: "u.blocked_at", Kind: filter.Time, Nullable: true},
+}
 
+// Search ищет пользователей по подстроке email/имени, роли и блокировке
+func (r *userRepository) Search(ctx context.Context, search *model.UserSearch, f filter.Filter, p paging.Params) (*paging.Page[*model.UserResponse], error) {
+	conditions, err := userFilter.Build(f)
 	if err != nil {
-		r.logger.Errorf("failed to build get all users query: %v", err)
 		return nil, err
 	}
+	where := sq.And{}
+	if conditions != nil {
+		where = append(where, conditions)
+	}
+	if deleted := dbx.NotDeleted(ctx, "u.deleted_at"); deleted != nil {
+		where = append(where, deleted)
+	}
+	if search.Query != "" {
+		pattern := "%" + filter.EscapeLike(search.Query) + "%"
+		where = append(where, sq.Or{sq.ILike{"u.email": pattern}, sq.ILike{"u.name": pattern}})
+	}
+	if search.Email != "" {
+		where = append(where, sq.ILike{"u.email": "%" + filter.EscapeLike(search.Email) + "%"})
+	}
+	if search.Name != "" {
+		where = append(where, sq.ILike{"u.name": "%" + filter.EscapeLike(search.Name) + "%"})
+	}
+	if search.Role != "" {
+		where = append(where, sq.Eq{"r.name": search.Role})
+	}
+	if search.Blocked != nil {
+		if *search.Blocked {
+			where = append(where, sq.NotEq{"u.blocked_at": nil})
+		} else {
+			where = append(where, sq.Eq{"u.blocked_at": nil})
+		}
+	}
+
+	query := r.qb.Sq.Select(
+		"u.id",
+		"u.name",
+		"COALESCE(u.email, '') AS email",
+		"r.name AS role_name",
+		"u.email_verified",
+		"COALESCE(u.phone, '') AS phone",
+		"u.is_service_account",
+		"u.preferred_language",
+		"u.blocked_at",
+		"u.blocked_reason",
+		"u.created_at",
+		"u.updated_at",
+		"u.deleted_at",
+	).
+		From("users u").
+		Join("roles r ON u.role_id = r.id").
+		Where(where)
 
-	// Execute the query
-	var users []*model.UserDB
-	err = r.qb.DB.SelectContext(ctx, &users, query, args...)
+	users, err := paging.Select[*model.UserDB](ctx, r.qb, query, userPaging, p)
 	if err != nil {
-		r.logger.Errorf("failed to fetch users: %v", err)
+		r.logger.Errorf("failed to search users: %v", err)
 		return nil, err
 	}
 
-	// Convert to response model
-	responses := make([]*model.UserResponse, len(users))
-	for i, user := range users {
+	responses := make([]*model.UserResponse, len(users.Items))
+	for i, user := range users.Items {
 		responses[i] = user.ToResponse()
 	}
-
-	r.logger.Infof("successfully fetched %d users", len(responses))
-	return responses, nil
+	return &paging.Page[*model.UserResponse]{
+		Items:      responses,
+		Limit:      users.Limit,
+		Page:       users.Page,
+		Total:      users.Total,
+		NextCursor: users.NextCursor,
+	}, nil
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id int) (*model.UserResponse, error) {
 	r.logger.Infof("fetching user with id: %d", id)
 
-	query, args, err := r.qb.Sq.Select("id", "name", "email", "created_at", "updated_at").
-		From(TableUsers).
-		Where(sq.Eq{"id": id}).
+	query, args, err := r.qb.Sq.Select(
+		"u.id",
+		"u.name",
+		"COALESCE(u.email, '') AS email",
+		"r.name AS role_name",
+		"u.email_verified",
+		"COALESCE(u.phone, '') AS phone",
+		"u.preferred_language",
+		"u.blocked_at",
+		"u.blocked_reason",
+		"u.created_at",
+		"u.updated_at",
+		"u.deleted_at",
+	).
+		From("users u").
+		Join("roles r ON u.role_id = r.id").
+		Where(sq.Eq{"u.id": id}).
+		Where(dbx.NotDeleted(ctx, "u.deleted_at")).
 		ToSql()
 	if err != nil {
 		r.logger.Errorf("failed to build query for user id %d: %v", id, err)
@@ -126,18 +201,27 @@ func (r *userRepository) GetByID(ctx context.Context, id int) (*model.UserRespon
 
 	r.logger.Infof("successfully fetched user with id %d", id)
 	return &model.UserResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.Role,
+		EmailVerified: user.EmailVerified,
+		Phone:         user.Phone,
+		Language:      user.Language,
+		BlockedAt:     user.BlockedAt,
+		BlockedReason: user.BlockedReason,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+		DeletedAt:     user.DeletedAt,
 	}, nil
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.UserDB, error) {
 	r.logger.Infof("fetching user by email: %s", email)
 
-	query, args, err := r.qb.Sq.Select("id", "email", "password_hash", "name", "created_at", "updated_at").
+	query, args, err := r.qb.Sq.Select("id", "COALESCE(email, '') AS email", "password_hash", "name", "created_at", "updated_at").
 		From(TableUsers).
-		Where(sq.Eq{"email": email}).
+		Where(sq.Eq{"email": email, "deleted_at": nil}).
 		ToSql()
 
 	if err != nil {
@@ -167,7 +251,7 @@ func (r *userRepository) Update(ctx context.Context, id int, user *model.UserUpd
 
 	updateQuery := r.qb.Sq.Update(TableUsers).
 		Set("updated_at", now).
-		Where(sq.Eq{"id": id})
+		Where(sq.Eq{"id": id, "deleted_at": nil})
 
 	if user.Name != nil {
 		updateQuery = updateQuery.Set("name", *user.Name)
@@ -175,7 +259,10 @@ func (r *userRepository) Update(ctx context.Context, id int, user *model.UserUpd
 	}
 
 	if user.Email != nil {
-		updateQuery = updateQuery.Set("email", *user.Email)
+		// в SET справа видно старое значение email: подтверждение сбрасывается, только если адрес сменился
+		updateQuery = updateQuery.
+			Set("email", *user.Email).
+			Set("email_verified", sq.Expr("email_verified AND email IS NOT DISTINCT FROM ?", *user.Email))
 		r.logger.Debugf("updating email for user %d", id)
 	}
 
@@ -208,30 +295,78 @@ func (r *userRepository) Update(ctx context.Context, id int, user *model.UserUpd
 	return r.GetByID(ctx, id)
 }
 
-func (r *userRepository) Delete(ctx context.Context, id int) error {
-	r.logger.Infof("deleting user with id: %d", id)
+// UpdateProfile обновляет поля профиля, которые пользователь меняет сам
+func (r *userRepository) UpdateProfile(ctx context.Context, id int, profile *model.ProfileUpdate) (*model.UserResponse, error) {
+	updateQuery := r.qb.Sq.Update(TableUsers).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id, "deleted_at": nil})
 
-	query, args, err := sq.Delete(TableUsers).
-		Where(sq.Eq{"id": id}).
-		PlaceholderFormat(sq.Dollar).
-		ToSql()
+	if profile.Name != nil {
+		updateQuery = updateQuery.Set("name", *profile.Name)
+	}
+	if profile.PreferredLanguage != nil {
+		updateQuery = updateQuery.Set("preferred_language", *profile.PreferredLanguage)
+	}
+
+	query, args, err := updateQuery.ToSql()
 	if err != nil {
-		r.logger.Errorf("failed to build delete query for user %d: %v", id, err)
-		return err
+		r.logger.Errorf("failed to build update profile query for user %d: %v", id, err)
+		return nil, err
 	}
 
 	result, err := r.qb.DB.ExecContext(ctx, query, args...)
 	if err != nil {
-		r.logger.Errorf("failed to delete user %d: %v", id, err)
-		return err
+		r.logger.Errorf("failed to update profile of user %d: %v", id, err)
+		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return nil, ErrNotFound
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Delete помечает пользователя удалённым и отзывает его сессии. Строка остаётся:
+// на неё ссылаются заказы, а персональные данные позже обезличиваются
+func (r *userRepository) Delete(ctx context.Context, id int) error {
+	r.logger.Infof("deleting user with id: %d", id)
+
+	err := r.qb.Tx.Do(ctx, func(ctx context.Context) error {
+		query, args, err := r.qb.Sq.Update(TableUsers).
+			Set("deleted_at", sq.Expr("now()")).
+			Where(sq.Eq{"id": id, "deleted_at": nil}).
+			ToSql()
+		if err != nil {
+			r.logger.Errorf("failed to build delete query for user %d: %v", id, err)
+			return err
+		}
+
+		result, err := r.qb.DB.ExecContext(ctx, query, args...)
+		if err != nil {
+			r.logger.Errorf("failed to delete user %d: %v", id, err)
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			r.logger.Infof("no rows affected when deleting user %d - user not found", id)
+			return ErrNotFound
+		}
+
+		query, args, err = r.qb.Sq.Update(TableRefreshToken).
+			Set("revoked", true).
+			Where(sq.Eq{"user_id": id, "revoked": false}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err = r.qb.DB.ExecContext(ctx, query, args...); err != nil {
+			r.logger.Errorf("failed to revoke sessions of deleted user %d: %v", id, err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		r.logger.Warnf("failed to get rows affected for user %d deletion: %v", id, err)
-	} else if rowsAffected == 0 {
-		r.logger.Infof("no rows affected when deleting user %d - user not found", id)
-		return ErrNotFound
+		return err
 	}
 
 	r.logger.Infof("successfully deleted user with id: %d", id)
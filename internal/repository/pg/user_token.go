@@ -0,0 +1,121 @@
+package pg
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"corpord-api/pkg/dbx"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+var ErrUserTokenNotFound = errors.New("user token not found")
+
+// UserTokenRepository хранит одноразовые токены (сброс пароля, подтверждение email)
+type UserTokenRepository interface {
+	Save(ctx context.Context, token *model.UserToken) error
+	Consume(ctx context.Context, hash, purpose string) (*model.UserToken, error)
+	InvalidateByUser(ctx context.Context, userID int, purpose string) error
+	CleanupExpired(ctx context.Context) error
+}
+
+type userTokenRepo struct {
+	logger *logger.Logger
+	qb     *dbx.QueryBuilder
+}
+
+func NewUserTokenRepo(logger *logger.Logger, qb *dbx.QueryBuilder) UserTokenRepository {
+	return &userTokenRepo{
+		logger: logger,
+		qb:     qb,
+	}
+}
+
+// Save сохраняет хеш токена
+func (r *userTokenRepo) Save(ctx context.Context, token *model.UserToken) error {
+	query, args, err := r.qb.Sq.Insert(TableUserTokens).
+		Columns("id", "user_id", "purpose", "token_hash", "expires_at").
+		Values(token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	_, err = r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error(err)
+	}
+
+	return err
+}
+
+// Consume атомарно помечает действующий токен использованным и возвращает его.
+// Повторное использование, истёкший или чужой по назначению токен дают ErrUserTokenNotFound.
+func (r *userTokenRepo) Consume(ctx context.Context, hash, purpose string) (*model.UserToken, error) {
+	query, args, err := r.qb.Sq.Update(TableUserTokens).
+		Set("used_at", sq.Expr("now()")).
+		Where(sq.Eq{"token_hash": hash, "purpose": purpose, "used_at": nil}).
+		Where("expires_at > now()").
+		Suffix("RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at").
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	var token model.UserToken
+	err = r.qb.DB.GetContext(ctx, &token, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserTokenNotFound
+		}
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// InvalidateByUser помечает использованными все активные токены пользователя с данным назначением
+func (r *userTokenRepo) InvalidateByUser(ctx context.Context, userID int, purpose string) error {
+	query, args, err := r.qb.Sq.Update(TableUserTokens).
+		Set("used_at", sq.Expr("now()")).
+		Where(sq.Eq{"user_id": userID, "purpose": purpose, "used_at": nil}).
+		ToSql()
+	if err != nil {
+		r.logger.Error(err)
+		return err
+	}
+
+	_, err = r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error(err)
+	}
+
+	return err
+}
+
+// CleanupExpired удаляет истёкшие и использованные токены
+func (r *userTokenRepo) CleanupExpired(ctx context.Context) error {
+	query, args, err := r.qb.Sq.
+		Delete(TableUserTokens).
+		Where(sq.Or{sq.Expr("expires_at < now()"), sq.NotEq{"used_at": nil}}).
+		ToSql()
+	if err != nil {
+		r.logger.Error("failed to build cleanup query: ", err)
+		return err
+	}
+
+	res, err := r.qb.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to execute cleanup query: ", err)
+		return err
+	}
+
+	count, _ := res.RowsAffected()
+	r.logger.Infof("cleanup expired user tokens: deleted %d rows", count)
+	return nil
+}
@@ -0,0 +1,80 @@
+package rd
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	loginAttemptsPrefix = "login:attempts:"
+	loginLockPrefix     = "login:lock:"
+)
+
+// LoginAttemptRepository считает неудачные попытки входа и хранит временные блокировки.
+// key — произвольный идентификатор субъекта, например "email:user@example.com" или "ip:1.2.3.4"
+type LoginAttemptRepository interface {
+	// Fail увеличивает счётчик неудачных попыток и продлевает его жизнь на window
+	Fail(ctx context.Context, key string, window time.Duration) (int64, error)
+	// Lock блокирует вход для key на ttl
+	Lock(ctx context.Context, key string, ttl time.Duration) error
+	// LockedFor возвращает оставшееся время блокировки, 0 если блокировки нет
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// Reset сбрасывает счётчик и блокировку
+	Reset(ctx context.Context, key string) error
+}
+
+type loginAttemptRepo struct {
+	logger *logger.Logger
+	client *redis.Client
+}
+
+func NewLoginAttemptRepo(logger *logger.Logger, client *redis.Client) LoginAttemptRepository {
+	return &loginAttemptRepo{
+		logger: logger,
+		client: client,
+	}
+}
+
+func (r *loginAttemptRepo) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	incr := pipe.Incr(ctx, loginAttemptsPrefix+key)
+	pipe.Expire(ctx, loginAttemptsPrefix+key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Errorf("failed to register login failure for %s: %v", key, err)
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (r *loginAttemptRepo) Lock(ctx context.Context, key string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, loginLockPrefix+key, 1, ttl).Err(); err != nil {
+		r.logger.Errorf("failed to lock login for %s: %v", key, err)
+		return err
+	}
+	return nil
+}
+
+func (r *loginAttemptRepo) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, loginLockPrefix+key).Result()
+	if err != nil {
+		r.logger.Errorf("failed to get login lock for %s: %v", key, err)
+		return 0, err
+	}
+	// -2: ключа нет, -1: ключ без срока (не должно случаться)
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (r *loginAttemptRepo) Reset(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, loginAttemptsPrefix+key, loginLockPrefix+key).Err(); err != nil {
+		r.logger.Errorf("failed to reset login attempts for %s: %v", key, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package rd
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/model"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const oauthStatePrefix = "oauth:state:"
+
+var ErrOAuthStateNotFound = errors.New("oauth state not found")
+
+// consumeStateScript удаляет state и возвращает его, только если binding_hash совпадает с ARGV[1]:
+// проверка и удаление атомарны, чтобы state нельзя было забрать без cookie браузера
+var consumeStateScript = redis.NewScript(`
+local raw = redis.call('GET', KEYS[1])
+if not raw then
+	return false
+end
+local ok, data = pcall(cjson.decode, raw)
+if not ok or type(data) ~= 'table' or data['binding_hash'] ~= ARGV[1] then
+	return false
+end
+redis.call('DEL', KEYS[1])
+return raw
+`)
+
+// OAuthStateRepository хранит параметры SSO-входа по значению state
+type OAuthStateRepository interface {
+	Save(ctx context.Context, state string, data *model.OAuthState, ttl time.Duration) error
+	// Consume возвращает и удаляет данные state, только если bindingHash совпадает с сохранённым.
+	// Иначе, как и при повторном вызове, возвращает ErrOAuthStateNotFound и state не трогает
+	Consume(ctx context.Context, state, bindingHash string) (*model.OAuthState, error)
+}
+
+type oauthStateRepo struct {
+	logger *logger.Logger
+	client *redis.Client
+}
+
+func NewOAuthStateRepo(logger *logger.Logger, client *redis.Client) OAuthStateRepository {
+	return &oauthStateRepo{
+		logger: logger,
+		client: client,
+	}
+}
+
+func (r *oauthStateRepo) Save(ctx context.Context, state string, data *model.OAuthState, ttl time.Duration) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if err := r.client.Set(ctx, oauthStatePrefix+state, raw, ttl).Err(); err != nil {
+		r.logger.Errorf("failed to save oauth state: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *oauthStateRepo) Consume(ctx context.Context, state, bindingHash string) (*model.OAuthState, error) {
+	raw, err := consumeStateScript.Run(ctx, r.client, []string{oauthStatePrefix + state}, bindingHash).Text()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrOAuthStateNotFound
+		}
+		r.logger.Errorf("failed to consume oauth state: %v", err)
+		return nil, err
+	}
+
+	var data model.OAuthState
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,133 @@
+package rd
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	otpCodePrefix     = "otp:code:"
+	otpCooldownPrefix = "otp:cooldown:"
+)
+
+var (
+	ErrOTPNotFound         = errors.New("otp not found")
+	ErrOTPMismatch         = errors.New("otp mismatch")
+	ErrOTPAttemptsExceeded = errors.New("otp attempts exceeded")
+)
+
+// verifyOTPScript засчитывает попытку до сравнения: параллельные попытки не могут
+// все пройти проверку лимита. Код удаляется после успешной или последней попытки.
+// Возвращает 1 — код верный, 0 — неверный, -1 — кода нет, -2 — попытки исчерпаны
+var verifyOTPScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+local max = tonumber(ARGV[2])
+if attempts > max then
+	redis.call('DEL', KEYS[1])
+	return -2
+end
+if redis.call('HGET', KEYS[1], 'hash') == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return 1
+end
+if attempts >= max then
+	redis.call('DEL', KEYS[1])
+	return -2
+end
+return 0
+`)
+
+// OTPRepository хранит хеш одноразового кода и число попыток его ввода.
+// phone — номер в формате E.164
+type OTPRepository interface {
+	// Save сохраняет новый код, сбрасывая счётчик попыток
+	Save(ctx context.Context, phone, codeHash string, ttl time.Duration) error
+	// Verify атомарно засчитывает попытку и сверяет хеш кода. Возвращает nil, если код верный,
+	// ErrOTPMismatch, ErrOTPAttemptsExceeded после maxAttempts попыток или ErrOTPNotFound
+	Verify(ctx context.Context, phone, codeHash string, maxAttempts int) error
+	Delete(ctx context.Context, phone string) error
+	// CooldownLeft возвращает, сколько ещё действует запрет повторной отправки
+	CooldownLeft(ctx context.Context, phone string) (time.Duration, error)
+	// Cooldown запрещает повторную отправку на ttl
+	Cooldown(ctx context.Context, phone string, ttl time.Duration) error
+}
+
+type otpRepo struct {
+	logger *logger.Logger
+	client *redis.Client
+}
+
+func NewOTPRepo(logger *logger.Logger, client *redis.Client) OTPRepository {
+	return &otpRepo{
+		logger: logger,
+		client: client,
+	}
+}
+
+func (r *otpRepo) Save(ctx context.Context, phone, codeHash string, ttl time.Duration) error {
+	key := otpCodePrefix + phone
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, "hash", codeHash, "attempts", 0)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Errorf("failed to save otp for %s: %v", phone, err)
+		return err
+	}
+	return nil
+}
+
+func (r *otpRepo) Verify(ctx context.Context, phone, codeHash string, maxAttempts int) error {
+	res, err := verifyOTPScript.Run(ctx, r.client, []string{otpCodePrefix + phone}, codeHash, maxAttempts).Int()
+	if err != nil {
+		r.logger.Errorf("failed to verify otp for %s: %v", phone, err)
+		return err
+	}
+
+	switch res {
+	case 1:
+		return nil
+	case 0:
+		return ErrOTPMismatch
+	case -1:
+		return ErrOTPNotFound
+	default:
+		return ErrOTPAttemptsExceeded
+	}
+}
+
+func (r *otpRepo) Delete(ctx context.Context, phone string) error {
+	if err := r.client.Del(ctx, otpCodePrefix+phone).Err(); err != nil {
+		r.logger.Errorf("failed to delete otp for %s: %v", phone, err)
+		return err
+	}
+	return nil
+}
+
+func (r *otpRepo) CooldownLeft(ctx context.Context, phone string) (time.Duration, error) {
+	left, err := r.client.PTTL(ctx, otpCooldownPrefix+phone).Result()
+	if err != nil {
+		r.logger.Errorf("failed to get otp cooldown for %s: %v", phone, err)
+		return 0, err
+	}
+	// -2 — ключа нет, -1 — ключ без срока (не бывает, но и не должен блокировать навсегда)
+	if left < 0 {
+		return 0, nil
+	}
+	return left, nil
+}
+
+func (r *otpRepo) Cooldown(ctx context.Context, phone string, ttl time.Duration) error {
+	if err := r.client.Set(ctx, otpCooldownPrefix+phone, 1, ttl).Err(); err != nil {
+		r.logger.Errorf("failed to set otp cooldown for %s: %v", phone, err)
+		return err
+	}
+	return nil
+}
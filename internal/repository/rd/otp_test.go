@@ -0,0 +1,117 @@
+package rd
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestOTPRepo(t *testing.T) (OTPRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewOTPRepo(&logger.Logger{SugaredLogger: zap.NewNop().Sugar()}, client), mr
+}
+
+func TestOTPVerify(t *testing.T) {
+	const number = "+79991234567"
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		guesses []string
+		want    []error
+	}{
+		{name: "right code", guesses: []string{"good"}, want: []error{nil}},
+		{name: "code is single use", guesses: []string{"good", "good"}, want: []error{nil, ErrOTPNotFound}},
+		{name: "wrong then right", guesses: []string{"bad", "good"}, want: []error{ErrOTPMismatch, nil}},
+		{
+			name:    "attempts exhausted",
+			guesses: []string{"bad", "bad", "bad", "good"},
+			want:    []error{ErrOTPMismatch, ErrOTPMismatch, ErrOTPAttemptsExceeded, ErrOTPNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, _ := newTestOTPRepo(t)
+			if err := repo.Save(ctx, number, "good", time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			for i, guess := range tt.guesses {
+				if err := repo.Verify(ctx, number, guess, 3); !errors.Is(err, tt.want[i]) {
+					t.Fatalf("guess %d: err = %v, want %v", i, err, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOTPVerifyConcurrentGuesses(t *testing.T) {
+	const (
+		number      = "+79991234567"
+		maxAttempts = 5
+		guesses     = 50
+	)
+	ctx := context.Background()
+	repo, _ := newTestOTPRepo(t)
+	if err := repo.Save(ctx, number, "good", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		mismatches int
+	)
+	for range guesses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := repo.Verify(ctx, number, "bad", maxAttempts); errors.Is(err, ErrOTPMismatch) {
+				mu.Lock()
+				mismatches++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// сравнение получают только maxAttempts-1 попыток, последняя удаляет код
+	if mismatches != maxAttempts-1 {
+		t.Fatalf("mismatches = %d, want %d", mismatches, maxAttempts-1)
+	}
+	if err := repo.Verify(ctx, number, "good", maxAttempts); !errors.Is(err, ErrOTPNotFound) {
+		t.Fatalf("right code after exhausted attempts: err = %v, want %v", err, ErrOTPNotFound)
+	}
+}
+
+func TestOTPCooldown(t *testing.T) {
+	const number = "+79991234567"
+	ctx := context.Background()
+	repo, mr := newTestOTPRepo(t)
+
+	if left, err := repo.CooldownLeft(ctx, number); err != nil || left != 0 {
+		t.Fatalf("CooldownLeft = %v, %v before cooldown", left, err)
+	}
+	if err := repo.Cooldown(ctx, number, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if left, err := repo.CooldownLeft(ctx, number); err != nil || left <= 0 || left > time.Minute {
+		t.Fatalf("CooldownLeft = %v, %v during cooldown", left, err)
+	}
+
+	mr.FastForward(time.Minute)
+	if left, err := repo.CooldownLeft(ctx, number); err != nil || left != 0 {
+		t.Fatalf("CooldownLeft = %v, %v after cooldown", left, err)
+	}
+}
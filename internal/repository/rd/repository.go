@@ -0,0 +1,23 @@
+package rd
+
+import (
+	"corpord-api/internal/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type RedisRepository struct {
+	logger       *logger.Logger
+	LoginAttempt LoginAttemptRepository
+	OAuthState   OAuthStateRepository
+	OTP          OTPRepository
+}
+
+func New(logger *logger.Logger, client *redis.Client) *RedisRepository {
+	return &RedisRepository{
+		logger:       logger,
+		LoginAttempt: NewLoginAttemptRepo(logger, client),
+		OAuthState:   NewOAuthStateRepo(logger, client),
+		OTP:          NewOTPRepo(logger, client),
+	}
+}
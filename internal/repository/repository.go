@@ -3,17 +3,22 @@ package repository
 import (
 	"corpord-api/internal/logger"
 	"corpord-api/internal/repository/pg"
+	"corpord-api/internal/repository/rd"
 	"corpord-api/pkg/dbx"
+
+	"github.com/redis/go-redis/v9"
 )
 
 type Repository struct {
-	logger       *logger.Logger
-	PgRepository *pg.PostgresRepository
+	logger          *logger.Logger
+	PgRepository    *pg.PostgresRepository
+	RedisRepository *rd.RedisRepository
 }
 
-func New(logger *logger.Logger, qb *dbx.QueryBuilder) *Repository {
+func New(logger *logger.Logger, qb *dbx.QueryBuilder, rdb *redis.Client) *Repository {
 	return &Repository{
-		logger:       logger,
-		PgRepository: pg.New(logger, qb),
+		logger:          logger,
+		PgRepository:    pg.New(logger, qb),
+		RedisRepository: rd.New(logger, rdb),
 	}
 }
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"corpord-api/internal/repository/pg"
+	"time"
 
 	"corpord-api/internal/logger"
 )
@@ -27,3 +28,100 @@ func (t *CleanupRefreshTokensTask) Run(ctx context.Context) error {
 	t.logger.Info("expired refresh tokens cleanup completed")
 	return nil
 }
+
+type CleanupUserTokensTask struct {
+	repo   pg.UserTokenRepository
+	logger *logger.Logger
+}
+
+func NewCleanupUserTokensTask(repo pg.UserTokenRepository, logger *logger.Logger) *CleanupUserTokensTask {
+	return &CleanupUserTokensTask{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (t *CleanupUserTokensTask) Run(ctx context.Context) error {
+	if err := t.repo.CleanupExpired(ctx); err != nil {
+		t.logger.Warnf("failed to cleanup user tokens: %v", err)
+		return err
+	}
+	t.logger.Info("user tokens cleanup completed")
+	return nil
+}
+
+// UserEraser обезличивает пользователей, у которых истёк срок отмены запроса на удаление
+type UserEraser interface {
+	EraseDue(ctx context.Context) (int, error)
+}
+
+type EraseUsersTask struct {
+	eraser UserEraser
+	logger *logger.Logger
+}
+
+func NewEraseUsersTask(eraser UserEraser, logger *logger.Logger) *EraseUsersTask {
+	return &EraseUsersTask{
+		eraser: eraser,
+		logger: logger,
+	}
+}
+
+func (t *EraseUsersTask) Run(ctx context.Context) error {
+	n, err := t.eraser.EraseDue(ctx)
+	if err != nil {
+		t.logger.Warnf("failed to erase users: %v", err)
+		return err
+	}
+	if n > 0 {
+		t.logger.Infof("anonymized %d users", n)
+	}
+	return nil
+}
+
+// DeletedUserEraser обезличивает пользователей, мягко удалённых раньше before
+type DeletedUserEraser interface {
+	EraseDeleted(ctx context.Context, before time.Time) (int, error)
+}
+
+// PurgeDeletedTask окончательно удаляет записи, мягко удалённые дольше retention
+type PurgeDeletedTask struct {
+	repo      pg.Purge
+	users     DeletedUserEraser
+	retention time.Duration
+	logger    *logger.Logger
+}
+
+func NewPurgeDeletedTask(repo pg.Purge, users DeletedUserEraser, retention time.Duration, logger *logger.Logger) *PurgeDeletedTask {
+	return &PurgeDeletedTask{
+		repo:      repo,
+		users:     users,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+func (t *PurgeDeletedTask) Run(ctx context.Context) error {
+	before := time.Now().Add(-t.retention)
+
+	for _, table := range pg.PurgeTables {
+		n, err := t.repo.Purge(ctx, table, before)
+		if err != nil {
+			t.logger.Warnf("failed to purge deleted rows from %s: %v", table, err)
+			return err
+		}
+		if n > 0 {
+			t.logger.Infof("purged %d deleted rows from %s", n, table)
+		}
+	}
+
+	n, err := t.users.EraseDeleted(ctx, before)
+	if err != nil {
+		t.logger.Warnf("failed to anonymize deleted users: %v", err)
+		return err
+	}
+	if n > 0 {
+		t.logger.Infof("anonymized %d deleted users", n)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/mailer"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/model"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Account — восстановление пароля и подтверждение email
+type Account interface {
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, rawToken, newPassword string) error
+	RequestEmailVerification(ctx context.Context, email string) error
+	VerifyEmail(ctx context.Context, rawToken string) error
+}
+
+type account struct {
+	logger      *logger.Logger
+	cfg         *config.Account
+	authRepo    pg.AuthRepository
+	tokenRepo   pg.UserTokenRepository
+	refreshRepo pg.RefreshTokenRepository
+	mailer      mailer.Mailer
+}
+
+func NewAccount(
+	logger *logger.Logger,
+	cfg *config.Account,
+	authRepo pg.AuthRepository,
+	tokenRepo pg.UserTokenRepository,
+	refreshRepo pg.RefreshTokenRepository,
+	mailer mailer.Mailer,
+) Account {
+	return &account{
+		logger:      logger,
+		cfg:         cfg,
+		authRepo:    authRepo,
+		tokenRepo:   tokenRepo,
+		refreshRepo: refreshRepo,
+		mailer:      mailer,
+	}
+}
+
+// hashToken возвращает sha256 хеш токена в hex — в базе хранится только он
+func hashToken(raw string) string {
+	hash := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(hash[:])
+}
+
+// newOneTimeToken генерирует случайный токен для ссылки из письма
+func newOneTimeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// issueToken отзывает прежние токены того же назначения и сохраняет новый
+func (s *account) issueToken(ctx context.Context, userID int, purpose string, ttl time.Duration) (string, error) {
+	if err := s.tokenRepo.InvalidateByUser(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+
+	raw, err := newOneTimeToken()
+	if err != nil {
+		return "", err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.tokenRepo.Save(ctx, &model.UserToken{
+		ID:        id,
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// link добавляет токен к адресу страницы фронтенда
+func link(base, token string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// RequestPasswordReset отправляет письмо со ссылкой для сброса пароля.
+// Если пользователь не найден или письмо не ушло, ошибка не возвращается, чтобы не раскрывать наличие аккаунта.
+func (s *account) RequestPasswordReset(ctx context.Context, email string) error {
+	u, err := s.authRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		s.logger.Infof("password reset requested for unknown email %s", email)
+		return nil
+	}
+
+	raw, err := s.issueToken(ctx, u.ID, model.TokenPurposePasswordReset, s.cfg.PasswordResetTTL)
+	if err != nil {
+		s.logger.Errorf("failed to issue password reset token for user %d: %v", u.ID, err)
+		return err
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Восстановление пароля",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nДля установки нового пароля перейдите по ссылке:\n%s\n\nСсылка действительна %s. Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.\n",
+			u.Name, link(s.cfg.PasswordResetURL, raw), s.cfg.PasswordResetTTL,
+		),
+	})
+	if err != nil {
+		// ответ не должен отличаться от ответа на неизвестный email
+		s.logger.Errorf("failed to send password reset email to user %d: %v", u.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword устанавливает новый пароль по одноразовому токену
+// и отзывает все refresh-сессии пользователя
+func (s *account) ResetPassword(ctx context.Context, rawToken, newPassword string) error {
+	t, err := s.tokenRepo.Consume(ctx, hashToken(rawToken), model.TokenPurposePasswordReset)
+	if err != nil {
+		if errors.Is(err, pg.ErrUserTokenNotFound) {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	if err := s.authRepo.UpdatePassword(ctx, t.UserID, string(hash)); err != nil {
+		if errors.Is(err, pg.ErrNotFound) {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+
+	// пользователь подтвердил владение почтой, перейдя по ссылке
+	if err := s.authRepo.SetEmailVerified(ctx, t.UserID); err != nil {
+		s.logger.Warnf("failed to mark email verified for user %d: %v", t.UserID, err)
+	}
+
+	if err := s.refreshRepo.RevokeAllByUser(ctx, t.UserID); err != nil {
+		s.logger.Errorf("failed to revoke sessions after password reset for user %d: %v", t.UserID, err)
+		return err
+	}
+
+	s.logger.Infof("password reset for user %d, all sessions revoked", t.UserID)
+	return nil
+}
+
+// RequestEmailVerification отправляет письмо для подтверждения email.
+// Для неизвестных и уже подтверждённых адресов ничего не делает.
+func (s *account) RequestEmailVerification(ctx context.Context, email string) error {
+	u, err := s.authRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if u == nil || u.EmailVerified {
+		return nil
+	}
+
+	raw, err := s.issueToken(ctx, u.ID, model.TokenPurposeEmailVerification, s.cfg.EmailVerificationTTL)
+	if err != nil {
+		s.logger.Errorf("failed to issue email verification token for user %d: %v", u.ID, err)
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      u.Email,
+		Subject: "Подтверждение email",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nПодтвердите адрес электронной почты, перейдя по ссылке:\n%s\n\nСсылка действительна %s.\n",
+			u.Name, link(s.cfg.EmailVerificationURL, raw), s.cfg.EmailVerificationTTL,
+		),
+	})
+}
+
+// VerifyEmail подтверждает email по одноразовому токену
+func (s *account) VerifyEmail(ctx context.Context, rawToken string) error {
+	t, err := s.tokenRepo.Consume(ctx, hashToken(rawToken), model.TokenPurposeEmailVerification)
+	if err != nil {
+		if errors.Is(err, pg.ErrUserTokenNotFound) {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+
+	if err := s.authRepo.SetEmailVerified(ctx, t.UserID); err != nil {
+		if errors.Is(err, pg.ErrNotFound) {
+			return ErrInvalidUserToken
+		}
+		return err
+	}
+
+	s.logger.Infof("email verified for user %d", t.UserID)
+	return nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/mailer"
+	"corpord-api/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+type failingMailer struct {
+	sent int
+}
+
+func (m *failingMailer) Send(context.Context, mailer.Message) error {
+	m.sent++
+	return errors.New("smtp: connection refused")
+}
+
+func TestRequestPasswordResetHidesAccountExistence(t *testing.T) {
+	auth := &fakeAuthRepo{users: map[int]*model.UserDB{
+		1: {ID: 1, Name: "Ivan", Email: "ivan@example.com"},
+	}}
+	tokens := &fakeUserTokenRepo{}
+	m := &failingMailer{}
+	cfg := &config.Account{PasswordResetURL: "http://localhost/reset", PasswordResetTTL: time.Hour}
+	s := NewAccount(testLogger(), cfg, auth, tokens, fakeRefreshRepo{}, m)
+
+	for _, email := range []string{"ivan@example.com", "nobody@example.com"} {
+		if err := s.RequestPasswordReset(context.Background(), email); err != nil {
+			t.Fatalf("RequestPasswordReset(%q) = %v, want nil", email, err)
+		}
+	}
+	if m.sent != 1 || len(tokens.saved) != 1 {
+		t.Fatalf("sent = %d, tokens = %d, want one email for the known user", m.sent, len(tokens.saved))
+	}
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/model"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyScheme начало каждого ключа: ck_<prefix>_<secret>
+const apiKeyScheme = "ck"
+
+type APIKey interface {
+	// CreateServiceAccount создаёт сервисный аккаунт с ролью, права которой не шире прав caller
+	CreateServiceAccount(ctx context.Context, input *model.ServiceAccountCreate, caller *model.Claims) (*model.UserResponse, error)
+	List(ctx context.Context, userID int) ([]*model.APIKeyResponse, error)
+	// Create выпускает ключ для самого caller или для сервисного аккаунта с правами не шире его
+	Create(ctx context.Context, userID int, input *model.APIKeyCreate, caller *model.Claims) (*model.APIKeyCreated, error)
+	// Rotate выпускает новый ключ с теми же параметрами и сразу отзывает старый
+	Rotate(ctx context.Context, id uuid.UUID, caller *model.Claims) (*model.APIKeyCreated, error)
+	Revoke(ctx context.Context, id uuid.UUID, adminID int) error
+	// Authenticate проверяет ключ из X-API-Key и возвращает claims владельца ключа
+	Authenticate(ctx context.Context, rawKey string) (*model.Claims, error)
+}
+
+type apiKey struct {
+	logger *logger.Logger
+	repo   pg.APIKeyRepository
+	users  pg.AuthRepository
+	roles  pg.Role
+	access Role
+	events pg.SecurityEventRepository
+}
+
+func NewAPIKey(
+	logger *logger.Logger,
+	repo pg.APIKeyRepository,
+	users pg.AuthRepository,
+	roles pg.Role,
+	access Role,
+	events pg.SecurityEventRepository,
+) APIKey {
+	return &apiKey{
+		logger: logger,
+		repo:   repo,
+		users:  users,
+		roles:  roles,
+		access: access,
+		events: events,
+	}
+}
+
+func (s *apiKey) CreateServiceAccount(ctx context.Context, input *model.ServiceAccountCreate, caller *model.Claims) (*model.UserResponse, error) {
+	role, err := s.roles.ByID(ctx, input.RoleID)
+	if err != nil {
+		return nil, mapRoleError(err)
+	}
+	if err := s.covers(ctx, caller, role.Name); err != nil {
+		return nil, err
+	}
+
+	id, err := s.users.CreateServiceAccount(ctx, input.Name, input.RoleID)
+	if err != nil {
+		if errors.Is(err, pg.ErrForeignKeyViolation) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	u, err := s.users.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u.ToResponse(), nil
+}
+
+func (s *apiKey) List(ctx context.Context, userID int) ([]*model.APIKeyResponse, error) {
+	keys, err := s.repo.ByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*model.APIKeyResponse, len(keys))
+	for i, k := range keys {
+		resp[i] = k.ToResponse()
+	}
+	return resp, nil
+}
+
+func (s *apiKey) Create(ctx context.Context, userID int, input *model.APIKeyCreate, caller *model.Claims) (*model.APIKeyCreated, error) {
+	if err := s.authorize(ctx, caller, userID); err != nil {
+		return nil, err
+	}
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	scopes, err := s.validateScopes(ctx, input.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, raw, err := newAPIKey(userID, input.Name, scopes, input.ExpiresAt, caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, model.SecurityEventAPIKeyCreated, userID, caller.UserID)
+	return &model.APIKeyCreated{APIKeyResponse: *key.ToResponse(), Key: raw}, nil
+}
+
+func (s *apiKey) Rotate(ctx context.Context, id uuid.UUID, caller *model.Claims) (*model.APIKeyCreated, error) {
+	old, err := s.repo.ByID(ctx, id)
+	if err != nil {
+		return nil, mapAPIKeyError(err)
+	}
+	if old.RevokedAt != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	// новый ключ возвращается вызывающему, поэтому проверки те же, что при выпуске
+	if err := s.authorize(ctx, caller, old.UserID); err != nil {
+		return nil, err
+	}
+
+	key, raw, err := newAPIKey(old.UserID, old.Name, old.Scopes, old.ExpiresAt, caller.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Rotate(ctx, old.ID, key); err != nil {
+		return nil, mapAPIKeyError(err)
+	}
+
+	s.recordEvent(ctx, model.SecurityEventAPIKeyRotated, old.UserID, caller.UserID)
+	return &model.APIKeyCreated{APIKeyResponse: *key.ToResponse(), Key: raw}, nil
+}
+
+func (s *apiKey) Revoke(ctx context.Context, id uuid.UUID, adminID int) error {
+	key, err := s.repo.ByID(ctx, id)
+	if err != nil {
+		return mapAPIKeyError(err)
+	}
+
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		return mapAPIKeyError(err)
+	}
+
+	s.recordEvent(ctx, model.SecurityEventAPIKeyRevoked, key.UserID, adminID)
+	return nil
+}
+
+func (s *apiKey) Authenticate(ctx context.Context, rawKey string) (*model.Claims, error) {
+	prefix, ok := apiKeyPrefix(rawKey)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.ByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, pg.ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashToken(rawKey))) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	u, err := s.users.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil || u.BlockedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if err := s.repo.Touch(ctx, key.ID); err != nil {
+		s.logger.Warnf("failed to update last use of api key %s: %v", key.Prefix, err)
+	}
+
+	// ключ действует до отзыва, поэтому срок claims — срок самого ключа или конец запроса
+	expiresAt := now.Add(time.Minute)
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(expiresAt) {
+		expiresAt = *key.ExpiresAt
+	}
+
+	return model.NewClaims(model.NewClaimsParams{
+		UserID:     u.ID,
+		Email:      u.Email,
+		Role:       u.Role,
+		Provider:   model.ProviderAPIKey,
+		ProviderID: key.Prefix,
+		ExpiresAt:  expiresAt,
+		AMR:        []string{model.ProviderAPIKey},
+		AuthTime:   now,
+		Scopes:     key.ScopeList(),
+		Language:   u.Language,
+	}), nil
+}
+
+// authorize разрешает выпуск ключа для пользователя userID: им может быть сам caller
+// или сервисный аккаунт, и права пользователя не должны быть шире прав caller
+func (s *apiKey) authorize(ctx context.Context, caller *model.Claims, userID int) error {
+	u, err := s.users.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrUserNotFound
+	}
+	if u.ID != caller.UserID && !u.IsService {
+		return ErrAPIKeyTarget
+	}
+	return s.covers(ctx, caller, u.Role)
+}
+
+// covers возвращает ErrPrivilegeEscalation, если у роли roleName есть права, которых нет у caller
+func (s *apiKey) covers(ctx context.Context, caller *model.Claims, roleName string) error {
+	ok, err := s.access.Covers(ctx, caller, roleName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPrivilegeEscalation
+	}
+	return nil
+}
+
+// validateScopes проверяет, что все коды есть в справочнике прав, и возвращает их через пробел
+func (s *apiKey) validateScopes(ctx context.Context, scopes []string) (string, error) {
+	if len(scopes) == 0 {
+		return "", nil
+	}
+
+	perms, err := s.roles.AllPermissions(ctx)
+	if err != nil {
+		return "", err
+	}
+	known := make(map[string]struct{}, len(perms))
+	for _, p := range perms {
+		known[p.Code] = struct{}{}
+	}
+
+	seen := make(map[string]struct{}, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if _, ok := known[scope]; !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownPermission, scope)
+		}
+		if _, dup := seen[scope]; dup {
+			continue
+		}
+		seen[scope] = struct{}{}
+		result = append(result, scope)
+	}
+	return strings.Join(result, " "), nil
+}
+
+func (s *apiKey) recordEvent(ctx context.Context, eventType string, userID, adminID int) {
+	event := &model.SecurityEvent{
+		EventType: eventType,
+		UserID:    &userID,
+		ActorID:   &adminID,
+	}
+	if err := s.events.Save(ctx, event); err != nil {
+		s.logger.Warnf("failed to record security event %s: %v", eventType, err)
+	}
+}
+
+// newAPIKey генерирует ключ ck_<prefix>_<secret>. Возвращает запись для базы и сам ключ,
+// который больше нигде не сохраняется
+func newAPIKey(userID int, name, scopes string, expiresAt *time.Time, adminID int) (*model.APIKey, string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, "", err
+	}
+
+	public := make([]byte, 4)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(public); err != nil {
+		return nil, "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+
+	prefix := apiKeyScheme + "_" + hex.EncodeToString(public)
+	raw := prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &model.APIKey{
+		ID:        id,
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashToken(raw),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedBy: &adminID,
+	}, raw, nil
+}
+
+// apiKeyPrefix выделяет открытую часть ключа
+func apiKeyPrefix(raw string) (string, bool) {
+	parts := strings.SplitN(raw, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyScheme || len(parts[1]) != 8 || parts[2] == "" {
+		return "", false
+	}
+	return parts[0] + "_" + parts[1], true
+}
+
+func mapAPIKeyError(err error) error {
+	if errors.Is(err, pg.ErrAPIKeyNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	return err
+}
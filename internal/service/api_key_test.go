@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"corpord-api/model"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestAPIKey(users map[int]*model.UserDB) (*apiKey, *fakeAuthRepo) {
+	roles := newFakeRoleRepo()
+	auth := &fakeAuthRepo{users: users}
+	s := NewAPIKey(
+		testLogger(),
+		&fakeAPIKeyRepo{keys: map[uuid.UUID]*model.APIKey{}},
+		auth,
+		roles,
+		NewRole(testLogger(), roles),
+		fakeEvents{},
+	)
+	return s.(*apiKey), auth
+}
+
+func TestAPIKeyCreateServiceAccountRefusesWiderRole(t *testing.T) {
+	support := &model.Claims{UserID: 1, Role: "support"}
+	scoped := &model.Claims{UserID: 1, Role: "support", Scopes: []string{model.PermAPIKeysManage}}
+
+	tests := []struct {
+		name   string
+		caller *model.Claims
+		roleID int
+		want   error
+	}{
+		{"role with more permissions", support, 1, ErrPrivilegeEscalation},
+		{"same role", support, 2, nil},
+		{"narrower role", support, 3, nil},
+		{"role outside api key scopes", scoped, 3, ErrPrivilegeEscalation},
+		{"unknown role", support, 42, ErrRoleNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, auth := newTestAPIKey(map[int]*model.UserDB{})
+
+			_, err := s.CreateServiceAccount(context.Background(), &model.ServiceAccountCreate{Name: "crm", RoleID: tt.roleID}, tt.caller)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil && len(auth.created) != 0 {
+				t.Fatalf("service account created despite refusal")
+			}
+		})
+	}
+}
+
+func TestAPIKeyCreateRefusesForeignOrWiderTarget(t *testing.T) {
+	users := map[int]*model.UserDB{
+		1: {ID: 1, Role: "support"},
+		2: {ID: 2, Role: "fleet"},
+		3: {ID: 3, Role: model.RoleAdmin},
+		4: {ID: 4, Role: model.RoleAdmin, IsService: true},
+		5: {ID: 5, Role: "fleet", IsService: true},
+	}
+	caller := &model.Claims{UserID: 1, Role: "support"}
+
+	tests := []struct {
+		name   string
+		userID int
+		want   error
+	}{
+		{"own key", 1, nil},
+		{"service account with narrower role", 5, nil},
+		{"another user", 2, ErrAPIKeyTarget},
+		{"admin user", 3, ErrAPIKeyTarget},
+		{"service account with admin role", 4, ErrPrivilegeEscalation},
+		{"missing user", 9, ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestAPIKey(users)
+
+			out, err := s.Create(context.Background(), tt.userID, &model.APIKeyCreate{Name: "ci"}, caller)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && out.Key == "" {
+				t.Fatalf("key not returned")
+			}
+		})
+	}
+}
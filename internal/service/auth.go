@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/repository/rd"
+	"corpord-api/internal/sms"
 	"corpord-api/internal/sso"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +16,7 @@ import (
 	"corpord-api/internal/repository/pg"
 	"corpord-api/internal/token"
 	"corpord-api/model"
+	"corpord-api/pkg/dbx"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -20,20 +25,47 @@ import (
 type Auth interface {
 	Register(ctx context.Context, user *model.UserCreate, userAgent, ip string) (*model.TokenPair, error)
 	Login(ctx context.Context, credentials model.UserLogin, userAgent, ip string) (*model.TokenPair, error)
-	SSOLogin(ctx context.Context, provider, providerID, email, name, userAgent, ip string) (*model.TokenPair, error)
+	SSOLogin(ctx context.Context, provider, code string, params sso.AuthParams, email, name, userAgent, ip string) (*model.TokenPair, error)
+	// BeginSSO возвращает URL провайдера и значение для cookie, которое привязывает вход к браузеру
+	BeginSSO(ctx context.Context, provider, returnTo string) (string, string, error)
+	// CompleteSSO завершает вход или привязку. session — refresh token из cookie, по нему
+	// проверяется, что привязку завершает тот же пользователь, который её начал
+	CompleteSSO(ctx context.Context, provider string, cb sso.Callback, binding, session, userAgent, ip string) (*model.TokenPair, string, error)
+	BeginLink(ctx context.Context, userID int, provider, returnTo string) (string, string, error)
+	TelegramLogin(ctx context.Context, data sso.TelegramAuthData, userAgent, ip string) (*model.TokenPair, error)
+	RequestPhoneCode(ctx context.Context, phone, userAgent, ip string) (*model.PhoneCodeResponse, error)
+	PhoneLogin(ctx context.Context, req model.PhoneLoginRequest, userAgent, ip string) (*model.TokenPair, error)
+	ConfirmPhone(ctx context.Context, userID int, req model.PhoneLoginRequest) (string, error)
+	Identities(ctx context.Context, userID int) ([]*model.UserIdentity, error)
+	Unlink(ctx context.Context, userID int, identityID string) error
 	ValidateToken(tokenString string) (int, error)
 	Refresh(ctx context.Context, rawRefreshToken, userAgent, ip string) (*model.TokenPair, error)
 	Logout(ctx context.Context, rawRefreshToken string) error
 	LogoutAll(ctx context.Context, userID int) error
+	Unlock(ctx context.Context, userID, adminID int) error
+	// Block блокирует пользователя, права которого не шире прав администратора из claims
+	Block(ctx context.Context, userID int, admin *model.Claims, reason string) error
+	Unblock(ctx context.Context, userID int, admin *model.Claims) error
+	Impersonate(ctx context.Context, userID int, admin *model.Claims, userAgent, ip string) (*model.ImpersonationResponse, error)
 }
 
 type auth struct {
-	token        token.Manager
-	logger       *logger.Logger
-	authRepo     pg.AuthRepository
-	refreshRepo  pg.RefreshTokenRepository
-	userIdentity pg.UserIdentitiesRepository
-	sso          *sso.Registry
+	token            token.Manager
+	logger           *logger.Logger
+	authRepo         pg.AuthRepository
+	refreshRepo      pg.RefreshTokenRepository
+	userIdentity     pg.UserIdentitiesRepository
+	events           pg.SecurityEventRepository
+	tx               dbx.Transactor
+	access           Role
+	guard            *loginGuard
+	states           rd.OAuthStateRepository
+	ssoCfg           *config.SSO
+	sso              *sso.Registry
+	otp              rd.OTPRepository
+	otpCfg           *config.OTP
+	sms              sms.Sender
+	impersonationTTL time.Duration
 }
 
 func NewAuth(
@@ -42,15 +74,43 @@ func NewAuth(
 	authRepo pg.AuthRepository,
 	refreshRepo pg.RefreshTokenRepository,
 	userIdentity pg.UserIdentitiesRepository,
+	events pg.SecurityEventRepository,
+	tx dbx.Transactor,
+	access Role,
+	attempts rd.LoginAttemptRepository,
+	loginCfg *config.LoginProtection,
+	states rd.OAuthStateRepository,
+	ssoCfg *config.SSO,
 	sso *sso.Registry,
+	otp rd.OTPRepository,
+	otpCfg *config.OTP,
+	sender sms.Sender,
+	impersonationTTL time.Duration,
 ) Auth {
 	return &auth{
-		token:        token,
-		logger:       logger,
-		authRepo:     authRepo,
-		refreshRepo:  refreshRepo,
-		userIdentity: userIdentity,
-		sso:          sso,
+		token:            token,
+		logger:           logger,
+		authRepo:         authRepo,
+		refreshRepo:      refreshRepo,
+		userIdentity:     userIdentity,
+		events:           events,
+		tx:               tx,
+		access:           access,
+		guard:            newLoginGuard(logger, loginCfg, attempts),
+		states:           states,
+		ssoCfg:           ssoCfg,
+		sso:              sso,
+		otp:              otp,
+		otpCfg:           otpCfg,
+		sms:              sender,
+		impersonationTTL: impersonationTTL,
+	}
+}
+
+// recordEvent пишет событие в журнал безопасности, ошибки только логируются
+func (s *auth) recordEvent(ctx context.Context, event *model.SecurityEvent) {
+	if err := s.events.Save(ctx, event); err != nil {
+		s.logger.Warnf("failed to record security event %s: %v", event.EventType, err)
 	}
 }
 
@@ -64,6 +124,7 @@ func (s *auth) generateAccessToken(u *model.UserDB, amr string) (string, error)
 		ProviderID: u.ProviderID,
 		AMR:        []string{amr},
 		AuthTime:   time.Now(),
+		Language:   u.Language,
 	})
 }
 
@@ -112,6 +173,9 @@ func (s *auth) issueTokens(
 	u *model.UserDB,
 	userAgent, ip, amr string,
 ) (*model.TokenPair, error) {
+	if u.BlockedAt != nil {
+		return nil, ErrUserBlocked
+	}
 
 	access, err := s.generateAccessToken(u, amr)
 	if err != nil {
@@ -171,15 +235,47 @@ func (s *auth) Login(ctx context.Context, credentials model.UserLogin, userAgent
 		return nil, ErrNoFields
 	}
 
+	event := &model.SecurityEvent{
+		Email:     &credentials.Email,
+		IP:        &ip,
+		UserAgent: &userAgent,
+	}
+
+	if err := s.guard.check(ctx, credentials.Email, ip); err != nil {
+		event.EventType = model.SecurityEventLoginLocked
+		s.recordEvent(ctx, event)
+		return nil, err
+	}
+
 	u, err := s.authRepo.GetUserByEmail(ctx, credentials.Email)
-	if err != nil || u == nil {
+	if err != nil {
+		return nil, err
+	}
+
+	// Один и тот же ответ для несуществующего email, SSO-аккаунта без пароля и неверного пароля
+	if u == nil || u.PasswordHash == nil ||
+		bcrypt.CompareHashAndPassword([]byte(*u.PasswordHash), []byte(credentials.Password)) != nil {
+		s.guard.fail(ctx, credentials.Email, ip)
+		event.EventType = model.SecurityEventLoginFailed
+		if u != nil {
+			event.UserID = &u.ID
+		}
+		s.recordEvent(ctx, event)
 		return nil, ErrInvalidCredentials
 	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(*u.PasswordHash), []byte(credentials.Password)); err != nil {
-		return nil, ErrInvalidPass
+	if u.BlockedAt != nil {
+		event.EventType = model.SecurityEventLoginFailed
+		event.UserID = &u.ID
+		s.recordEvent(ctx, event)
+		return nil, ErrUserBlocked
 	}
 
+	s.guard.succeed(ctx, credentials.Email)
+	event.EventType = model.SecurityEventLoginSuccess
+	event.UserID = &u.ID
+	s.recordEvent(ctx, event)
+
 	u.Provider = "local"
 	u.ProviderID = fmt.Sprintf("local:%d", u.ID)
 
@@ -197,6 +293,10 @@ func (s *auth) ValidateToken(tokenString string) (int, error) {
 	if err != nil || u == nil {
 		return 0, ErrUserNotFound
 	}
+	// заблокированный пользователь теряет доступ сразу, не дожидаясь истечения токена
+	if u.BlockedAt != nil {
+		return 0, ErrUserBlocked
+	}
 
 	return claims.UserID, nil
 }
@@ -213,7 +313,7 @@ func (s *auth) Refresh(
 	// 1. Найти сессию
 	session, err := s.refreshRepo.FindByHash(ctx, hashHex)
 	if err != nil {
-		return nil, ErrInvalidRefreshToken
+		return nil, mapRefreshTokenError(err)
 	}
 
 	// 2. Проверить срок действия
@@ -224,10 +324,17 @@ func (s *auth) Refresh(
 
 	// 3. Получить пользователя
 	u, err := s.authRepo.GetUserByID(ctx, session.UserID)
-	if err != nil || u == nil {
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
 		_ = s.refreshRepo.Revoke(ctx, session.ID)
 		return nil, ErrInvalidRefreshToken
 	}
+	if u.BlockedAt != nil {
+		_ = s.refreshRepo.Revoke(ctx, session.ID)
+		return nil, ErrUserBlocked
+	}
 
 	// 4. Сгенерировать новую пару токенов
 	newTokens, err := s.issueTokens(ctx, u, userAgent, ip, "refresh")
@@ -251,7 +358,8 @@ func (s *auth) Refresh(
 		UserAgent: userAgent,
 	}
 	if err := s.refreshRepo.RefreshToken(ctx, hashHex, newSession); err != nil {
-		return nil, err
+		// токен успели использовать параллельным запросом
+		return nil, mapRefreshTokenError(err)
 	}
 
 	_ = s.refreshRepo.CleanupExpired(ctx)
@@ -260,6 +368,15 @@ func (s *auth) Refresh(
 	return newTokens, nil
 }
 
+// mapRefreshTokenError переводит отсутствие сессии в ErrInvalidRefreshToken,
+// остальные ошибки базы возвращаются как есть
+func mapRefreshTokenError(err error) error {
+	if errors.Is(err, pg.ErrRefreshTokenNotFound) {
+		return ErrInvalidRefreshToken
+	}
+	return err
+}
+
 // Logout отзывает один конкретный refresh токен
 func (s *auth) Logout(ctx context.Context, rawRefreshToken string) error {
 	hash := sha256.Sum256([]byte(rawRefreshToken))
@@ -267,7 +384,7 @@ func (s *auth) Logout(ctx context.Context, rawRefreshToken string) error {
 
 	session, err := s.refreshRepo.FindByHash(ctx, hashHex)
 	if err != nil {
-		return ErrInvalidRefreshToken
+		return mapRefreshTokenError(err)
 	}
 
 	return s.refreshRepo.Revoke(ctx, session.ID)
@@ -277,3 +394,26 @@ func (s *auth) Logout(ctx context.Context, rawRefreshToken string) error {
 func (s *auth) LogoutAll(ctx context.Context, userID int) error {
 	return s.refreshRepo.RevokeAllByUser(ctx, userID)
 }
+
+// Unlock снимает блокировку входа с аккаунта
+func (s *auth) Unlock(ctx context.Context, userID, adminID int) error {
+	u, err := s.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.guard.unlock(ctx, u.Email); err != nil {
+		return err
+	}
+
+	s.recordEvent(ctx, &model.SecurityEvent{
+		EventType: model.SecurityEventAccountUnlocked,
+		UserID:    &u.ID,
+		ActorID:   &adminID,
+		Email:     &u.Email,
+	})
+	return nil
+}
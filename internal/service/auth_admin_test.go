@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"corpord-api/model"
+	"errors"
+	"testing"
+)
+
+func newTestAdminAuth(users map[int]*model.UserDB) (*auth, *fakeAuthRepo) {
+	repo := &fakeAuthRepo{users: users}
+	return &auth{
+		logger:      testLogger(),
+		authRepo:    repo,
+		refreshRepo: fakeRefreshRepo{},
+		events:      fakeEvents{},
+		access:      NewRole(testLogger(), newFakeRoleRepo()),
+	}, repo
+}
+
+func TestAuthBlockRefusesWiderTarget(t *testing.T) {
+	users := map[int]*model.UserDB{
+		2: {ID: 2, Role: "fleet"},
+		3: {ID: 3, Role: model.RoleAdmin},
+	}
+	admin := &model.Claims{UserID: 1, Role: "support"}
+
+	tests := []struct {
+		name   string
+		userID int
+		want   error
+	}{
+		{"narrower role", 2, nil},
+		{"wider role", 3, ErrPrivilegeEscalation},
+		{"own account", 1, ErrSelfAction},
+		{"missing user", 9, ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestAdminAuth(users)
+
+			err := s.Block(context.Background(), tt.userID, admin, "")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if blocked := len(repo.blocked) == 1; blocked != (tt.want == nil) {
+				t.Fatalf("blocked = %v, want %v", blocked, tt.want == nil)
+			}
+		})
+	}
+}
+
+func TestAuthUnblockRefusesWiderTarget(t *testing.T) {
+	users := map[int]*model.UserDB{
+		2: {ID: 2, Role: "fleet"},
+		3: {ID: 3, Role: model.RoleAdmin},
+	}
+	admin := &model.Claims{UserID: 1, Role: "support"}
+
+	tests := []struct {
+		name   string
+		userID int
+		want   error
+	}{
+		{"narrower role", 2, nil},
+		{"wider role", 3, ErrPrivilegeEscalation},
+		{"missing user", 9, ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestAdminAuth(users)
+
+			err := s.Unblock(context.Background(), tt.userID, admin)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if unblocked := len(repo.unblocked) == 1; unblocked != (tt.want == nil) {
+				t.Fatalf("unblocked = %v, want %v", unblocked, tt.want == nil)
+			}
+		})
+	}
+}
+
+func TestAuthImpersonateRefusesWiderTarget(t *testing.T) {
+	users := map[int]*model.UserDB{
+		3: {ID: 3, Role: "custom"},
+		4: {ID: 4, Role: "fleet", IsService: true},
+	}
+	// роль из базы с правом, которого нет у support: строка роли не admin, но права шире
+	testRolePermissions["custom"] = []string{model.PermBusesWrite, model.PermRolesManage}
+	t.Cleanup(func() { delete(testRolePermissions, "custom") })
+
+	s, _ := newTestAdminAuth(users)
+	admin := &model.Claims{UserID: 1, Role: "support"}
+
+	for _, userID := range []int{3, 4} {
+		if _, err := s.Impersonate(context.Background(), userID, admin, "", ""); !errors.Is(err, ErrCannotImpersonate) {
+			t.Fatalf("user %d: err = %v, want %v", userID, err, ErrCannotImpersonate)
+		}
+	}
+}
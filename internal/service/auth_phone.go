@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/internal/repository/rd"
+	"corpord-api/model"
+	"corpord-api/pkg/phone"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// RequestPhoneCode отправляет одноразовый код на телефон.
+// Повторная отправка на тот же номер возможна не чаще ResendInterval
+func (s *auth) RequestPhoneCode(ctx context.Context, rawPhone, userAgent, ip string) (*model.PhoneCodeResponse, error) {
+	number, err := phone.Normalize(rawPhone, s.otpCfg.DefaultCountry)
+	if err != nil {
+		return nil, ErrInvalidPhone
+	}
+
+	left, err := s.otp.CooldownLeft(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if left > 0 {
+		return nil, &LockoutError{RetryAfter: left}
+	}
+
+	code, err := generateOTP(s.otpCfg.Length)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate code: %w", err)
+	}
+
+	if err := s.otp.Save(ctx, number, hashOTP(number, code), s.otpCfg.TTL); err != nil {
+		return nil, err
+	}
+
+	text := fmt.Sprintf("Код для входа: %s. Никому его не сообщайте", code)
+	if err := s.sms.Send(ctx, number, text); err != nil {
+		s.logger.Errorf("failed to send otp to %s: %v", phone.Mask(number), err)
+		return nil, fmt.Errorf("failed to send sms: %w", err)
+	}
+
+	// запрет ставится только после отправки: неудачная отправка не должна лишать повторной попытки
+	if err := s.otp.Cooldown(ctx, number, s.otpCfg.ResendInterval); err != nil {
+		s.logger.Errorf("failed to set otp cooldown for %s: %v", phone.Mask(number), err)
+	}
+
+	s.recordEvent(ctx, &model.SecurityEvent{
+		EventType: model.SecurityEventOTPSent,
+		IP:        &ip,
+		UserAgent: &userAgent,
+	})
+
+	return &model.PhoneCodeResponse{
+		Phone:     number,
+		ExpiresIn: int(s.otpCfg.TTL.Seconds()),
+		ResendIn:  int(s.otpCfg.ResendInterval.Seconds()),
+	}, nil
+}
+
+// PhoneLogin проверяет код и выдаёт токены с AMR otp.
+// Пользователь с таким номером создаётся при первом входе
+func (s *auth) PhoneLogin(ctx context.Context, req model.PhoneLoginRequest, userAgent, ip string) (*model.TokenPair, error) {
+	number, err := phone.Normalize(req.Phone, s.otpCfg.DefaultCountry)
+	if err != nil {
+		return nil, ErrInvalidPhone
+	}
+
+	event := &model.SecurityEvent{
+		IP:        &ip,
+		UserAgent: &userAgent,
+	}
+
+	if err := s.checkOTP(ctx, number, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidOTP) || errors.Is(err, ErrOTPAttemptsExceeded) {
+			event.EventType = model.SecurityEventLoginFailed
+			s.recordEvent(ctx, event)
+		}
+		return nil, err
+	}
+
+	u, err := s.findOrCreateUserByPhone(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	event.EventType = model.SecurityEventLoginSuccess
+	event.UserID = &u.ID
+	s.recordEvent(ctx, event)
+
+	u.Provider = "phone"
+	u.ProviderID = number
+
+	return s.issueTokens(ctx, u, userAgent, ip, "otp")
+}
+
+// ConfirmPhone привязывает номер к аккаунту после проверки кода, отправленного RequestPhoneCode
+func (s *auth) ConfirmPhone(ctx context.Context, userID int, req model.PhoneLoginRequest) (string, error) {
+	number, err := phone.Normalize(req.Phone, s.otpCfg.DefaultCountry)
+	if err != nil {
+		return "", ErrInvalidPhone
+	}
+
+	if err := s.checkOTP(ctx, number, req.Code); err != nil {
+		return "", err
+	}
+
+	if err := s.authRepo.SetPhone(ctx, userID, number); err != nil {
+		switch {
+		case errors.Is(err, pg.ErrAlreadyExists):
+			return "", ErrPhoneTaken
+		case errors.Is(err, pg.ErrNotFound):
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	return number, nil
+}
+
+// checkOTP сверяет код. Попытка засчитывается до сравнения, код удаляется
+// после успешной проверки (он одноразовый) или после последней попытки
+func (s *auth) checkOTP(ctx context.Context, number, code string) error {
+	err := s.otp.Verify(ctx, number, hashOTP(number, code), s.otpCfg.MaxAttempts)
+	switch {
+	case errors.Is(err, rd.ErrOTPNotFound), errors.Is(err, rd.ErrOTPMismatch):
+		return ErrInvalidOTP
+	case errors.Is(err, rd.ErrOTPAttemptsExceeded):
+		return ErrOTPAttemptsExceeded
+	}
+	return err
+}
+
+func (s *auth) findOrCreateUserByPhone(ctx context.Context, number string) (*model.UserDB, error) {
+	u, err := s.authRepo.GetUserByPhone(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup user by phone: %w", err)
+	}
+	if u != nil {
+		return u, nil
+	}
+
+	uid, err := s.authRepo.CreatePhoneUser(ctx, number)
+	if err != nil {
+		// параллельный вход с тем же номером успел создать пользователя
+		if errors.Is(err, pg.ErrAlreadyExists) {
+			u, err = s.authRepo.GetUserByPhone(ctx, number)
+			if err == nil && u == nil {
+				// номер принадлежит удалённому аккаунту
+				err = ErrUserNotFound
+			}
+			return u, err
+		}
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	created, err := s.authRepo.GetUserByID(ctx, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch created user: %w", err)
+	}
+	if created == nil {
+		return nil, ErrUserNotFound
+	}
+	return created, nil
+}
+
+// generateOTP возвращает код из length случайных цифр
+func generateOTP(length int) (string, error) {
+	if length <= 0 {
+		length = 6
+	}
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", length, n), nil
+}
+
+// hashOTP привязывает код к номеру, чтобы хеш не совпадал для разных телефонов
+func hashOTP(number, code string) string {
+	return hashToken(number + ":" + code)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/repository/rd"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeSMS запоминает последний отправленный код или возвращает err
+type fakeSMS struct {
+	err  error
+	code string
+}
+
+func (f *fakeSMS) Send(_ context.Context, _, text string) error {
+	if f.err != nil {
+		return f.err
+	}
+	code, _, _ := strings.Cut(strings.TrimPrefix(text, "Код для входа: "), ".")
+	f.code = code
+	return nil
+}
+
+func newTestPhoneAuth(t *testing.T, sender *fakeSMS) *auth {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return &auth{
+		logger: testLogger(),
+		events: fakeEvents{},
+		otp:    rd.NewOTPRepo(testLogger(), client),
+		otpCfg: &config.OTP{
+			Length:         6,
+			TTL:            5 * time.Minute,
+			ResendInterval: time.Minute,
+			MaxAttempts:    3,
+			DefaultCountry: "7",
+		},
+		sms: sender,
+	}
+}
+
+func TestRequestPhoneCodeCooldownAfterSend(t *testing.T) {
+	ctx := context.Background()
+	sender := &fakeSMS{err: errors.New("gateway unavailable")}
+	s := newTestPhoneAuth(t, sender)
+
+	if _, err := s.RequestPhoneCode(ctx, "+79991234567", "", ""); err == nil {
+		t.Fatal("failed send returned no error")
+	}
+
+	// неудачная отправка не включает запрет повторной
+	sender.err = nil
+	if _, err := s.RequestPhoneCode(ctx, "+79991234567", "", ""); err != nil {
+		t.Fatalf("retry after failed send: %v", err)
+	}
+
+	_, err := s.RequestPhoneCode(ctx, "+79991234567", "", "")
+	var lockout *LockoutError
+	if !errors.As(err, &lockout) || lockout.RetryAfter <= 0 {
+		t.Fatalf("err = %v, want LockoutError", err)
+	}
+}
+
+func TestCheckOTP(t *testing.T) {
+	const number = "+79991234567"
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		guesses []string // "" — верный код
+		want    []error
+	}{
+		{name: "right code", guesses: []string{""}, want: []error{nil}},
+		{name: "single use", guesses: []string{"", ""}, want: []error{nil, ErrInvalidOTP}},
+		{name: "wrong code", guesses: []string{"000000", ""}, want: []error{ErrInvalidOTP, nil}},
+		{
+			name:    "attempts exhausted",
+			guesses: []string{"000000", "000000", "000000", ""},
+			want:    []error{ErrInvalidOTP, ErrInvalidOTP, ErrOTPAttemptsExceeded, ErrInvalidOTP},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSMS{}
+			s := newTestPhoneAuth(t, sender)
+			if _, err := s.RequestPhoneCode(ctx, number, "", ""); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, guess := range tt.guesses {
+				if guess == "" {
+					guess = sender.code
+				} else if guess == sender.code {
+					guess = "111111"
+				}
+				if err := s.checkOTP(ctx, number, guess); !errors.Is(err, tt.want[i]) {
+					t.Fatalf("guess %d: err = %v, want %v", i, err, tt.want[i])
+				}
+			}
+		})
+	}
+}
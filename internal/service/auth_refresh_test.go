@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/model"
+	"corpord-api/pkg/dbx"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type failingRefreshRepo struct {
+	pg.RefreshTokenRepository
+	err error
+}
+
+func (r failingRefreshRepo) FindByHash(context.Context, string) (*model.RefreshSession, error) {
+	return nil, r.err
+}
+
+func TestRefreshKeepsDatabaseErrors(t *testing.T) {
+	serialization := &pgconn.PgError{Code: "40001"}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"unknown token", pg.ErrRefreshTokenNotFound, ErrInvalidRefreshToken},
+		{"serialization failure", serialization, serialization},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &auth{logger: testLogger(), refreshRepo: failingRefreshRepo{err: tt.err}}
+
+			_, err := s.Refresh(context.Background(), "raw", "ua", "127.0.0.1")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Refresh err = %v, want %v", err, tt.want)
+			}
+			if err := s.Logout(context.Background(), "raw"); !errors.Is(err, tt.want) {
+				t.Fatalf("Logout err = %v, want %v", err, tt.want)
+			}
+			if tt.want == serialization && !dbx.IsRetryable(err) {
+				t.Fatal("serialization failure is not retryable")
+			}
+		})
+	}
+}
@@ -3,10 +3,14 @@ package service
 import (
 	"context"
 	"corpord-api/internal/repository/pg"
+	"corpord-api/internal/repository/rd"
 	"corpord-api/internal/sso"
 	"corpord-api/model"
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,38 +22,214 @@ import (
 // 4) финализирует — выдаёт токены
 func (s *auth) SSOLogin(
 	ctx context.Context,
-	providerName, providerCodeOrID, email, name, userAgent, ip string,
+	providerName, providerCodeOrID string,
+	params sso.AuthParams,
+	email, name, userAgent, ip string,
 ) (*model.TokenPair, error) {
 
-	// 1) получить провайдера из реестра
+	// 1-3) провайдер, exchange и user info
+	info, err := s.fetchUserInfo(ctx, providerName, providerCodeOrID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4) найти или создать пользователя + identity
+	u, err := s.findOrCreateUserFromSSO(ctx, info, email, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 5) финализировать: пометить provider и providerID в user (для claims) и выдать токены
+	return s.finalizeSSOLogin(ctx, u, info.Provider, info.ProviderID, userAgent, ip)
+}
+
+// fetchUserInfo получает провайдера из реестра, обменивает код на токен и запрашивает пользователя
+func (s *auth) fetchUserInfo(ctx context.Context, providerName, code string, params sso.AuthParams) (*sso.UserInfo, error) {
 	p, err := s.sso.Get(providerName)
 	if err != nil {
 		return nil, ErrProviderNotSupported
 	}
 
-	// 2) exchange (code -> token). В зависимости от реализации провайдера
-	// providerCodeOrID обычно — auth code из callback.
-	tok, err := p.Exchange(ctx, providerCodeOrID)
+	// exchange (code -> token). В зависимости от реализации провайдера
+	// code обычно — auth code из callback.
+	tok, err := p.Exchange(ctx, code, params)
 	if err != nil {
 		return nil, fmt.Errorf("exchange failed: %w", err)
 	}
 
-	// 3) получить user info
-	info, err := p.GetUserInfo(ctx, tok)
+	info, err := p.GetUserInfo(ctx, tok, params)
 	if err != nil {
 		return nil, fmt.Errorf("get userinfo failed: %w", err)
 	}
+	return info, nil
+}
 
-	// 4) найти или создать пользователя + identity
-	u, err := s.findOrCreateUserFromSSO(ctx, info, email, name)
+// BeginSSO начинает вход через провайдера: сохраняет state, nonce, PKCE verifier
+// и адрес возврата в Redis и возвращает URL авторизации у провайдера.
+// binding кладётся в cookie браузера: без него callback с этим state не примется,
+// поэтому ссылку на чужой вход нельзя подсунуть другому пользователю
+func (s *auth) BeginSSO(ctx context.Context, providerName, returnTo string) (string, string, error) {
+	return s.beginSSO(ctx, providerName, returnTo, 0)
+}
+
+// BeginLink начинает привязку провайдера к аккаунту вошедшего пользователя.
+// Дальше поток идёт через тот же callback, что и вход
+func (s *auth) BeginLink(ctx context.Context, userID int, providerName, returnTo string) (string, string, error) {
+	return s.beginSSO(ctx, providerName, returnTo, userID)
+}
+
+func (s *auth) beginSSO(ctx context.Context, providerName, returnTo string, linkUserID int) (string, string, error) {
+	p, err := s.sso.Get(providerName)
+	if err != nil {
+		return "", "", ErrProviderNotSupported
+	}
+
+	params, err := sso.NewAuthParams()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate auth params: %w", err)
+	}
+	binding, err := newOneTimeToken()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate state binding: %w", err)
+	}
+
+	state := &model.OAuthState{
+		Provider:     providerName,
+		Nonce:        params.Nonce,
+		CodeVerifier: params.CodeVerifier,
+		ReturnURL:    s.returnURL(returnTo),
+		LinkUserID:   linkUserID,
+		BindingHash:  hashToken(binding),
+		CreatedAt:    time.Now(),
+	}
+	if err := s.states.Save(ctx, params.State, state, s.ssoCfg.StateTTL); err != nil {
+		return "", "", fmt.Errorf("failed to save oauth state: %w", err)
+	}
+
+	return p.AuthURL(params), binding, nil
+}
+
+// CompleteSSO обрабатывает callback: state проверяется вместе с binding из cookie браузера
+// и удаляется (одноразовый), затем выполняется обмен кода с сохранёнными nonce и PKCE verifier.
+// Возвращает токены и адрес фронтенда, куда нужно вернуть пользователя.
+// В режиме привязки токены не выдаются (nil): пользователь уже вошёл, и его сессия
+// должна принадлежать тому, кто начал привязку, иначе чужой identity попадёт в аккаунт атакующего
+func (s *auth) CompleteSSO(
+	ctx context.Context,
+	providerName string,
+	cb sso.Callback,
+	binding, session, userAgent, ip string,
+) (*model.TokenPair, string, error) {
+	if cb.State == "" || binding == "" {
+		return nil, "", ErrInvalidOAuthState
+	}
+
+	saved, err := s.states.Consume(ctx, cb.State, hashToken(binding))
+	if err != nil {
+		if errors.Is(err, rd.ErrOAuthStateNotFound) {
+			return nil, "", ErrInvalidOAuthState
+		}
+		return nil, "", err
+	}
+	if saved.Provider != providerName {
+		return nil, "", ErrInvalidOAuthState
+	}
+
+	params := sso.AuthParams{
+		State:        cb.State,
+		Nonce:        saved.Nonce,
+		CodeVerifier: saved.CodeVerifier,
+		DeviceID:     cb.DeviceID,
+	}
+
+	if saved.LinkUserID != 0 {
+		userID, err := s.sessionUserID(ctx, session)
+		if err != nil || userID != saved.LinkUserID {
+			s.logger.Warnf("sso link for user %d completed without their session", saved.LinkUserID)
+			return nil, "", ErrInvalidOAuthState
+		}
+
+		info, err := s.fetchUserInfo(ctx, providerName, cb.Code, params)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := s.linkIdentity(ctx, saved.LinkUserID, info); err != nil {
+			return nil, "", err
+		}
+		return nil, saved.ReturnURL, nil
+	}
+
+	tokens, err := s.SSOLogin(ctx, providerName, cb.Code, params, "", "", userAgent, ip)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return tokens, saved.ReturnURL, nil
+}
+
+// sessionUserID возвращает владельца действующей refresh-сессии, не продлевая её
+func (s *auth) sessionUserID(ctx context.Context, rawRefreshToken string) (int, error) {
+	if rawRefreshToken == "" {
+		return 0, ErrInvalidRefreshToken
+	}
+
+	session, err := s.refreshRepo.FindByHash(ctx, hashToken(rawRefreshToken))
+	if err != nil {
+		return 0, mapRefreshTokenError(err)
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return 0, ErrRefreshTokenExpired
+	}
+	return session.UserID, nil
+}
+
+// TelegramLogin вход через Telegram Login Widget. Вместо обмена кода
+// проверяется подпись данных виджета, дальше — та же модель identity, что и у SSO
+func (s *auth) TelegramLogin(ctx context.Context, data sso.TelegramAuthData, userAgent, ip string) (*model.TokenPair, error) {
+	v, err := s.sso.Telegram()
+	if err != nil {
+		return nil, ErrProviderNotSupported
+	}
+
+	info, err := v.Verify(data)
+	if err != nil {
+		s.logger.Warnf("telegram auth rejected for id %d: %v", data.ID, err)
+		return nil, ErrInvalidTelegramAuth
+	}
+
+	u, err := s.findOrCreateUserFromSSO(ctx, info, "", "")
 	if err != nil {
 		return nil, err
 	}
 
-	// 5) финализировать: пометить provider и providerID в user (для claims) и выдать токены
 	return s.finalizeSSOLogin(ctx, u, info.Provider, info.ProviderID, userAgent, ip)
 }
 
+// returnURL возвращает returnTo, если его origin разрешён, иначе адрес фронтенда по умолчанию
+func (s *auth) returnURL(returnTo string) string {
+	if returnTo == "" {
+		return s.ssoCfg.FrontendRedirectURL
+	}
+
+	u, err := url.Parse(returnTo)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return s.ssoCfg.FrontendRedirectURL
+	}
+
+	origin := u.Scheme + "://" + u.Host
+	if def, err := url.Parse(s.ssoCfg.FrontendRedirectURL); err == nil && origin == def.Scheme+"://"+def.Host {
+		return returnTo
+	}
+	for _, allowed := range s.ssoCfg.AllowedRedirectOrigins {
+		if origin == strings.TrimSuffix(allowed, "/") {
+			return returnTo
+		}
+	}
+
+	s.logger.Warnf("sso: return url %q is not allowed", returnTo)
+	return s.ssoCfg.FrontendRedirectURL
+}
+
 // findOrCreateUserFromSSO ищет user по identity.provider+provider_id,
 // если не находит — пытается найти по email и привязать identity,
 // если и по email нет — создаёт нового пользователя и identity.
@@ -89,20 +269,14 @@ func (s *auth) findOrCreateUserFromSSO(
 			return nil, fmt.Errorf("failed to lookup user by email: %w", err)
 		}
 		if u != nil {
-			// пользователь найден по email → привязать identity
-			uid, err := uuid.NewV7()
-			if err != nil {
-				s.logger.Errorf("failed to generate uuid: %v", err)
-				return nil, fmt.Errorf("failed to generate uuid: %w", err)
-			}
-			newIdentity := &model.UserIdentity{
-				ID:         uid,
-				UserID:     u.ID,
-				Provider:   info.Provider,
-				ProviderID: info.ProviderID,
+			// Автопривязка по email допустима, только если провайдер подтвердил email
+			// и она разрешена в конфиге — иначе это захват чужого аккаунта.
+			// В остальных случаях пользователь входит сам и привязывает провайдера явно
+			if !s.ssoCfg.AutoLinkVerifiedEmail || !info.EmailVerified || info.Email == "" {
+				return nil, ErrSSOEmailTaken
 			}
-			if err := s.userIdentity.AddIdentity(ctx, newIdentity); err != nil {
-				return nil, fmt.Errorf("failed to add identity: %w", err)
+			if err := s.addIdentity(ctx, u.ID, info); err != nil {
+				return nil, err
 			}
 			return u, nil
 		}
@@ -120,25 +294,18 @@ func (s *auth) findOrCreateUserFromSSO(
 		Password: nil, // для SSO ставим пустой пароль; в БД password_hash может быть NULL
 	}
 
-	uid, err := s.authRepo.CreateUser(ctx, userCreate)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
-	}
-
-	// создать identity для нового пользователя
-	id, err := uuid.NewV7()
+	// пользователь и identity создаются в одной транзакции: без identity
+	// повторный вход не найдёт пользователя и упрётся в занятый email
+	var uid int
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if uid, err = s.authRepo.CreateUser(ctx, userCreate); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		return s.addIdentity(ctx, uid, info)
+	})
 	if err != nil {
-		s.logger.Errorf("failed to generate uuid: %v", err)
-		return nil, fmt.Errorf("failed to generate uuid: %w", err)
-	}
-	newIdentity := &model.UserIdentity{
-		ID:         id,
-		UserID:     uid,
-		Provider:   info.Provider,
-		ProviderID: info.ProviderID,
-	}
-	if err := s.userIdentity.AddIdentity(ctx, newIdentity); err != nil {
-		return nil, fmt.Errorf("failed to add identity for new user: %w", err)
+		return nil, err
 	}
 
 	// вернуть только-что созданного пользователя
@@ -168,3 +335,84 @@ func (s *auth) finalizeSSOLogin(
 	}
 	return tokens, nil
 }
+
+func (s *auth) addIdentity(ctx context.Context, userID int, info *sso.UserInfo) error {
+	id, err := uuid.NewV7()
+	if err != nil {
+		s.logger.Errorf("failed to generate uuid: %v", err)
+		return fmt.Errorf("failed to generate uuid: %w", err)
+	}
+	identity := &model.UserIdentity{
+		ID:         id,
+		UserID:     userID,
+		Provider:   info.Provider,
+		ProviderID: info.ProviderID,
+	}
+	if err := s.userIdentity.AddIdentity(ctx, identity); err != nil {
+		return fmt.Errorf("failed to add identity: %w", err)
+	}
+	return nil
+}
+
+// linkIdentity привязывает внешний аккаунт к пользователю, если он не привязан к кому-то ещё
+func (s *auth) linkIdentity(ctx context.Context, userID int, info *sso.UserInfo) error {
+	existing, err := s.userIdentity.GetProviderByID(ctx, info.Provider, info.ProviderID)
+	if err != nil && !errors.Is(err, pg.ErrIdentityNotFound) {
+		return fmt.Errorf("failed to lookup identity: %w", err)
+	}
+	if existing != nil {
+		if existing.UserID == userID {
+			return nil
+		}
+		return ErrIdentityLinked
+	}
+
+	// один аккаунт провайдера на пользователя
+	_, err = s.userIdentity.GetByProvider(ctx, userID, info.Provider)
+	if err == nil {
+		return ErrIdentityLinked
+	}
+	if !errors.Is(err, pg.ErrIdentityNotFound) {
+		return fmt.Errorf("failed to lookup identity: %w", err)
+	}
+
+	return s.addIdentity(ctx, userID, info)
+}
+
+// Identities возвращает привязанные к пользователю внешние аккаунты
+func (s *auth) Identities(ctx context.Context, userID int) ([]*model.UserIdentity, error) {
+	return s.userIdentity.GetByUserID(ctx, userID)
+}
+
+// Unlink отвязывает внешний аккаунт. Последний способ входа (нет пароля,
+// подтверждённого телефона и это единственная identity) удалить нельзя
+func (s *auth) Unlink(ctx context.Context, userID int, identityID string) error {
+	identities, err := s.userIdentity.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, identity := range identities {
+		if identity.ID.String() == identityID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrIdentityNotFound
+	}
+
+	u, err := s.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrUserNotFound
+	}
+	if u.PasswordHash == nil && !u.PhoneVerified && len(identities) == 1 {
+		return ErrLastLoginMethod
+	}
+
+	return s.userIdentity.RemoveIdentity(ctx, identityID)
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"corpord-api/internal/repository/pg"
+	"errors"
+)
+
+// validateEach проверяет все элементы пакетной операции до записи.
+// Возвращает ошибки всех некорректных элементов вместе с их индексами
+func validateEach[T interface{ Validate() error }](items []T) error {
+	var errs []error
+	for i, item := range items {
+		if err := item.Validate(); err != nil {
+			errs = append(errs, &pg.ItemError{Index: i, Err: invalid(err)})
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -4,15 +4,23 @@ import (
 	"context"
 	"corpord-api/internal/logger"
 	"corpord-api/internal/repository/pg"
+	"corpord-api/internal/validation"
 	"corpord-api/model"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/mergepatch"
+	"corpord-api/pkg/paging"
 )
 
 type Bus interface {
-	CreateBus(ctx context.Context, bus model.Bus) error
+	CreateBus(ctx context.Context, bus model.BusCreate) error
 	GetBus(ctx context.Context, id int) (*model.ViewBus, error)
-	GetAllBuses(ctx context.Context) ([]model.ViewBus, error)
+	GetAllBuses(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[model.ViewBus], error)
+	CreateMany(ctx context.Context, buses []model.BusCreate) ([]int, error)
 	UpdateBus(ctx context.Context, bus model.BusUpdate) error
+	UpdateMany(ctx context.Context, buses []*model.BusUpdate) error
+	Patch(ctx context.Context, id int, patch []byte, match Precondition) (*model.BusPatch, error)
 	DeleteBus(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type bus struct {
@@ -27,7 +35,8 @@ func NewBus(logger *logger.Logger, repo pg.BusRepository) Bus {
 	}
 }
 
-func (b *bus) CreateBus(ctx context.Context, bus model.Bus) error {
+func (b *bus) CreateBus(ctx context.Context, bus model.BusCreate) error {
+	bus.LicensePlate = validation.NormalizePlate(bus.LicensePlate)
 	return b.repo.CreateBus(ctx, bus)
 }
 
@@ -35,17 +44,76 @@ func (b *bus) GetBus(ctx context.Context, id int) (*model.ViewBus, error) {
 	return b.repo.GetBus(ctx, id)
 }
 
-func (b *bus) GetAllBuses(ctx context.Context) ([]model.ViewBus, error) {
-	return b.repo.GetAllBuses(ctx)
+func (b *bus) GetAllBuses(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[model.ViewBus], error) {
+	return b.repo.GetAllBuses(ctx, f, p)
 }
 
 func (b *bus) UpdateBus(ctx context.Context, bus model.BusUpdate) error {
 	if bus.Validate() != nil {
 		return ErrNoFields
 	}
-	return b.repo.UpdateBus(ctx, &bus)
+	if bus.LicensePlate != nil {
+		plate := validation.NormalizePlate(*bus.LicensePlate)
+		bus.LicensePlate = &plate
+	}
+	return mapNotFound(b.repo.UpdateBus(ctx, &bus), ErrBusNotFound)
+}
+
+// CreateMany создаёт автобусы в одной транзакции: если не удался хотя бы один, не создаётся ни одного
+func (b *bus) CreateMany(ctx context.Context, buses []model.BusCreate) ([]int, error) {
+	for i := range buses {
+		buses[i].LicensePlate = validation.NormalizePlate(buses[i].LicensePlate)
+	}
+	return b.repo.CreateMany(ctx, buses)
+}
+
+// UpdateMany проверяет все автобусы и обновляет их в одной транзакции
+func (b *bus) UpdateMany(ctx context.Context, buses []*model.BusUpdate) error {
+	if err := validateEach(buses); err != nil {
+		return err
+	}
+	for _, bus := range buses {
+		if bus.LicensePlate != nil {
+			plate := validation.NormalizePlate(*bus.LicensePlate)
+			bus.LicensePlate = &plate
+		}
+	}
+	return mapNotFound(b.repo.UpdateMany(ctx, buses), ErrBusNotFound)
+}
+
+// Patch применяет к автобусу JSON Merge Patch. Результат проверяется теми же правилами,
+// что и тело PUT, и сохраняется, только если автобус не изменили параллельно
+func (b *bus) Patch(ctx context.Context, id int, patch []byte, match Precondition) (*model.BusPatch, error) {
+	current, err := b.repo.ForPatch(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, ErrBusNotFound)
+	}
+	if err := checkVersion(match, current.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	next := *current
+	if err := mergepatch.Into(&next, patch); err != nil {
+		return nil, err
+	}
+	next.ID, next.UpdatedAt = current.ID, current.UpdatedAt
+	next.LicensePlate = validation.NormalizePlate(next.LicensePlate)
+	if err := validation.Struct(&next); err != nil {
+		return nil, err
+	}
+
+	next.UpdatedAt, err = b.repo.Patch(ctx, &next)
+	if err != nil {
+		return nil, patchError(err)
+	}
+	return &next, nil
 }
 
 func (b *bus) DeleteBus(ctx context.Context, id int) error {
-	return b.repo.DeleteBus(ctx, id)
+	return mapNotFound(b.repo.DeleteBus(ctx, id), ErrBusNotFound)
+}
+
+// Restore восстанавливает удалённый автобус
+func (b *bus) Restore(ctx context.Context, id int) error {
+	return mapNotFound(b.repo.Restore(ctx, id), ErrBusNotFound)
 }
@@ -13,6 +13,7 @@ type BusCategory interface {
 	Create(ctx context.Context, category model.BusCategory) error
 	Update(ctx context.Context, category model.BusCategory) (model.BusCategory, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type busCategory struct {
@@ -36,16 +37,24 @@ func (b *busCategory) GetById(ctx context.Context, id int) (*model.BusCategory,
 }
 
 func (b *busCategory) Create(ctx context.Context, category model.BusCategory) error {
+	if err := category.Validate(); err != nil {
+		return invalid(err)
+	}
 	return b.repo.Create(ctx, category)
 }
 
 func (b *busCategory) Update(ctx context.Context, category model.BusCategory) (model.BusCategory, error) {
 	if err := category.Validate(); err != nil {
-		return model.BusCategory{}, err
+		return model.BusCategory{}, invalid(err)
 	}
 	return b.repo.Update(ctx, category)
 }
 
 func (b *busCategory) Delete(ctx context.Context, id int) error {
-	return b.repo.Delete(ctx, id)
+	return mapNotFound(b.repo.Delete(ctx, id), ErrBusCategoryNotFound)
+}
+
+// Restore восстанавливает удалённую категорию автобуса
+func (b *busCategory) Restore(ctx context.Context, id int) error {
+	return mapNotFound(b.repo.Restore(ctx, id), ErrBusCategoryNotFound)
 }
@@ -13,6 +13,7 @@ type BusStatus interface {
 	Create(ctx context.Context, status model.BusStatus) error
 	Update(ctx context.Context, status model.BusStatus) (model.BusStatus, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type busStatus struct {
@@ -36,16 +37,24 @@ func (b *busStatus) ByID(ctx context.Context, id int) (model.BusStatus, error) {
 }
 
 func (b *busStatus) Create(ctx context.Context, status model.BusStatus) error {
+	if err := status.Validate(); err != nil {
+		return invalid(err)
+	}
 	return b.repo.Create(ctx, status)
 }
 
 func (b *busStatus) Update(ctx context.Context, status model.BusStatus) (model.BusStatus, error) {
-	if status.Validate() != nil {
-		return model.BusStatus{}, ErrBusStatusExists
+	if err := status.Validate(); err != nil {
+		return model.BusStatus{}, invalid(err)
 	}
 	return b.repo.Update(ctx, status)
 }
 
 func (b *busStatus) Delete(ctx context.Context, id int) error {
-	return b.repo.Delete(ctx, id)
+	return mapNotFound(b.repo.Delete(ctx, id), ErrBusStatusNotFound)
+}
+
+// Restore восстанавливает удалённый статус автобуса
+func (b *busStatus) Restore(ctx context.Context, id int) error {
+	return mapNotFound(b.repo.Restore(ctx, id), ErrBusStatusNotFound)
 }
@@ -4,15 +4,20 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/internal/repository/pg"
 	"corpord-api/model"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
 	"golang.org/x/net/context"
 )
 
 type Driver interface {
-	All(ctx context.Context) ([]model.DriverOutput, error)
+	All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[model.DriverOutput], error)
 	ByID(ctx context.Context, id int) (model.DriverOutput, error)
 	Create(ctx context.Context, driver model.DriverInput) error
+	CreateMany(ctx context.Context, drivers []model.DriverInput) ([]int, error)
 	Update(ctx context.Context, driver model.DriverInput) error
+	UpdateMany(ctx context.Context, drivers []model.DriverInput) error
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type driver struct {
@@ -27,8 +32,8 @@ func NewDriver(logger *logger.Logger, repo pg.Driver) Driver {
 	}
 }
 
-func (d *driver) All(ctx context.Context) ([]model.DriverOutput, error) {
-	return d.repo.All(ctx)
+func (d *driver) All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[model.DriverOutput], error) {
+	return d.repo.All(ctx, f, p)
 }
 
 func (d *driver) ByID(ctx context.Context, id int) (model.DriverOutput, error) {
@@ -40,9 +45,24 @@ func (d *driver) Create(ctx context.Context, driver model.DriverInput) error {
 }
 
 func (d *driver) Update(ctx context.Context, driver model.DriverInput) error {
-	return d.repo.Update(ctx, driver)
+	return mapNotFound(d.repo.Update(ctx, driver), ErrDriverNotFound)
+}
+
+// CreateMany создаёт водителей в одной транзакции: если не удался хотя бы один, не создаётся ни одного
+func (d *driver) CreateMany(ctx context.Context, drivers []model.DriverInput) ([]int, error) {
+	return d.repo.CreateMany(ctx, drivers)
+}
+
+// UpdateMany обновляет водителей в одной транзакции
+func (d *driver) UpdateMany(ctx context.Context, drivers []model.DriverInput) error {
+	return mapNotFound(d.repo.UpdateMany(ctx, drivers), ErrDriverNotFound)
 }
 
 func (d *driver) Delete(ctx context.Context, id int) error {
-	return d.repo.Delete(ctx, id)
+	return mapNotFound(d.repo.Delete(ctx, id), ErrDriverNotFound)
+}
+
+// Restore восстанавливает удалённого водителя
+func (d *driver) Restore(ctx context.Context, id int) error {
+	return mapNotFound(d.repo.Restore(ctx, id), ErrDriverNotFound)
 }
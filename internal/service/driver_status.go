@@ -13,6 +13,7 @@ type DriverStatus interface {
 	Create(ctx context.Context, status *model.DriverStatus) error
 	Update(ctx context.Context, status *model.DriverStatus) error
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type driverStatus struct {
@@ -36,16 +37,24 @@ func (d *driverStatus) ById(ctx context.Context, id int) (model.DriverStatus, er
 }
 
 func (d *driverStatus) Create(ctx context.Context, status *model.DriverStatus) error {
+	if err := status.Validate(); err != nil {
+		return invalid(err)
+	}
 	return d.repo.Create(ctx, status)
 }
 
 func (d *driverStatus) Update(ctx context.Context, status *model.DriverStatus) error {
 	if err := status.Validate(); err != nil {
-		return err
+		return invalid(err)
 	}
 	return d.repo.Update(ctx, status)
 }
 
 func (d *driverStatus) Delete(ctx context.Context, id int) error {
-	return d.repo.Delete(ctx, id)
+	return mapNotFound(d.repo.Delete(ctx, id), ErrDriverStatusNotFound)
+}
+
+// Restore восстанавливает удалённый статус водителя
+func (d *driverStatus) Restore(ctx context.Context, id int) error {
+	return mapNotFound(d.repo.Restore(ctx, id), ErrDriverStatusNotFound)
 }
@@ -1,6 +1,10 @@
 package service
 
-import "errors"
+import (
+	"corpord-api/internal/repository/pg"
+	"errors"
+	"time"
+)
 
 var (
 	ErrNoFields             = errors.New("no fields")
@@ -17,4 +21,91 @@ var (
 	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
 	ErrRefreshTokenExpired  = errors.New("refresh token expired")
 	ErrProviderNotSupported = errors.New("provider not supported")
+	ErrInvalidUserToken     = errors.New("invalid or expired token")
+	ErrTooManyAttempts      = errors.New("too many login attempts")
+	ErrRoleNotFound         = errors.New("role not found")
+	ErrRoleExists           = errors.New("role already exists")
+	ErrUnknownPermission    = errors.New("unknown permission")
+	ErrInvalidOAuthState    = errors.New("invalid or expired oauth state")
+	ErrSSOEmailTaken        = errors.New("account with this email already exists, sign in and link the provider")
+	ErrIdentityLinked       = errors.New("identity already linked")
+	ErrIdentityNotFound     = errors.New("identity not found")
+	ErrLastLoginMethod      = errors.New("cannot remove the last login method")
+	ErrInvalidTelegramAuth  = errors.New("invalid telegram auth data")
+	ErrInvalidPhone         = errors.New("invalid phone number")
+	ErrInvalidOTP           = errors.New("invalid or expired code")
+	ErrOTPAttemptsExceeded  = errors.New("too many invalid codes, request a new one")
+	ErrInvalidAPIKey        = errors.New("invalid api key")
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrInvalidExpiry        = errors.New("expiry must be in the future")
+	ErrPhoneTaken           = errors.New("phone is already used by another account")
+	ErrPassengerNotFound    = errors.New("passenger not found")
+	ErrInvalidDocument      = errors.New("invalid document number")
+	ErrInvalidBirthDate     = errors.New("invalid birth date")
+	ErrErasureNotRequested  = errors.New("erasure was not requested")
+	ErrDriverNotFound       = errors.New("driver not found")
+	ErrDriverStatusNotFound = errors.New("driver status not found")
+	ErrStopNotFound         = errors.New("stop not found")
+	ErrTripStopNotFound     = errors.New("trip stop not found")
+	ErrTripNotFound         = errors.New("trip not found")
+	ErrVersionConflict      = errors.New("resource was modified by another request")
+	ErrUserBlocked          = errors.New("user is blocked")
+	ErrCannotImpersonate    = errors.New("impersonation of this user is not allowed")
+	ErrSelfAction           = errors.New("action is not allowed on own account")
+	ErrPrivilegeEscalation  = errors.New("target has permissions the caller does not have")
+	ErrAPIKeyTarget         = errors.New("api keys can only be issued for yourself or a service account")
 )
+
+// LockoutError возвращается, пока вход временно заблокирован после серии неудачных попыток
+// или повторная отправка кода ещё недоступна
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LockoutError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// mapNotFound заменяет pg.ErrNotFound на ошибку сервиса target, остальные ошибки возвращает как есть.
+// У ошибки элемента пакетной операции сохраняется его индекс
+func mapNotFound(err, target error) error {
+	if !errors.Is(err, pg.ErrNotFound) {
+		return err
+	}
+	// ошибки пакетной операции заменяются по отдельности, остальные сохраняются
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		mapped := make([]error, len(errs))
+		for i, e := range errs {
+			mapped[i] = mapNotFound(e, target)
+		}
+		return errors.Join(mapped...)
+	}
+	var item *pg.ItemError
+	if errors.As(err, &item) {
+		return &pg.ItemError{Index: item.Index, Err: target}
+	}
+	return target
+}
+
+// ValidationError некорректные входные данные, найденные сервисом. Текст ошибки можно показать клиенту
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// invalid помечает ошибку проверки модели как ошибку входных данных
+func invalid(err error) error {
+	return &ValidationError{Err: err}
+}
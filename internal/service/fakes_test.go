@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/internal/validation"
+	"corpord-api/model"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	// сервисы проверяют документы после merge patch тем же валидатором, что и gin
+	validation.Register()
+	os.Exit(m.Run())
+}
+
+func testLogger() *logger.Logger {
+	return &logger.Logger{SugaredLogger: zap.NewNop().Sugar()}
+}
+
+// testRolePermissions права ролей для тестов: у admin есть всё, support — часть прав admin
+var testRolePermissions = map[string][]string{
+	model.RoleAdmin: {model.PermAPIKeysManage, model.PermUsersManage, model.PermUsersImpersonate, model.PermBusesWrite},
+	"support":       {model.PermAPIKeysManage, model.PermUsersImpersonate, model.PermBusesWrite},
+	"fleet":         {model.PermBusesWrite},
+}
+
+type fakeRoleRepo struct {
+	pg.Role
+	roles map[int]*model.Role
+}
+
+func newFakeRoleRepo() *fakeRoleRepo {
+	return &fakeRoleRepo{roles: map[int]*model.Role{
+		1: {ID: 1, Name: model.RoleAdmin},
+		2: {ID: 2, Name: "support"},
+		3: {ID: 3, Name: "fleet"},
+	}}
+}
+
+func (r *fakeRoleRepo) ByID(_ context.Context, id int) (*model.Role, error) {
+	role, ok := r.roles[id]
+	if !ok {
+		return nil, pg.ErrNotFound
+	}
+	return role, nil
+}
+
+func (r *fakeRoleRepo) PermissionsByRole(_ context.Context, roleName string) ([]string, error) {
+	return testRolePermissions[roleName], nil
+}
+
+type fakeAuthRepo struct {
+	pg.AuthRepository
+	users     map[int]*model.UserDB
+	created   []int
+	blocked   []int
+	unblocked []int
+}
+
+func (r *fakeAuthRepo) GetUserByID(_ context.Context, id int) (*model.UserDB, error) {
+	return r.users[id], nil
+}
+
+func (r *fakeAuthRepo) GetUserByEmail(_ context.Context, email string) (*model.UserDB, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAuthRepo) CreateServiceAccount(_ context.Context, name string, roleID int) (int, error) {
+	id := 100 + len(r.created)
+	r.created = append(r.created, id)
+	r.users[id] = &model.UserDB{ID: id, Name: name, IsService: true}
+	return id, nil
+}
+
+func (r *fakeAuthRepo) SetBlocked(_ context.Context, id int, blocked bool, _ *string) error {
+	if blocked {
+		r.blocked = append(r.blocked, id)
+	} else {
+		r.unblocked = append(r.unblocked, id)
+	}
+	return nil
+}
+
+type fakeUserRepo struct {
+	pg.UserRepository
+	users   map[int]*model.UserResponse
+	deleted []int
+}
+
+func (r *fakeUserRepo) GetByID(_ context.Context, id int) (*model.UserResponse, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, pg.ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *fakeUserRepo) Delete(_ context.Context, id int) error {
+	if _, ok := r.users[id]; !ok {
+		return pg.ErrNotFound
+	}
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+// fakeTripRepo хранит рейсы и версии как trips.updated_at: каждое изменение
+// сдвигает версию, изменение по устаревшей версии — ErrVersionConflict
+type fakeTripRepo struct {
+	pg.Trip
+	trips   map[int]*model.TripPatch
+	updates []*model.TripUpdate
+}
+
+func (r *fakeTripRepo) ForPatch(_ context.Context, id int) (*model.TripPatch, error) {
+	trip, ok := r.trips[id]
+	if !ok {
+		return nil, pg.ErrNotFound
+	}
+	copied := *trip
+	return &copied, nil
+}
+
+func (r *fakeTripRepo) Update(_ context.Context, trip *model.TripUpdate, version time.Time) (time.Time, error) {
+	current, ok := r.trips[trip.ID]
+	if !ok || !current.UpdatedAt.Equal(version) {
+		return time.Time{}, pg.ErrVersionConflict
+	}
+	r.updates = append(r.updates, trip)
+	current.UpdatedAt = current.UpdatedAt.Add(time.Second)
+	return current.UpdatedAt, nil
+}
+
+func (r *fakeTripRepo) Patch(_ context.Context, trip *model.TripPatch) (time.Time, error) {
+	current, ok := r.trips[trip.ID]
+	if !ok || !current.UpdatedAt.Equal(trip.UpdatedAt) {
+		return time.Time{}, pg.ErrVersionConflict
+	}
+	*current = *trip
+	current.UpdatedAt = current.UpdatedAt.Add(time.Second)
+	return current.UpdatedAt, nil
+}
+
+// fakeTripStopRepo возвращает заданную ошибку пакетного обновления
+type fakeTripStopRepo struct {
+	pg.TripStop
+	err error
+}
+
+func (r *fakeTripStopRepo) UpdateMany(context.Context, []*model.TripStopUpdate) error {
+	return r.err
+}
+
+type fakeAPIKeyRepo struct {
+	pg.APIKeyRepository
+	keys map[uuid.UUID]*model.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Create(_ context.Context, key *model.APIKey) error {
+	r.keys[key.ID] = key
+	return nil
+}
+
+type fakeUserTokenRepo struct {
+	pg.UserTokenRepository
+	saved []*model.UserToken
+}
+
+func (r *fakeUserTokenRepo) InvalidateByUser(context.Context, int, string) error {
+	return nil
+}
+
+func (r *fakeUserTokenRepo) Save(_ context.Context, token *model.UserToken) error {
+	r.saved = append(r.saved, token)
+	return nil
+}
+
+type fakeRefreshRepo struct {
+	pg.RefreshTokenRepository
+}
+
+func (fakeRefreshRepo) RevokeAllByUser(context.Context, int) error {
+	return nil
+}
+
+type fakeEvents struct{}
+
+func (fakeEvents) Save(context.Context, *model.SecurityEvent) error {
+	return nil
+}
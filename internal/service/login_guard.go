@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/repository/rd"
+	"strings"
+	"time"
+)
+
+// loginGuard ограничивает число неудачных попыток входа по email и по IP.
+// После cfg.MaxAttempts неудач вход блокируется на cfg.Lockout, каждая следующая
+// неудача удваивает блокировку вплоть до cfg.MaxLockout.
+// Ошибки Redis не мешают входу: защита деградирует, а не ломает аутентификацию.
+type loginGuard struct {
+	logger   *logger.Logger
+	cfg      *config.LoginProtection
+	attempts rd.LoginAttemptRepository
+}
+
+func newLoginGuard(logger *logger.Logger, cfg *config.LoginProtection, attempts rd.LoginAttemptRepository) *loginGuard {
+	return &loginGuard{
+		logger:   logger,
+		cfg:      cfg,
+		attempts: attempts,
+	}
+}
+
+func emailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// check возвращает *LockoutError, если вход для email или IP заблокирован
+func (g *loginGuard) check(ctx context.Context, email, ip string) error {
+	var retry time.Duration
+	for _, key := range []string{emailKey(email), ipKey(ip)} {
+		ttl, err := g.attempts.LockedFor(ctx, key)
+		if err != nil {
+			g.logger.Warnf("login guard: lock check failed for %s: %v", key, err)
+			continue
+		}
+		if ttl > retry {
+			retry = ttl
+		}
+	}
+
+	if retry > 0 {
+		return &LockoutError{RetryAfter: retry}
+	}
+	return nil
+}
+
+// fail учитывает неудачную попытку и при превышении лимита ставит блокировку
+func (g *loginGuard) fail(ctx context.Context, email, ip string) {
+	g.register(ctx, emailKey(email), g.cfg.MaxAttempts)
+	g.register(ctx, ipKey(ip), g.cfg.IPMaxAttempts)
+}
+
+func (g *loginGuard) register(ctx context.Context, key string, limit int) {
+	if limit <= 0 {
+		return
+	}
+
+	window := g.cfg.Window
+	if window < g.cfg.MaxLockout {
+		// счётчик должен пережить блокировку, иначе backoff не будет расти
+		window = g.cfg.MaxLockout
+	}
+
+	count, err := g.attempts.Fail(ctx, key, window)
+	if err != nil {
+		g.logger.Warnf("login guard: failed to register attempt for %s: %v", key, err)
+		return
+	}
+	if count < int64(limit) {
+		return
+	}
+
+	ttl := g.lockoutFor(count - int64(limit))
+	if err := g.attempts.Lock(ctx, key, ttl); err != nil {
+		g.logger.Warnf("login guard: failed to lock %s: %v", key, err)
+		return
+	}
+	g.logger.Warnf("login guard: %s locked for %v after %d failed attempts", key, ttl, count)
+}
+
+// lockoutFor возвращает длительность блокировки для n-й неудачи сверх лимита
+func (g *loginGuard) lockoutFor(n int64) time.Duration {
+	ttl := g.cfg.Lockout
+	for i := int64(0); i < n && ttl < g.cfg.MaxLockout; i++ {
+		ttl *= 2
+	}
+	if g.cfg.MaxLockout > 0 && ttl > g.cfg.MaxLockout {
+		ttl = g.cfg.MaxLockout
+	}
+	return ttl
+}
+
+// succeed сбрасывает счётчик по email после успешного входа
+func (g *loginGuard) succeed(ctx context.Context, email string) {
+	if err := g.attempts.Reset(ctx, emailKey(email)); err != nil {
+		g.logger.Warnf("login guard: failed to reset attempts for %s: %v", email, err)
+	}
+}
+
+// unlock снимает блокировку с email
+func (g *loginGuard) unlock(ctx context.Context, email string) error {
+	return g.attempts.Reset(ctx, emailKey(email))
+}
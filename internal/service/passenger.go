@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/encryption"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/model"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// documentVisibleChars сколько последних символов номера документа видно в ответах
+const documentVisibleChars = 4
+
+// documentFormats допустимые номера документов после удаления пробелов и дефисов
+var documentFormats = map[string]*regexp.Regexp{
+	model.DocumentPassportRF:       regexp.MustCompile(`^\d{10}$`),                     // серия и номер
+	model.DocumentBirthCertificate: regexp.MustCompile(`^[IVXLC]{1,4}[А-ЯЁ]{2}\d{6}$`), // II-АБ 123456
+	model.DocumentForeignPassport:  regexp.MustCompile(`^[A-Z0-9]{5,20}$`),
+	model.DocumentInternational:    regexp.MustCompile(`^\d{9}$`), // загранпаспорт РФ
+}
+
+type Passenger interface {
+	All(ctx context.Context, userID int) ([]*model.PassengerResponse, error)
+	ByID(ctx context.Context, userID, id int) (*model.PassengerResponse, error)
+	Create(ctx context.Context, userID int, input *model.PassengerCreate) (*model.PassengerResponse, error)
+	Update(ctx context.Context, userID, id int, input *model.PassengerUpdate) (*model.PassengerResponse, error)
+	Delete(ctx context.Context, userID, id int) error
+	// ForCheckout возвращает данные пассажира с расшифрованным документом для заполнения позиции заказа
+	ForCheckout(ctx context.Context, userID, id int) (*model.PassengerDocument, error)
+}
+
+type passenger struct {
+	logger *logger.Logger
+	repo   pg.Passenger
+	cipher *encryption.Cipher
+}
+
+func NewPassenger(logger *logger.Logger, repo pg.Passenger, cipher *encryption.Cipher) Passenger {
+	return &passenger{
+		logger: logger,
+		repo:   repo,
+		cipher: cipher,
+	}
+}
+
+func (s *passenger) All(ctx context.Context, userID int) ([]*model.PassengerResponse, error) {
+	list, err := s.repo.ByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*model.PassengerResponse, 0, len(list))
+	for _, p := range list {
+		resp, err := s.toResponse(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, resp)
+	}
+	return out, nil
+}
+
+func (s *passenger) ByID(ctx context.Context, userID, id int) (*model.PassengerResponse, error) {
+	p, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(p)
+}
+
+func (s *passenger) Create(ctx context.Context, userID int, input *model.PassengerCreate) (*model.PassengerResponse, error) {
+	birthDate, err := parseBirthDate(input.BirthDate)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := normalizeDocument(input.DocumentType, input.DocumentNumber)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.cipher.Encrypt(number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt document: %w", err)
+	}
+
+	p := &model.Passenger{
+		UserID:         userID,
+		FirstName:      strings.TrimSpace(input.FirstName),
+		LastName:       strings.TrimSpace(input.LastName),
+		MiddleName:     input.MiddleName,
+		BirthDate:      birthDate,
+		DocumentType:   input.DocumentType,
+		DocumentNumber: encrypted,
+	}
+
+	id, err := s.repo.Create(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return s.ByID(ctx, userID, id)
+}
+
+func (s *passenger) Update(ctx context.Context, userID, id int, input *model.PassengerUpdate) (*model.PassengerResponse, error) {
+	p, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.FirstName != nil {
+		p.FirstName = strings.TrimSpace(*input.FirstName)
+	}
+	if input.LastName != nil {
+		p.LastName = strings.TrimSpace(*input.LastName)
+	}
+	if input.MiddleName != nil {
+		p.MiddleName = input.MiddleName
+	}
+	if input.BirthDate != nil {
+		if p.BirthDate, err = parseBirthDate(*input.BirthDate); err != nil {
+			return nil, err
+		}
+	}
+
+	// при смене типа документа номер проверяется по новому формату
+	if input.DocumentType != nil || input.DocumentNumber != nil {
+		number, err := s.cipher.Decrypt(p.DocumentNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt document: %w", err)
+		}
+		if input.DocumentType != nil {
+			p.DocumentType = *input.DocumentType
+		}
+		if input.DocumentNumber != nil {
+			number = *input.DocumentNumber
+		}
+
+		if number, err = normalizeDocument(p.DocumentType, number); err != nil {
+			return nil, err
+		}
+		if p.DocumentNumber, err = s.cipher.Encrypt(number); err != nil {
+			return nil, fmt.Errorf("failed to encrypt document: %w", err)
+		}
+	}
+
+	if err := s.repo.Update(ctx, p); err != nil {
+		return nil, mapPassengerError(err)
+	}
+	return s.ByID(ctx, userID, id)
+}
+
+func (s *passenger) Delete(ctx context.Context, userID, id int) error {
+	return mapPassengerError(s.repo.Delete(ctx, userID, id))
+}
+
+func (s *passenger) ForCheckout(ctx context.Context, userID, id int) (*model.PassengerDocument, error) {
+	p, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := s.cipher.Decrypt(p.DocumentNumber)
+	if err != nil {
+		s.logger.Errorf("failed to decrypt document of passenger %d: %v", p.ID, err)
+		return nil, err
+	}
+
+	return &model.PassengerDocument{
+		PassengerID:    p.ID,
+		Name:           p.FullName(),
+		DocumentType:   p.DocumentType,
+		DocumentNumber: number,
+	}, nil
+}
+
+func (s *passenger) get(ctx context.Context, userID, id int) (*model.Passenger, error) {
+	p, err := s.repo.ByID(ctx, userID, id)
+	if err != nil {
+		return nil, mapPassengerError(err)
+	}
+	return p, nil
+}
+
+func (s *passenger) toResponse(p *model.Passenger) (*model.PassengerResponse, error) {
+	number, err := s.cipher.Decrypt(p.DocumentNumber)
+	if err != nil {
+		s.logger.Errorf("failed to decrypt document of passenger %d: %v", p.ID, err)
+		return nil, err
+	}
+
+	return &model.PassengerResponse{
+		ID:             p.ID,
+		FirstName:      p.FirstName,
+		LastName:       p.LastName,
+		MiddleName:     p.MiddleName,
+		BirthDate:      p.BirthDate.Format(model.BirthDateLayout),
+		DocumentType:   p.DocumentType,
+		DocumentNumber: encryption.Mask(number, documentVisibleChars),
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}, nil
+}
+
+func parseBirthDate(value string) (time.Time, error) {
+	date, err := time.Parse(model.BirthDateLayout, value)
+	if err != nil || date.After(time.Now()) || date.Year() < 1900 {
+		return time.Time{}, ErrInvalidBirthDate
+	}
+	return date, nil
+}
+
+// normalizeDocument убирает пробелы и дефисы, приводит к верхнему регистру и проверяет формат
+func normalizeDocument(docType, number string) (string, error) {
+	number = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(number))
+
+	format, ok := documentFormats[docType]
+	if !ok || !format.MatchString(number) {
+		return "", ErrInvalidDocument
+	}
+	return number, nil
+}
+
+func mapPassengerError(err error) error {
+	if errors.Is(err, pg.ErrNotFound) {
+		return ErrPassengerNotFound
+	}
+	return err
+}
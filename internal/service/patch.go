@@ -0,0 +1,28 @@
+package service
+
+import (
+	"corpord-api/internal/repository/pg"
+	"errors"
+	"time"
+)
+
+// Precondition проверяет версию записи перед изменением (If-Match): false — запись
+// изменилась с тех пор, как её прочитал клиент. nil — без проверки
+type Precondition func(version time.Time) bool
+
+// checkVersion сверяет текущую версию записи с условием запроса
+func checkVersion(match Precondition, version time.Time) error {
+	if match != nil && !match(version) {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// patchError переводит конфликт версий при сохранении в ошибку сервиса:
+// запись изменили между чтением и записью
+func patchError(err error) error {
+	if errors.Is(err, pg.ErrVersionConflict) {
+		return ErrVersionConflict
+	}
+	return err
+}
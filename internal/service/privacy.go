@@ -0,0 +1,220 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/model"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// erasureBatchSize сколько пользователей обезличивается за один запуск задачи
+const erasureBatchSize = 100
+
+// Privacy выгрузка и удаление персональных данных по запросу пользователя
+type Privacy interface {
+	// Export собирает ZIP-архив с JSON-файлами профиля, привязанных аккаунтов, сессий, пассажиров и заказов
+	Export(ctx context.Context, userID int) ([]byte, error)
+	RequestErasure(ctx context.Context, userID int) (*model.ErasureResponse, error)
+	CancelErasure(ctx context.Context, userID int) error
+	// EraseDue обезличивает пользователей, у которых истёк срок на отмену запроса. Возвращает их количество
+	EraseDue(ctx context.Context) (int, error)
+	// EraseDeleted обезличивает пользователей, мягко удалённых раньше before
+	EraseDeleted(ctx context.Context, before time.Time) (int, error)
+}
+
+type privacy struct {
+	logger     *logger.Logger
+	cfg        *config.Account
+	users      pg.UserRepository
+	identities pg.UserIdentitiesRepository
+	sessions   pg.RefreshTokenRepository
+	orders     pg.Order
+	passengers Passenger
+	erasure    pg.Erasure
+	events     pg.SecurityEventRepository
+}
+
+func NewPrivacy(
+	logger *logger.Logger,
+	cfg *config.Account,
+	users pg.UserRepository,
+	identities pg.UserIdentitiesRepository,
+	sessions pg.RefreshTokenRepository,
+	orders pg.Order,
+	passengers Passenger,
+	erasure pg.Erasure,
+	events pg.SecurityEventRepository,
+) Privacy {
+	return &privacy{
+		logger:     logger,
+		cfg:        cfg,
+		users:      users,
+		identities: identities,
+		sessions:   sessions,
+		orders:     orders,
+		passengers: passengers,
+		erasure:    erasure,
+		events:     events,
+	}
+}
+
+func (s *privacy) Export(ctx context.Context, userID int) ([]byte, error) {
+	profile, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pg.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	identities, err := s.identities.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	exportIdentities := make([]model.ExportIdentity, 0, len(identities))
+	for _, i := range identities {
+		exportIdentities = append(exportIdentities, model.ExportIdentity{
+			Provider:   i.Provider,
+			ProviderID: i.ProviderID,
+			CreatedAt:  i.CreatedAt,
+		})
+	}
+
+	sessions, err := s.sessions.ByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	exportSessions := make([]model.ExportSession, 0, len(sessions))
+	for _, rs := range sessions {
+		exportSessions = append(exportSessions, model.ExportSession{
+			ID:        rs.ID.String(),
+			IP:        rs.IP,
+			UserAgent: rs.UserAgent,
+			Revoked:   rs.Revoked,
+			CreatedAt: rs.CreatedAt,
+			ExpiresAt: rs.ExpiresAt,
+		})
+	}
+
+	passengers, err := s.passengers.All(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.orders.ByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", profile},
+		{"identities.json", exportIdentities},
+		{"sessions.json", exportSessions},
+		{"passengers.json", passengers},
+		{"orders.json", orders},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(ctx, model.SecurityEventDataExported, userID)
+	return buf.Bytes(), nil
+}
+
+// RequestErasure ставит аккаунт в очередь на обезличивание. До истечения ErasureGracePeriod
+// аккаунт работает как обычно и запрос можно отменить
+func (s *privacy) RequestErasure(ctx context.Context, userID int) (*model.ErasureResponse, error) {
+	requestedAt, err := s.erasure.Request(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pg.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	s.recordEvent(ctx, model.SecurityEventErasureRequest, userID)
+	return &model.ErasureResponse{
+		RequestedAt: requestedAt,
+		EraseAfter:  requestedAt.Add(s.cfg.ErasureGracePeriod),
+	}, nil
+}
+
+func (s *privacy) CancelErasure(ctx context.Context, userID int) error {
+	if err := s.erasure.Cancel(ctx, userID); err != nil {
+		if errors.Is(err, pg.ErrNotFound) {
+			return ErrErasureNotRequested
+		}
+		return err
+	}
+
+	s.recordEvent(ctx, model.SecurityEventErasureCancel, userID)
+	return nil
+}
+
+func (s *privacy) EraseDue(ctx context.Context) (int, error) {
+	ids, err := s.erasure.Due(ctx, time.Now().Add(-s.cfg.ErasureGracePeriod), erasureBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return s.anonymize(ctx, ids), nil
+}
+
+func (s *privacy) EraseDeleted(ctx context.Context, before time.Time) (int, error) {
+	ids, err := s.erasure.Deleted(ctx, before, erasureBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return s.anonymize(ctx, ids), nil
+}
+
+// anonymize обезличивает пользователей ids и возвращает число успешно обработанных
+func (s *privacy) anonymize(ctx context.Context, ids []int) int {
+	erased := 0
+	for _, id := range ids {
+		if err := s.erasure.Anonymize(ctx, id); err != nil {
+			// остальные пользователи обрабатываются, этот попадёт в следующий запуск
+			s.logger.Errorf("failed to anonymize user %d: %v", id, err)
+			continue
+		}
+		s.recordEvent(ctx, model.SecurityEventUserAnonymized, id)
+		erased++
+	}
+	return erased
+}
+
+func (s *privacy) recordEvent(ctx context.Context, eventType string, userID int) {
+	event := &model.SecurityEvent{
+		EventType: eventType,
+		UserID:    &userID,
+	}
+	if err := s.events.Save(ctx, event); err != nil {
+		s.logger.Warnf("failed to record security event %s: %v", eventType, err)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+	"corpord-api/internal/policy"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/model"
+	"errors"
+	"sync"
+	"time"
+)
+
+// permissionCacheTTL ограничивает, как долго другой инстанс API может видеть старый набор прав роли
+const permissionCacheTTL = time.Minute
+
+type Role interface {
+	All(ctx context.Context) ([]*model.Role, error)
+	Create(ctx context.Context, input *model.RoleCreate) (*model.Role, error)
+	SetPermissions(ctx context.Context, roleID int, codes []string) (*model.Role, error)
+	AllPermissions(ctx context.Context) ([]model.Permission, error)
+	AssignToUser(ctx context.Context, userID, roleID int) error
+	// HasPermission проверяет, что у роли есть хотя бы одно из прав codes
+	HasPermission(ctx context.Context, roleName string, codes ...string) (bool, error)
+	// Covers проверяет, что у пользователя из claims есть каждое право роли roleName.
+	// Так пользователь не может выдать или получить права шире своих
+	Covers(ctx context.Context, claims *model.Claims, roleName string) (bool, error)
+}
+
+type rolePermissions struct {
+	codes     map[string]struct{}
+	expiresAt time.Time
+}
+
+type role struct {
+	logger *logger.Logger
+	repo   pg.Role
+
+	mu    sync.RWMutex
+	cache map[string]rolePermissions
+}
+
+func NewRole(logger *logger.Logger, repo pg.Role) Role {
+	return &role{
+		logger: logger,
+		repo:   repo,
+		cache:  make(map[string]rolePermissions),
+	}
+}
+
+func (s *role) All(ctx context.Context) ([]*model.Role, error) {
+	return s.repo.All(ctx)
+}
+
+func (s *role) Create(ctx context.Context, input *model.RoleCreate) (*model.Role, error) {
+	id, err := s.repo.Create(ctx, input)
+	if err != nil {
+		return nil, mapRoleError(err)
+	}
+	return s.repo.ByID(ctx, id)
+}
+
+func (s *role) SetPermissions(ctx context.Context, roleID int, codes []string) (*model.Role, error) {
+	if err := s.repo.SetPermissions(ctx, roleID, codes); err != nil {
+		return nil, mapRoleError(err)
+	}
+	s.invalidate()
+	return s.repo.ByID(ctx, roleID)
+}
+
+func (s *role) AllPermissions(ctx context.Context) ([]model.Permission, error) {
+	return s.repo.AllPermissions(ctx)
+}
+
+// AssignToUser меняет роль пользователя. Уже выданный access token
+// продолжает нести старую роль до истечения срока
+func (s *role) AssignToUser(ctx context.Context, userID, roleID int) error {
+	if _, err := s.repo.ByID(ctx, roleID); err != nil {
+		return mapRoleError(err)
+	}
+
+	if err := s.repo.AssignToUser(ctx, userID, roleID); err != nil {
+		if errors.Is(err, pg.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		if errors.Is(err, pg.ErrForeignKeyViolation) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *role) HasPermission(ctx context.Context, roleName string, codes ...string) (bool, error) {
+	perms, err := s.permissions(ctx, roleName)
+	if err != nil {
+		return false, err
+	}
+
+	for _, code := range codes {
+		if _, ok := perms[code]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *role) Covers(ctx context.Context, claims *model.Claims, roleName string) (bool, error) {
+	target, err := s.permissions(ctx, roleName)
+	if err != nil {
+		return false, err
+	}
+
+	for code := range target {
+		ok, err := policy.Allowed(ctx, s, claims, code)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// permissions возвращает права роли, кешируя их на permissionCacheTTL
+func (s *role) permissions(ctx context.Context, roleName string) (map[string]struct{}, error) {
+	s.mu.RLock()
+	entry, ok := s.cache[roleName]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.codes, nil
+	}
+
+	codes, err := s.repo.PermissionsByRole(ctx, roleName)
+	if err != nil {
+		s.logger.Errorf("failed to load permissions for role %s: %v", roleName, err)
+		return nil, err
+	}
+
+	set := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		set[code] = struct{}{}
+	}
+
+	s.mu.Lock()
+	s.cache[roleName] = rolePermissions{codes: set, expiresAt: time.Now().Add(permissionCacheTTL)}
+	s.mu.Unlock()
+
+	return set, nil
+}
+
+func (s *role) invalidate() {
+	s.mu.Lock()
+	s.cache = make(map[string]rolePermissions)
+	s.mu.Unlock()
+}
+
+func mapRoleError(err error) error {
+	switch {
+	case errors.Is(err, pg.ErrNotFound):
+		return ErrRoleNotFound
+	case errors.Is(err, pg.ErrAlreadyExists):
+		return ErrRoleExists
+	case errors.Is(err, pg.ErrUnknownPermission):
+		return ErrUnknownPermission
+	default:
+		return err
+	}
+}
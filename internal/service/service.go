@@ -1,35 +1,82 @@
 package service
 
 import (
+	"corpord-api/internal/config"
+	"corpord-api/internal/encryption"
 	"corpord-api/internal/logger"
+	"corpord-api/internal/mailer"
 	"corpord-api/internal/repository"
+	"corpord-api/internal/sms"
 	"corpord-api/internal/sso"
 	"corpord-api/internal/token"
 )
 
 // Service aggregates all service interfaces
 type Service struct {
-	logger   *logger.Logger
-	token    token.Manager
-	User     User
-	Auth     Auth
-	Bus      Bus
-	BC       BusCategory
-	BS       BusStatus
-	DS       DriverStatus
-	Driver   Driver
-	Trip     Trip
-	TripStop TripStop
-	Stop     Stop
+	logger    *logger.Logger
+	token     token.Manager
+	User      User
+	Auth      Auth
+	Account   Account
+	Bus       Bus
+	BC        BusCategory
+	BS        BusStatus
+	DS        DriverStatus
+	Driver    Driver
+	Trip      Trip
+	TripStop  TripStop
+	Stop      Stop
+	Role      Role
+	APIKey    APIKey
+	Passenger Passenger
+	Privacy   Privacy
 }
 
 // New creates a new service instance with all dependencies
-func New(logger *logger.Logger, repo *repository.Repository, token token.Manager, sso *sso.Registry) *Service {
+func New(
+	logger *logger.Logger,
+	repo *repository.Repository,
+	token token.Manager,
+	sso *sso.Registry,
+	cfg *config.Config,
+	mailer mailer.Mailer,
+	sender sms.Sender,
+	cipher *encryption.Cipher,
+) *Service {
+	passenger := NewPassenger(logger, repo.PgRepository.Passenger, cipher)
+	role := NewRole(logger, repo.PgRepository.Role)
+
 	return &Service{
-		logger:   logger,
-		token:    token,
-		User:     NewUser(logger, repo.PgRepository.User),
-		Auth:     NewAuth(logger, token, repo.PgRepository.Auth, repo.PgRepository.RefreshToken, repo.PgRepository.UserIdentity, sso),
+		logger: logger,
+		token:  token,
+		User:   NewUser(logger, repo.PgRepository.User, role),
+		Auth: NewAuth(
+			logger,
+			token,
+			repo.PgRepository.Auth,
+			repo.PgRepository.RefreshToken,
+			repo.PgRepository.UserIdentity,
+			repo.PgRepository.SecurityEvent,
+			repo.PgRepository.Tx,
+			role,
+			repo.RedisRepository.LoginAttempt,
+			&cfg.Security.Login,
+			repo.RedisRepository.OAuthState,
+			&cfg.SSO,
+			sso,
+			repo.RedisRepository.OTP,
+			&cfg.Security.OTP,
+			sender,
+			cfg.Security.ImpersonationTTL,
+		),
+		Account: NewAccount(
+			logger,
+			&cfg.Account,
+			repo.PgRepository.Auth,
+			repo.PgRepository.UserToken,
+			repo.PgRepository.RefreshToken,
+			mailer,
+		),
 		Bus:      NewBus(logger, repo.PgRepository.Bus),
 		BC:       NewBusCategory(logger, repo.PgRepository.Bc),
 		BS:       NewBusStatus(logger, repo.PgRepository.Bs),
@@ -38,5 +85,26 @@ func New(logger *logger.Logger, repo *repository.Repository, token token.Manager
 		Trip:     NewTrip(logger, repo.PgRepository.Trip),
 		TripStop: NewTripStop(logger, repo.PgRepository.TripStop),
 		Stop:     NewStop(logger, repo.PgRepository.Stop),
+		Role:     role,
+		APIKey: NewAPIKey(
+			logger,
+			repo.PgRepository.APIKey,
+			repo.PgRepository.Auth,
+			repo.PgRepository.Role,
+			role,
+			repo.PgRepository.SecurityEvent,
+		),
+		Passenger: passenger,
+		Privacy: NewPrivacy(
+			logger,
+			&cfg.Account,
+			repo.PgRepository.User,
+			repo.PgRepository.UserIdentity,
+			repo.PgRepository.RefreshToken,
+			repo.PgRepository.Order,
+			passenger,
+			repo.PgRepository.Erasure,
+			repo.PgRepository.SecurityEvent,
+		),
 	}
 }
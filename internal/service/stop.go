@@ -3,16 +3,24 @@ package service
 import (
 	"corpord-api/internal/logger"
 	"corpord-api/internal/repository/pg"
+	"corpord-api/internal/validation"
 	"corpord-api/model"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/mergepatch"
+	"corpord-api/pkg/paging"
 	"golang.org/x/net/context"
 )
 
 type Stop interface {
-	All(ctx context.Context) ([]*model.Stop, error)
+	All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.Stop], error)
 	ByID(ctx context.Context, id int) (*model.Stop, error)
 	Create(ctx context.Context, stop *model.Stop) error
+	CreateMany(ctx context.Context, stops []*model.Stop) ([]int, error)
 	Update(ctx context.Context, stop *model.StopUpdate) error
+	UpdateMany(ctx context.Context, stops []*model.StopUpdate) error
+	Patch(ctx context.Context, id int, patch []byte, match Precondition) (*model.StopPatch, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
 }
 
 type stop struct {
@@ -27,8 +35,8 @@ func NewStop(logger *logger.Logger, repo pg.Stop) Stop {
 	}
 }
 
-func (s *stop) All(ctx context.Context) ([]*model.Stop, error) {
-	return s.repo.All(ctx)
+func (s *stop) All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.Stop], error) {
+	return s.repo.All(ctx, f, p)
 }
 
 func (s *stop) ByID(ctx context.Context, id int) (*model.Stop, error) {
@@ -41,11 +49,56 @@ func (s *stop) Create(ctx context.Context, stop *model.Stop) error {
 
 func (s *stop) Update(ctx context.Context, stop *model.StopUpdate) error {
 	if err := stop.Validate(); err != nil {
+		return invalid(err)
+	}
+	return mapNotFound(s.repo.Update(ctx, stop), ErrStopNotFound)
+}
+
+// CreateMany создаёт остановки в одной транзакции: если не удалась хотя бы одна, не создаётся ни одной
+func (s *stop) CreateMany(ctx context.Context, stops []*model.Stop) ([]int, error) {
+	return s.repo.CreateMany(ctx, stops)
+}
+
+// UpdateMany проверяет все остановки и обновляет их в одной транзакции
+func (s *stop) UpdateMany(ctx context.Context, stops []*model.StopUpdate) error {
+	if err := validateEach(stops); err != nil {
 		return err
 	}
-	return s.repo.Update(ctx, stop)
+	return mapNotFound(s.repo.UpdateMany(ctx, stops), ErrStopNotFound)
+}
+
+// Patch применяет к остановке JSON Merge Patch и сохраняет результат,
+// только если остановку не изменили параллельно
+func (s *stop) Patch(ctx context.Context, id int, patch []byte, match Precondition) (*model.StopPatch, error) {
+	current, err := s.repo.ForPatch(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, ErrStopNotFound)
+	}
+	if err := checkVersion(match, current.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	next := *current
+	if err := mergepatch.Into(&next, patch); err != nil {
+		return nil, err
+	}
+	next.ID, next.UpdatedAt = current.ID, current.UpdatedAt
+	if err := validation.Struct(&next); err != nil {
+		return nil, err
+	}
+
+	next.UpdatedAt, err = s.repo.Patch(ctx, &next)
+	if err != nil {
+		return nil, patchError(err)
+	}
+	return &next, nil
 }
 
 func (s *stop) Delete(ctx context.Context, id int) error {
-	return s.repo.Delete(ctx, id)
+	return mapNotFound(s.repo.Delete(ctx, id), ErrStopNotFound)
+}
+
+// Restore восстанавливает удалённую остановку
+func (s *stop) Restore(ctx context.Context, id int) error {
+	return mapNotFound(s.repo.Restore(ctx, id), ErrStopNotFound)
 }
@@ -3,16 +3,23 @@ package service
 import (
 	"corpord-api/internal/logger"
 	"corpord-api/internal/repository/pg"
+	"corpord-api/internal/validation"
 	"corpord-api/model"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/mergepatch"
+	"corpord-api/pkg/paging"
+	"time"
+
 	"golang.org/x/net/context"
 )
 
 type Trip interface {
-	All(ctx context.Context) ([]*model.TripResponse, error)
-	AllShort(ctx context.Context) ([]*model.TripShortInfo, error)
+	All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripResponse], error)
+	AllShort(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripShortInfo], error)
 	ById(ctx context.Context, id int) (*model.TripResponse, error)
 	Create(ctx context.Context, trip *model.Trip) error
-	Update(ctx context.Context, trip *model.TripUpdate) error
+	Update(ctx context.Context, trip *model.TripUpdate, match Precondition) (time.Time, error)
+	Patch(ctx context.Context, id int, patch []byte, match Precondition) (*model.TripPatch, error)
 	Delete(ctx context.Context, id int) error
 }
 
@@ -28,12 +35,12 @@ func NewTrip(logger *logger.Logger, repo pg.Trip) Trip {
 	}
 }
 
-func (t *trip) All(ctx context.Context) ([]*model.TripResponse, error) {
-	return t.repo.All(ctx)
+func (t *trip) All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripResponse], error) {
+	return t.repo.All(ctx, f, p)
 }
 
-func (t *trip) AllShort(ctx context.Context) ([]*model.TripShortInfo, error) {
-	return t.repo.AllShort(ctx)
+func (t *trip) AllShort(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripShortInfo], error) {
+	return t.repo.AllShort(ctx, f, p)
 }
 
 func (t *trip) ById(ctx context.Context, id int) (*model.TripResponse, error) {
@@ -44,11 +51,56 @@ func (t *trip) Create(ctx context.Context, trip *model.Trip) error {
 	return t.repo.Create(ctx, trip)
 }
 
-func (t *trip) Update(ctx context.Context, trip *model.TripUpdate) error {
+// Update меняет переданные поля рейса и возвращает его новую версию.
+// Как и Patch, сохраняет изменения, только если рейс не изменили параллельно
+func (t *trip) Update(ctx context.Context, trip *model.TripUpdate, match Precondition) (time.Time, error) {
 	if err := trip.Validate(); err != nil {
-		return err
+		return time.Time{}, invalid(err)
+	}
+
+	current, err := t.repo.ForPatch(ctx, trip.ID)
+	if err != nil {
+		return time.Time{}, mapNotFound(err, ErrTripNotFound)
+	}
+	if err := checkVersion(match, current.UpdatedAt); err != nil {
+		return time.Time{}, err
+	}
+	if err := trip.ValidateWith(current); err != nil {
+		return time.Time{}, invalid(err)
+	}
+
+	version, err := t.repo.Update(ctx, trip, current.UpdatedAt)
+	if err != nil {
+		return time.Time{}, patchError(err)
+	}
+	return version, nil
+}
+
+// Patch применяет к рейсу JSON Merge Patch и сохраняет результат,
+// только если рейс не изменили параллельно
+func (t *trip) Patch(ctx context.Context, id int, patch []byte, match Precondition) (*model.TripPatch, error) {
+	current, err := t.repo.ForPatch(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err, ErrTripNotFound)
+	}
+	if err := checkVersion(match, current.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	next := *current
+	if err := mergepatch.Into(&next, patch); err != nil {
+		return nil, err
+	}
+	next.ID, next.UpdatedAt = current.ID, current.UpdatedAt
+	if err := validation.Struct(&next); err != nil {
+		return nil, err
+	}
+
+	next.UpdatedAt, err = t.repo.Patch(ctx, &next)
+	if err != nil {
+		return nil, patchError(err)
 	}
-	return t.repo.Update(ctx, trip)
+	return &next, nil
 }
 
 func (t *trip) Delete(ctx context.Context, id int) error {
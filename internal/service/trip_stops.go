@@ -4,14 +4,18 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/internal/repository/pg"
 	"corpord-api/model"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
 	"golang.org/x/net/context"
 )
 
 type TripStop interface {
-	All(ctx context.Context) ([]*model.TripStop, error)
+	All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripStop], error)
 	ByID(ctx context.Context, id int) (*model.TripStop, error)
 	Create(ctx context.Context, trip *model.TripStop) error
+	CreateMany(ctx context.Context, trips []*model.TripStop) ([]int, error)
 	Update(ctx context.Context, trip *model.TripStopUpdate) error
+	UpdateMany(ctx context.Context, trips []*model.TripStopUpdate) error
 	Delete(ctx context.Context, id int) error
 }
 
@@ -27,8 +31,8 @@ func NewTripStop(logger *logger.Logger, repo pg.TripStop) TripStop {
 	}
 }
 
-func (t *tripStop) All(ctx context.Context) ([]*model.TripStop, error) {
-	return t.repo.All(ctx)
+func (t *tripStop) All(ctx context.Context, f filter.Filter, p paging.Params) (*paging.Page[*model.TripStop], error) {
+	return t.repo.All(ctx, f, p)
 }
 
 func (t *tripStop) ByID(ctx context.Context, id int) (*model.TripStop, error) {
@@ -41,9 +45,22 @@ func (t *tripStop) Create(ctx context.Context, trip *model.TripStop) error {
 
 func (t *tripStop) Update(ctx context.Context, trip *model.TripStopUpdate) error {
 	if err := trip.Validate(); err != nil {
+		return invalid(err)
+	}
+	return mapNotFound(t.repo.Update(ctx, trip), ErrTripStopNotFound)
+}
+
+// CreateMany создаёт остановки рейсов в одной транзакции: если не удалась хотя бы одна, не создаётся ни одной
+func (t *tripStop) CreateMany(ctx context.Context, trips []*model.TripStop) ([]int, error) {
+	return t.repo.CreateMany(ctx, trips)
+}
+
+// UpdateMany проверяет все остановки рейсов и обновляет их в одной транзакции
+func (t *tripStop) UpdateMany(ctx context.Context, trips []*model.TripStopUpdate) error {
+	if err := validateEach(trips); err != nil {
 		return err
 	}
-	return t.repo.Update(ctx, trip)
+	return mapNotFound(t.repo.UpdateMany(ctx, trips), ErrTripStopNotFound)
 }
 
 func (t *tripStop) Delete(ctx context.Context, id int) error {
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"corpord-api/internal/repository/pg"
+	"corpord-api/model"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTripStopUpdateManyNotFound(t *testing.T) {
+	conflict := errors.New("duplicate stop order")
+	repo := &fakeTripStopRepo{err: errors.Join(
+		&pg.ItemError{Index: 0, Err: pg.ErrNotFound},
+		&pg.ItemError{Index: 1, Err: conflict},
+		&pg.ItemError{Index: 2, Err: pg.ErrNotFound},
+	)}
+	s := NewTripStop(testLogger(), repo)
+
+	order := 1
+	items := []*model.TripStopUpdate{{ID: 7, StopOrder: &order}, {ID: 8, StopOrder: &order}, {ID: 9, StopOrder: &order}}
+	err := s.UpdateMany(context.Background(), items)
+
+	want := map[int]error{0: ErrTripStopNotFound, 1: conflict, 2: ErrTripStopNotFound}
+	got := map[int]error{}
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var item *pg.ItemError
+		if !errors.As(e, &item) {
+			t.Fatalf("error %v has no item index", e)
+		}
+		got[item.Index] = item.Err
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("item errors = %v, want %v", got, want)
+	}
+}
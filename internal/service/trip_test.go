@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"corpord-api/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+var testTripVersion = time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestTrips() (*trip, *fakeTripRepo) {
+	start := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	repo := &fakeTripRepo{trips: map[int]*model.TripPatch{
+		1: {
+			ID:        1,
+			BusID:     1,
+			DriverID:  1,
+			StartTime: start,
+			EndTime:   &end,
+			Status:    "scheduled",
+			BasePrice: 500,
+			UpdatedAt: testTripVersion,
+		},
+	}}
+	return &trip{logger: testLogger(), repo: repo}, repo
+}
+
+// tripPreconditions случаи If-Match, общие для PUT и PATCH рейса
+var tripPreconditions = []struct {
+	name  string
+	id    int
+	match func(repo *fakeTripRepo) Precondition
+	want  error
+}{
+	{name: "without If-Match", id: 1, match: func(*fakeTripRepo) Precondition { return nil }},
+	{
+		name: "current version",
+		id:   1,
+		match: func(*fakeTripRepo) Precondition {
+			return func(v time.Time) bool { return v.Equal(testTripVersion) }
+		},
+	},
+	{
+		name: "stale version",
+		id:   1,
+		match: func(*fakeTripRepo) Precondition {
+			return func(time.Time) bool { return false }
+		},
+		want: ErrVersionConflict,
+	},
+	{
+		name: "changed between read and write",
+		id:   1,
+		match: func(repo *fakeTripRepo) Precondition {
+			return func(time.Time) bool {
+				repo.trips[1].UpdatedAt = repo.trips[1].UpdatedAt.Add(time.Minute)
+				return true
+			}
+		},
+		want: ErrVersionConflict,
+	},
+	{name: "missing trip", id: 9, match: func(*fakeTripRepo) Precondition { return nil }, want: ErrTripNotFound},
+}
+
+func TestTripUpdatePrecondition(t *testing.T) {
+	for _, tt := range tripPreconditions {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestTrips()
+
+			status := "cancelled"
+			version, err := s.Update(context.Background(), &model.TripUpdate{ID: tt.id, Status: &status}, tt.match(repo))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if updated := len(repo.updates) == 1; updated != (tt.want == nil) {
+				t.Fatalf("updated = %v, want %v", updated, tt.want == nil)
+			}
+			if tt.want == nil && !version.After(testTripVersion) {
+				t.Fatalf("version = %v, want newer than %v", version, testTripVersion)
+			}
+		})
+	}
+}
+
+func TestTripPatchPrecondition(t *testing.T) {
+	for _, tt := range tripPreconditions {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestTrips()
+
+			got, err := s.Patch(context.Background(), tt.id, []byte(`{"status":"cancelled"}`), tt.match(repo))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				return
+			}
+			if got.Status != "cancelled" || !got.UpdatedAt.After(testTripVersion) {
+				t.Fatalf("patched trip = %+v, want new status and version", got)
+			}
+		})
+	}
+}
+
+func TestTripUpdateTimesAgainstStored(t *testing.T) {
+	stored := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		update  model.TripUpdate
+		invalid bool
+	}{
+		{name: "end before stored start", update: model.TripUpdate{ID: 1, EndTime: ptr(stored.Add(-time.Hour))}, invalid: true},
+		{name: "end equal to stored start", update: model.TripUpdate{ID: 1, EndTime: ptr(stored)}, invalid: true},
+		{name: "start after stored end", update: model.TripUpdate{ID: 1, StartTime: ptr(stored.Add(5 * time.Hour))}, invalid: true},
+		{name: "start before stored end", update: model.TripUpdate{ID: 1, StartTime: ptr(stored.Add(time.Hour))}},
+		{name: "end after stored start", update: model.TripUpdate{ID: 1, EndTime: ptr(stored.Add(time.Hour))}},
+		{name: "both moved together", update: model.TripUpdate{ID: 1, StartTime: ptr(stored.Add(24 * time.Hour)), EndTime: ptr(stored.Add(26 * time.Hour))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestTrips()
+
+			_, err := s.Update(context.Background(), &tt.update, nil)
+			var verr *ValidationError
+			if got := errors.As(err, &verr); got != tt.invalid {
+				t.Fatalf("err = %v, want validation error: %v", err, tt.invalid)
+			}
+			if tt.invalid && len(repo.updates) != 0 {
+				t.Fatal("invalid update reached the repository")
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -5,40 +5,42 @@ import (
 	"corpord-api/internal/logger"
 	"corpord-api/internal/repository/pg"
 	"corpord-api/model"
+	"corpord-api/pkg/filter"
+	"corpord-api/pkg/paging"
 	"errors"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type User interface {
-	GetAll(ctx context.Context) ([]*model.UserResponse, error)
+	Search(ctx context.Context, search *model.UserSearch, f filter.Filter, p paging.Params) (*paging.Page[*model.UserResponse], error)
 	GetByID(ctx context.Context, id int) (*model.UserResponse, error)
 	GetByEmail(ctx context.Context, email string) (*model.UserDB, error)
 	Create(ctx context.Context, user *model.UserCreate) (*model.UserResponse, error)
 	Update(ctx context.Context, id int, update *model.UserUpdate) (*model.UserResponse, error)
 	Delete(ctx context.Context, id int) error
+	Remove(ctx context.Context, id int, caller *model.Claims) error
+	Restore(ctx context.Context, id int) error
+	UpdateProfile(ctx context.Context, id int, profile *model.ProfileUpdate) (*model.UserResponse, error)
 }
 
 type user struct {
 	logger *logger.Logger
 	r      pg.UserRepository
+	access Role
 }
 
-func NewUser(logger *logger.Logger, r pg.UserRepository) User {
+func NewUser(logger *logger.Logger, r pg.UserRepository, access Role) User {
 	return &user{
 		logger: logger,
 		r:      r,
+		access: access,
 	}
 }
 
-// GetAll возвращает всех пользователей
-func (s *user) GetAll(ctx context.Context) ([]*model.UserResponse, error) {
-	responses, err := s.r.GetAll(ctx)
-	if err != nil {
-		s.logger.Errorf("failed to get all users: %v", err)
-		return nil, err
-	}
-	return responses, nil
+// Search ищет пользователей для админки с постраничной выдачей
+func (s *user) Search(ctx context.Context, search *model.UserSearch, f filter.Filter, p paging.Params) (*paging.Page[*model.UserResponse], error) {
+	return s.r.Search(ctx, search, f, p)
 }
 
 // GetByID возвращает пользователя по ID
@@ -112,7 +114,7 @@ func (s *user) Create(ctx context.Context, userCreate *model.UserCreate) (*model
 // Update обновляет пользователя
 func (s *user) Update(ctx context.Context, id int, update *model.UserUpdate) (*model.UserResponse, error) {
 	if err := update.Validate(); err != nil {
-		return nil, err
+		return nil, invalid(err)
 	}
 
 	if update.Password != nil {
@@ -128,9 +130,45 @@ func (s *user) Update(ctx context.Context, id int, update *model.UserUpdate) (*m
 	return s.r.Update(ctx, id, update)
 }
 
+// UpdateProfile обновляет профиль текущего пользователя
+func (s *user) UpdateProfile(ctx context.Context, id int, profile *model.ProfileUpdate) (*model.UserResponse, error) {
+	resp, err := s.r.UpdateProfile(ctx, id, profile)
+	if err != nil {
+		if errors.Is(err, pg.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
 // Delete удаляет пользователя
 func (s *user) Delete(ctx context.Context, id int) error {
-	return s.r.Delete(ctx, id)
+	return mapNotFound(s.r.Delete(ctx, id), ErrUserNotFound)
+}
+
+// Remove удаляет пользователя по запросу caller. Чужой аккаунт с правами шире,
+// чем у caller, удалить нельзя
+func (s *user) Remove(ctx context.Context, id int, caller *model.Claims) error {
+	if caller.UserID != id {
+		target, err := s.r.GetByID(ctx, id)
+		if err != nil {
+			return mapNotFound(err, ErrUserNotFound)
+		}
+		ok, err := s.access.Covers(ctx, caller, target.Role)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrPrivilegeEscalation
+		}
+	}
+	return s.Delete(ctx, id)
+}
+
+// Restore восстанавливает удалённого пользователя
+func (s *user) Restore(ctx context.Context, id int) error {
+	return mapNotFound(s.r.Restore(ctx, id), ErrUserNotFound)
 }
 
 // Login проверяет локальные креды пользователя
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"corpord-api/model"
+	"errors"
+	"testing"
+)
+
+func TestUserRemoveRefusesWiderTarget(t *testing.T) {
+	users := map[int]*model.UserResponse{
+		1: {ID: 1, Role: "support"},
+		2: {ID: 2, Role: "fleet"},
+		3: {ID: 3, Role: model.RoleAdmin},
+	}
+	support := &model.Claims{UserID: 1, Role: "support"}
+
+	tests := []struct {
+		name   string
+		userID int
+		want   error
+	}{
+		{"own account", 1, nil},
+		{"narrower role", 2, nil},
+		{"wider role", 3, ErrPrivilegeEscalation},
+		{"missing user", 9, ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeUserRepo{users: users}
+			s := NewUser(testLogger(), repo, NewRole(testLogger(), newFakeRoleRepo()))
+
+			err := s.Remove(context.Background(), tt.userID, support)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if deleted := len(repo.deleted) == 1; deleted != (tt.want == nil) {
+				t.Fatalf("deleted = %v, want %v", deleted, tt.want == nil)
+			}
+		})
+	}
+}
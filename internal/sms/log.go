@@ -0,0 +1,20 @@
+package sms
+
+import (
+	"context"
+	"corpord-api/internal/logger"
+)
+
+// LogSender — sender для локальной разработки: вместо отправки пишет SMS в лог
+type LogSender struct {
+	log *logger.Logger
+}
+
+func NewLog(log *logger.Logger) Sender {
+	return &LogSender{log: log}
+}
+
+func (s *LogSender) Send(_ context.Context, phone, text string) error {
+	s.log.Infof("sms to %s: %s", phone, text)
+	return nil
+}
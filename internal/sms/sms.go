@@ -0,0 +1,23 @@
+package sms
+
+import (
+	"context"
+	"corpord-api/internal/config"
+	"corpord-api/internal/logger"
+	"fmt"
+)
+
+// Sender отправляет SMS на номер в формате E.164
+type Sender interface {
+	Send(ctx context.Context, phone, text string) error
+}
+
+// New создает sender в зависимости от выбранного драйвера
+func New(cfg *config.SMS, log *logger.Logger) (Sender, error) {
+	switch cfg.Driver {
+	case "log", "":
+		return NewLog(log), nil
+	default:
+		return nil, fmt.Errorf("unsupported sms driver: %s", cfg.Driver)
+	}
+}
@@ -32,13 +32,14 @@ func NewGoogleProvider(clientID, clientSecret, redirectURL string) Provider {
 }
 
 // формирует URL куда пользователь уйдёт для авторизации
-func (g *GoogleProvider) AuthURL(state string) string {
-	return g.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+func (g *GoogleProvider) AuthURL(params AuthParams) string {
+	opts := append([]oauth2.AuthCodeOption{oauth2.AccessTypeOffline}, authCodeOptions(params)...)
+	return g.config.AuthCodeURL(params.State, opts...)
 }
 
 // обмен кода на токен
-func (g *GoogleProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
-	tok, err := g.config.Exchange(ctx, code)
+func (g *GoogleProvider) Exchange(ctx context.Context, code string, params AuthParams) (*oauth2.Token, error) {
+	tok, err := g.config.Exchange(ctx, code, exchangeOptions(params)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to exchange code: %w", err)
 	}
@@ -54,7 +55,7 @@ type googleUser struct {
 	Picture       string `json:"picture"`
 }
 
-func (g *GoogleProvider) GetUserInfo(ctx context.Context, token *oauth2.Token) (*UserInfo, error) {
+func (g *GoogleProvider) GetUserInfo(ctx context.Context, token *oauth2.Token, _ AuthParams) (*UserInfo, error) {
 	req, _ := http.NewRequestWithContext(ctx, "GET", "https://www.googleapis.com/oauth2/v3/userinfo", nil)
 	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
 
@@ -70,10 +71,11 @@ func (g *GoogleProvider) GetUserInfo(ctx context.Context, token *oauth2.Token) (
 	}
 
 	return &UserInfo{
-		Provider:   "google",
-		ProviderID: gu.ID,
-		Email:      gu.Email,
-		Name:       gu.Name,
+		Provider:      "google",
+		ProviderID:    gu.ID,
+		Email:         gu.Email,
+		EmailVerified: gu.EmailVerified,
+		Name:          gu.Name,
 	}, nil
 }
 
@@ -1,13 +1,66 @@
 package sso
 
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"golang.org/x/oauth2"
+)
+
 type Token interface {
 	AccessToken() string
 }
 
 // UserInfo — всё, что нужно твоей системе.
 type UserInfo struct {
-	Provider   string
-	ProviderID string
-	Email      string
-	Name       string
+	Provider      string
+	ProviderID    string
+	Email         string
+	EmailVerified bool // провайдер подтверждает, что email принадлежит пользователю
+	Name          string
+}
+
+// NewAuthParams генерирует state, nonce и PKCE verifier для нового входа
+func NewAuthParams() (AuthParams, error) {
+	state, err := randomString(32)
+	if err != nil {
+		return AuthParams{}, err
+	}
+	nonce, err := randomString(32)
+	if err != nil {
+		return AuthParams{}, err
+	}
+	return AuthParams{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: oauth2.GenerateVerifier(),
+	}, nil
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// authCodeOptions параметры PKCE и nonce для AuthCodeURL
+func authCodeOptions(params AuthParams) []oauth2.AuthCodeOption {
+	var opts []oauth2.AuthCodeOption
+	if params.CodeVerifier != "" {
+		opts = append(opts, oauth2.S256ChallengeOption(params.CodeVerifier))
+	}
+	if params.Nonce != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("nonce", params.Nonce))
+	}
+	return opts
+}
+
+// exchangeOptions параметры PKCE для обмена кода
+func exchangeOptions(params AuthParams) []oauth2.AuthCodeOption {
+	if params.CodeVerifier == "" {
+		return nil
+	}
+	return []oauth2.AuthCodeOption{oauth2.VerifierOption(params.CodeVerifier)}
 }
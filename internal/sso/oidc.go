@@ -0,0 +1,385 @@
+package sso
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// минимальный интервал между повторными загрузками JWKS при неизвестном kid
+const jwksRefreshInterval = time.Minute
+
+var (
+	ErrMissingIDToken = errors.New("oidc: id_token missing in token response")
+	ErrInvalidIDToken = errors.New("oidc: invalid id_token")
+	ErrNonceMismatch  = errors.New("oidc: nonce mismatch")
+)
+
+// OIDCConfig настройки OpenID Connect провайдера
+type OIDCConfig struct {
+	Name         string // имя в реестре и в user_identities.provider
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string     // по умолчанию openid, email, profile
+	HTTPClient   *http.Client // для тестов и прокси; по умолчанию http.DefaultClient
+}
+
+// OIDCProvider универсальный провайдер OpenID Connect: конечные точки берутся из discovery,
+// ID token проверяется по подписи (JWKS), issuer, audience, сроку действия и nonce
+type OIDCProvider struct {
+	name     string
+	issuer   string
+	client   *http.Client
+	oauth    *oauth2.Config
+	userinfo string
+	jwksURI  string
+
+	mu          sync.RWMutex
+	keys        map[string]any
+	keysFetched time.Time
+}
+
+type oidcDiscovery struct {
+	Issuer           string `json:"issuer"`
+	AuthEndpoint     string `json:"authorization_endpoint"`
+	TokenEndpoint    string `json:"token_endpoint"`
+	UserInfoEndpoint string `json:"userinfo_endpoint"`
+	JWKSURI          string `json:"jwks_uri"`
+}
+
+// NewOIDCProvider выполняет discovery и загружает ключи издателя
+func NewOIDCProvider(ctx context.Context, cfg OIDCConfig) (*OIDCProvider, error) {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	issuer := strings.TrimSuffix(cfg.Issuer, "/")
+	var doc oidcDiscovery
+	if err := getJSON(ctx, client, issuer+"/.well-known/openid-configuration", &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery for %s failed: %w", cfg.Name, err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oidc discovery for %s: issuer mismatch: %q", cfg.Name, doc.Issuer)
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	p := &OIDCProvider{
+		name:     cfg.Name,
+		issuer:   doc.Issuer,
+		client:   client,
+		userinfo: doc.UserInfoEndpoint,
+		jwksURI:  doc.JWKSURI,
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       scopes,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  doc.AuthEndpoint,
+				TokenURL: doc.TokenEndpoint,
+			},
+		},
+	}
+
+	if err := p.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *OIDCProvider) AuthURL(params AuthParams) string {
+	return p.oauth.AuthCodeURL(params.State, authCodeOptions(params)...)
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, params AuthParams) (*oauth2.Token, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	tok, err := p.oauth.Exchange(ctx, code, exchangeOptions(params)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	return tok, nil
+}
+
+// idTokenClaims поля ID token, которые нам нужны
+type idTokenClaims struct {
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     any    `json:"email_verified"` // bool, но некоторые издатели отдают строку
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	jwt.RegisteredClaims
+}
+
+// GetUserInfo проверяет ID token из ответа токен-эндпоинта и извлекает из него пользователя.
+// Если email в ID token нет, дозапрашивает userinfo
+func (p *OIDCProvider) GetUserInfo(ctx context.Context, token *oauth2.Token, params AuthParams) (*UserInfo, error) {
+	raw, _ := token.Extra("id_token").(string)
+	if raw == "" {
+		return nil, ErrMissingIDToken
+	}
+
+	claims, err := p.verifyIDToken(ctx, raw, params.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &UserInfo{
+		Provider:      p.name,
+		ProviderID:    claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+		Name:          claims.Name,
+	}
+	if info.Name == "" {
+		info.Name = claims.PreferredUsername
+	}
+
+	if info.Email == "" && p.userinfo != "" {
+		if err := p.fillFromUserInfo(ctx, token, info); err != nil {
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
+// verifyIDToken проверяет подпись, issuer, audience, срок действия и nonce (если ожидается)
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, raw, nonce string) (*idTokenClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return p.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithAudience(p.oauth.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	// при нескольких audience токен должен быть выдан именно нам
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.oauth.ClientID {
+		return nil, fmt.Errorf("%w: unexpected azp %q", ErrInvalidIDToken, claims.AuthorizedParty)
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: empty sub", ErrInvalidIDToken)
+	}
+
+	return &claims, nil
+}
+
+func (p *OIDCProvider) fillFromUserInfo(ctx context.Context, token *oauth2.Token, info *UserInfo) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.userinfo, nil)
+	if err != nil {
+		return err
+	}
+	token.SetAuthHeader(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("oidc userinfo failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc userinfo failed: status %d", resp.StatusCode)
+	}
+
+	var data struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("decode oidc userinfo failed: %w", err)
+	}
+	// userinfo обязан относиться к тому же субъекту, что и ID token
+	if data.Sub != info.ProviderID {
+		return fmt.Errorf("%w: userinfo sub mismatch", ErrInvalidIDToken)
+	}
+
+	info.Email = data.Email
+	info.EmailVerified = isTrue(data.EmailVerified)
+	if info.Name == "" {
+		info.Name = data.Name
+	}
+	return nil
+}
+
+// key возвращает ключ по kid; при неизвестном kid перечитывает JWKS (ротация ключей издателя)
+func (p *OIDCProvider) key(ctx context.Context, kid string) (any, error) {
+	p.mu.RLock()
+	k, ok := p.lookup(kid)
+	stale := time.Since(p.keysFetched) > jwksRefreshInterval
+	p.mu.RUnlock()
+	if ok {
+		return k, nil
+	}
+
+	if stale {
+		if err := p.refreshKeys(ctx); err != nil {
+			return nil, err
+		}
+		p.mu.RLock()
+		k, ok = p.lookup(kid)
+		p.mu.RUnlock()
+		if ok {
+			return k, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup без kid допустим, только если у издателя ровно один ключ
+func (p *OIDCProvider) lookup(kid string) (any, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	k, ok := p.keys[kid]
+	return k, ok
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (p *OIDCProvider) refreshKeys(ctx context.Context) error {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, p.client, p.jwksURI, &set); err != nil {
+		return fmt.Errorf("oidc jwks for %s failed: %w", p.name, err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		k, err := jwk.publicKey()
+		if err != nil {
+			// неизвестные типы ключей пропускаем, остальные остаются рабочими
+			continue
+		}
+		keys[jwk.Kid] = k
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysFetched = time.Now()
+	p.mu.Unlock()
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func isTrue(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	default:
+		return false
+	}
+}
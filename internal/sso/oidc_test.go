@@ -0,0 +1,227 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+const testClientID = "corpord"
+
+// fakeIssuer OIDC-издатель на httptest: discovery и JWKS с подменяемым набором ключей
+type fakeIssuer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PrivateKey
+	jwksCalls int
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	f := &fakeIssuer{keys: map[string]*rsa.PrivateKey{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:        f.URL,
+			AuthEndpoint:  f.URL + "/authorize",
+			TokenEndpoint: f.URL + "/token",
+			JWKSURI:       f.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.jwksCalls++
+
+		set := struct {
+			Keys []jsonWebKey `json:"keys"`
+		}{}
+		for kid, key := range f.keys {
+			set.Keys = append(set.Keys, jsonWebKey{
+				Kid: kid,
+				Kty: "RSA",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// rotate заменяет ключи издателя одним новым ключом kid
+func (f *fakeIssuer) rotate(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.mu.Lock()
+	f.keys = map[string]*rsa.PrivateKey{kid: key}
+	f.mu.Unlock()
+	return key
+}
+
+func (f *fakeIssuer) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jwksCalls
+}
+
+func (f *fakeIssuer) provider(t *testing.T) *OIDCProvider {
+	t.Helper()
+
+	p, err := NewOIDCProvider(context.Background(), OIDCConfig{
+		Name:       "corp",
+		Issuer:     f.URL,
+		ClientID:   testClientID,
+		HTTPClient: f.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// validClaims ID token, который провайдер должен принять с nonce "n-1"
+func (f *fakeIssuer) validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            f.URL,
+		"aud":            testClientID,
+		"sub":            "user-1",
+		"nonce":          "n-1",
+		"email":          "user@example.com",
+		"email_verified": true,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+}
+
+func idToken(t *testing.T, claims jwt.MapClaims, key *rsa.PrivateKey, kid string) *oauth2.Token {
+	t.Helper()
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = kid
+	raw, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return (&oauth2.Token{AccessToken: "access"}).WithExtra(map[string]any{"id_token": raw})
+}
+
+func TestOIDCVerifyIDToken(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	key := issuer.rotate(t, "k1")
+	p := issuer.provider(t)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		edit  func(jwt.MapClaims)
+		key   *rsa.PrivateKey
+		nonce string
+		want  error
+	}{
+		{name: "valid", nonce: "n-1"},
+		{name: "bad signature", key: other, nonce: "n-1", want: ErrInvalidIDToken},
+		{name: "wrong audience", edit: func(c jwt.MapClaims) { c["aud"] = "someone-else" }, nonce: "n-1", want: ErrInvalidIDToken},
+		{
+			name:  "several audiences without our azp",
+			edit:  func(c jwt.MapClaims) { c["aud"] = []string{testClientID, "other"}; c["azp"] = "other" },
+			nonce: "n-1",
+			want:  ErrInvalidIDToken,
+		},
+		{
+			name:  "several audiences with our azp",
+			edit:  func(c jwt.MapClaims) { c["aud"] = []string{testClientID, "other"}; c["azp"] = testClientID },
+			nonce: "n-1",
+		},
+		{name: "wrong issuer", edit: func(c jwt.MapClaims) { c["iss"] = "https://evil.example" }, nonce: "n-1", want: ErrInvalidIDToken},
+		{name: "expired", edit: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, nonce: "n-1", want: ErrInvalidIDToken},
+		{name: "nonce mismatch", nonce: "n-2", want: ErrNonceMismatch},
+		{name: "missing nonce", edit: func(c jwt.MapClaims) { delete(c, "nonce") }, nonce: "n-1", want: ErrNonceMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := issuer.validClaims()
+			if tt.edit != nil {
+				tt.edit(claims)
+			}
+			signer := key
+			if tt.key != nil {
+				signer = tt.key
+			}
+
+			info, err := p.GetUserInfo(context.Background(), idToken(t, claims, signer, "k1"), AuthParams{Nonce: tt.nonce})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (info.ProviderID != "user-1" || info.Email != "user@example.com" || !info.EmailVerified) {
+				t.Fatalf("unexpected user info: %+v", info)
+			}
+		})
+	}
+}
+
+func TestOIDCKeyRotation(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	issuer.rotate(t, "k1")
+	p := issuer.provider(t)
+
+	// издатель сменил ключ: новый kid подхватывается повторной загрузкой JWKS
+	key := issuer.rotate(t, "k2")
+	tok := idToken(t, issuer.validClaims(), key, "k2")
+
+	// сразу после загрузки JWKS не перечитывается, чтобы неизвестный kid не заваливал издателя запросами
+	if _, err := p.GetUserInfo(context.Background(), tok, AuthParams{Nonce: "n-1"}); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("err = %v, want %v before refresh interval", err, ErrInvalidIDToken)
+	}
+	if got := issuer.calls(); got != 1 {
+		t.Fatalf("jwks calls = %d, want 1", got)
+	}
+
+	p.mu.Lock()
+	p.keysFetched = time.Now().Add(-2 * jwksRefreshInterval)
+	p.mu.Unlock()
+
+	info, err := p.GetUserInfo(context.Background(), tok, AuthParams{Nonce: "n-1"})
+	if err != nil {
+		t.Fatalf("token signed with rotated key rejected: %v", err)
+	}
+	if info.ProviderID != "user-1" {
+		t.Fatalf("provider id = %q", info.ProviderID)
+	}
+	if got := issuer.calls(); got != 2 {
+		t.Fatalf("jwks calls = %d, want 2", got)
+	}
+
+	// старый ключ после ротации больше не принимается
+	old := idToken(t, issuer.validClaims(), key, "k1")
+	if _, err := p.GetUserInfo(context.Background(), old, AuthParams{Nonce: "n-1"}); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("err = %v, want %v for removed kid", err, ErrInvalidIDToken)
+	}
+}
@@ -5,13 +5,29 @@ import (
 	"golang.org/x/oauth2"
 )
 
+// AuthParams одноразовые параметры одного входа: state от CSRF,
+// nonce для ID token и PKCE code verifier. Провайдер использует те, что поддерживает
+type AuthParams struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+	DeviceID     string // VK ID возвращает device_id в callback и требует его при обмене кода
+}
+
+// Callback параметры, с которыми провайдер вернул пользователя
+type Callback struct {
+	Code     string
+	State    string
+	DeviceID string
+}
+
 type Provider interface {
 	// URL куда отправляем пользователя для авторизации
-	AuthURL(state string) string
+	AuthURL(params AuthParams) string
 
 	// обмен кода на токен
-	Exchange(ctx context.Context, code string) (*oauth2.Token, error)
+	Exchange(ctx context.Context, code string, params AuthParams) (*oauth2.Token, error)
 
 	// получение инфо о пользователе
-	GetUserInfo(ctx context.Context, token *oauth2.Token) (*UserInfo, error)
+	GetUserInfo(ctx context.Context, token *oauth2.Token, params AuthParams) (*UserInfo, error)
 }
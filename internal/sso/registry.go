@@ -1,9 +1,15 @@
 package sso
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrTelegramDisabled = errors.New("telegram login is disabled")
 
 type Registry struct {
 	providers map[string]Provider
+	telegram  *TelegramVerifier
 }
 
 func NewRegistry() *Registry {
@@ -23,3 +29,15 @@ func (r *Registry) Get(name string) (Provider, error) {
 	}
 	return p, nil
 }
+
+// RegisterTelegram включает вход через Telegram Login Widget
+func (r *Registry) RegisterTelegram(v *TelegramVerifier) {
+	r.telegram = v
+}
+
+func (r *Registry) Telegram() (*TelegramVerifier, error) {
+	if r.telegram == nil {
+		return nil, ErrTelegramDisabled
+	}
+	return r.telegram, nil
+}
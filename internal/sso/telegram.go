@@ -0,0 +1,107 @@
+package sso
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// telegramClockSkew допустимое расхождение часов с Telegram для auth_date из будущего
+const telegramClockSkew = time.Minute
+
+var (
+	ErrTelegramHash    = errors.New("telegram: invalid hash")
+	ErrTelegramExpired = errors.New("telegram: auth_date is too old")
+	ErrTelegramFuture  = errors.New("telegram: auth_date is in the future")
+)
+
+// TelegramAuthData поля, которые Telegram Login Widget передаёт фронтенду
+type TelegramAuthData struct {
+	ID        int64  `json:"id" binding:"required"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Username  string `json:"username"`
+	PhotoURL  string `json:"photo_url"`
+	AuthDate  int64  `json:"auth_date" binding:"required"`
+	Hash      string `json:"hash" binding:"required"`
+}
+
+// TelegramVerifier проверяет подпись виджета Telegram.
+// Это не OAuth2: фронтенд получает данные от виджета и отправляет их нам,
+// подлинность подтверждается HMAC-SHA256 с ключом SHA256(bot_token)
+type TelegramVerifier struct {
+	secret []byte
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+func NewTelegramVerifier(botToken string, maxAge time.Duration) *TelegramVerifier {
+	secret := sha256.Sum256([]byte(botToken))
+	return &TelegramVerifier{
+		secret: secret[:],
+		maxAge: maxAge,
+		now:    time.Now,
+	}
+}
+
+// Verify проверяет hash и свежесть auth_date и возвращает пользователя.
+// auth_date из будущего не принимается: иначе подписанные данные не устаревали бы дольше maxAge
+func (v *TelegramVerifier) Verify(data TelegramAuthData) (*UserInfo, error) {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(data.checkString()))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(data.Hash))) {
+		return nil, ErrTelegramHash
+	}
+
+	authDate := time.Unix(data.AuthDate, 0)
+	now := v.now()
+	if authDate.After(now.Add(telegramClockSkew)) {
+		return nil, ErrTelegramFuture
+	}
+	if v.maxAge > 0 && now.Sub(authDate) > v.maxAge {
+		return nil, ErrTelegramExpired
+	}
+
+	name := strings.TrimSpace(data.FirstName + " " + data.LastName)
+	if name == "" {
+		name = data.Username
+	}
+
+	return &UserInfo{
+		Provider:   "telegram",
+		ProviderID: strconv.FormatInt(data.ID, 10),
+		Name:       name,
+	}, nil
+}
+
+// checkString строки key=value всех переданных полей кроме hash, по алфавиту, через \n
+func (d TelegramAuthData) checkString() string {
+	fields := map[string]string{
+		"id":         strconv.FormatInt(d.ID, 10),
+		"auth_date":  strconv.FormatInt(d.AuthDate, 10),
+		"first_name": d.FirstName,
+		"last_name":  d.LastName,
+		"username":   d.Username,
+		"photo_url":  d.PhotoURL,
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k, v := range fields {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, k+"="+fields[k])
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,120 @@
+package sso
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testBotToken = "123456:test-bot-token"
+
+// signTelegram подписывает данные так же, как Telegram Login Widget
+func signTelegram(d TelegramAuthData) TelegramAuthData {
+	secret := sha256.Sum256([]byte(testBotToken))
+	mac := hmac.New(sha256.New, secret[:])
+	mac.Write([]byte(d.checkString()))
+	d.Hash = hex.EncodeToString(mac.Sum(nil))
+	return d
+}
+
+func TestTelegramVerify(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	payload := TelegramAuthData{
+		ID:        42,
+		FirstName: "Ivan",
+		LastName:  "Petrov",
+		Username:  "ivan",
+		AuthDate:  now.Add(-time.Minute).Unix(),
+	}
+
+	tests := []struct {
+		name string
+		data func() TelegramAuthData
+		want error
+	}{
+		{
+			name: "valid hash",
+			data: func() TelegramAuthData { return signTelegram(payload) },
+		},
+		{
+			name: "uppercase hash",
+			data: func() TelegramAuthData {
+				d := signTelegram(payload)
+				d.Hash = strings.ToUpper(d.Hash)
+				return d
+			},
+		},
+		{
+			name: "tampered id",
+			data: func() TelegramAuthData {
+				d := signTelegram(payload)
+				d.ID = 43
+				return d
+			},
+			want: ErrTelegramHash,
+		},
+		{
+			name: "tampered name",
+			data: func() TelegramAuthData {
+				d := signTelegram(payload)
+				d.FirstName = "Admin"
+				return d
+			},
+			want: ErrTelegramHash,
+		},
+		{
+			name: "added field",
+			data: func() TelegramAuthData {
+				d := signTelegram(payload)
+				d.PhotoURL = "https://t.me/i/userpic/1.jpg"
+				return d
+			},
+			want: ErrTelegramHash,
+		},
+		{
+			name: "stale data",
+			data: func() TelegramAuthData {
+				d := payload
+				d.AuthDate = now.Add(-25 * time.Hour).Unix()
+				return signTelegram(d)
+			},
+			want: ErrTelegramExpired,
+		},
+		{
+			name: "future data within clock skew",
+			data: func() TelegramAuthData {
+				d := payload
+				d.AuthDate = now.Add(telegramClockSkew / 2).Unix()
+				return signTelegram(d)
+			},
+		},
+		{
+			name: "future data",
+			data: func() TelegramAuthData {
+				d := payload
+				d.AuthDate = now.Add(time.Hour).Unix()
+				return signTelegram(d)
+			},
+			want: ErrTelegramFuture,
+		},
+	}
+
+	v := NewTelegramVerifier(testBotToken, 24*time.Hour)
+	v.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := v.Verify(tt.data())
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (info.ProviderID != "42" || info.Name != "Ivan Petrov") {
+				t.Fatalf("unexpected user info: %+v", info)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package sso
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// VK ID (OAuth 2.1): PKCE обязателен, а в callback приходит device_id,
+// который нужно передать при обмене кода
+var vkEndpoint = oauth2.Endpoint{
+	AuthURL:   "https://id.vk.com/authorize",
+	TokenURL:  "https://id.vk.com/oauth2/auth",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+const vkUserInfoURL = "https://id.vk.com/oauth2/user_info"
+
+type VKProvider struct {
+	cfg         *oauth2.Config
+	client      *http.Client
+	userInfoURL string
+}
+
+func NewVKProvider(clientID, clientSecret, redirectURL string) Provider {
+	return &VKProvider{
+		cfg: &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       []string{"email"},
+			Endpoint:     vkEndpoint,
+		},
+		client:      &http.Client{},
+		userInfoURL: vkUserInfoURL,
+	}
+}
+
+func (p *VKProvider) AuthURL(params AuthParams) string {
+	return p.cfg.AuthCodeURL(params.State, authCodeOptions(AuthParams{CodeVerifier: params.CodeVerifier})...)
+}
+
+func (p *VKProvider) Exchange(ctx context.Context, code string, params AuthParams) (*oauth2.Token, error) {
+	if params.DeviceID == "" {
+		return nil, fmt.Errorf("vk: device_id is required")
+	}
+
+	opts := append(exchangeOptions(params),
+		oauth2.SetAuthURLParam("device_id", params.DeviceID),
+		oauth2.SetAuthURLParam("state", params.State),
+	)
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	tok, err := p.cfg.Exchange(ctx, code, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	return tok, nil
+}
+
+func (p *VKProvider) GetUserInfo(ctx context.Context, tok *oauth2.Token, _ AuthParams) (*UserInfo, error) {
+	form := url.Values{
+		"client_id":    {p.cfg.ClientID},
+		"access_token": {tok.AccessToken},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.userInfoURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vk userinfo failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var data struct {
+		User struct {
+			UserID    string `json:"user_id"`
+			FirstName string `json:"first_name"`
+			LastName  string `json:"last_name"`
+			Email     string `json:"email"`
+		} `json:"user"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("decode vk userinfo failed: %w", err)
+	}
+	if data.Error != "" {
+		return nil, fmt.Errorf("vk userinfo failed: %s: %s", data.Error, data.ErrorDescription)
+	}
+	if data.User.UserID == "" {
+		return nil, fmt.Errorf("vk userinfo failed: empty user_id")
+	}
+
+	return &UserInfo{
+		Provider:   "vk",
+		ProviderID: data.User.UserID,
+		Email:      data.User.Email,
+		// VK не сообщает, подтверждён ли email, поэтому для автопривязки он не годится
+		EmailVerified: false,
+		Name:          strings.TrimSpace(data.User.FirstName + " " + data.User.LastName),
+	}, nil
+}
@@ -0,0 +1,82 @@
+package sso
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestVKGetUserInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    *UserInfo
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			payload: `{"user":{"user_id":"1001","first_name":"Ivan","last_name":"Petrov","email":"ivan@example.com"}}`,
+			want:    &UserInfo{Provider: "vk", ProviderID: "1001", Email: "ivan@example.com", Name: "Ivan Petrov"},
+		},
+		{
+			name:    "provider error",
+			payload: `{"error":"invalid_token","error_description":"token expired"}`,
+			wantErr: "invalid_token: token expired",
+		},
+		{
+			name:    "empty user_id",
+			payload: `{"user":{"first_name":"Ivan"}}`,
+			wantErr: "empty user_id",
+		},
+		{
+			name:    "malformed payload",
+			payload: `{"user":`,
+			wantErr: "decode vk userinfo failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("parse form: %v", err)
+				}
+				if r.PostForm.Get("client_id") != testClientID || r.PostForm.Get("access_token") != "access" {
+					t.Errorf("unexpected form: %v", r.PostForm)
+				}
+				_, _ = w.Write([]byte(tt.payload))
+			}))
+			defer srv.Close()
+
+			p := &VKProvider{
+				cfg:         &oauth2.Config{ClientID: testClientID},
+				client:      srv.Client(),
+				userInfoURL: srv.URL,
+			}
+			info, err := p.GetUserInfo(context.Background(), &oauth2.Token{AccessToken: "access"}, AuthParams{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *info != *tt.want {
+				t.Fatalf("user info = %+v, want %+v", info, tt.want)
+			}
+		})
+	}
+}
+
+func TestVKExchangeRequiresDeviceID(t *testing.T) {
+	p := NewVKProvider(testClientID, "secret", "https://example.com/callback")
+	if _, err := p.Exchange(context.Background(), "code", AuthParams{State: "s"}); err == nil {
+		t.Fatal("exchange without device_id succeeded")
+	}
+}
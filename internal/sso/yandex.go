@@ -35,18 +35,18 @@ func (p *YandexProvider) Name() string {
 	return "yandex"
 }
 
-// Ссылка на авторизацию
-func (p *YandexProvider) AuthURL(state string) string {
-	return p.cfg.AuthCodeURL(state)
+// Ссылка на авторизацию. Яндекс OAuth не выдаёт ID token, поэтому nonce не передаём
+func (p *YandexProvider) AuthURL(params AuthParams) string {
+	return p.cfg.AuthCodeURL(params.State, authCodeOptions(AuthParams{CodeVerifier: params.CodeVerifier})...)
 }
 
 // Обмен code -> token
-func (p *YandexProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
-	return p.cfg.Exchange(ctx, code)
+func (p *YandexProvider) Exchange(ctx context.Context, code string, params AuthParams) (*oauth2.Token, error) {
+	return p.cfg.Exchange(ctx, code, exchangeOptions(params)...)
 }
 
 // Запрос user info
-func (p *YandexProvider) GetUserInfo(ctx context.Context, tok *oauth2.Token) (*UserInfo, error) {
+func (p *YandexProvider) GetUserInfo(ctx context.Context, tok *oauth2.Token, _ AuthParams) (*UserInfo, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", "https://login.yandex.ru/info?format=json", nil)
 	if err != nil {
 		return nil, err
@@ -33,6 +33,11 @@ type GenerateParams struct {
 	ProviderID string
 	AMR        []string
 	AuthTime   time.Time
+	TTL        time.Duration // overrides the configured access token lifetime when set
+	// admin acting as the user, 0 for a regular session
+	ImpersonatorID int
+	// preferred language of the user, empty when not set
+	Language string
 }
 
 // Create token manager
@@ -44,17 +49,23 @@ func NewManager(cfg *config.JWT) Manager {
 
 // Generate new JWT access token
 func (m *manager) Generate(params GenerateParams) (string, error) {
-	expiresAt := time.Now().Add(m.cfg.AccessTokenTTL)
+	ttl := m.cfg.AccessTokenTTL
+	if params.TTL > 0 {
+		ttl = params.TTL
+	}
+	expiresAt := time.Now().Add(ttl)
 
 	claims := model.NewClaims(model.NewClaimsParams{
-		UserID:     params.UserID,
-		Email:      params.Email,
-		Role:       params.Role,
-		Provider:   params.Provider,
-		ProviderID: params.ProviderID,
-		ExpiresAt:  expiresAt,
-		AMR:        params.AMR,
-		AuthTime:   params.AuthTime,
+		UserID:         params.UserID,
+		Email:          params.Email,
+		Role:           params.Role,
+		Provider:       params.Provider,
+		ProviderID:     params.ProviderID,
+		ExpiresAt:      expiresAt,
+		AMR:            params.AMR,
+		AuthTime:       params.AuthTime,
+		ImpersonatorID: params.ImpersonatorID,
+		Language:       params.Language,
 	})
 
 	token := jwt.NewWithClaims(m.mapSigningMethod(), claims)
@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"corpord-api/pkg/phone"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Буквы госномера — только те, что совпадают по написанию в кириллице и латинице
+var plateRe = regexp.MustCompile(`^[АВЕКМНОРСТУХ]\d{3}[АВЕКМНОРСТУХ]{2}\d{2,3}$`)
+
+var latinPlate = strings.NewReplacer(
+	"A", "А", "B", "В", "E", "Е", "K", "К", "M", "М", "H", "Н",
+	"O", "О", "P", "Р", "C", "С", "T", "Т", "Y", "У", "X", "Х",
+)
+
+// NormalizePlate приводит госномер к верхнему регистру и кириллице: a123bc77 -> А123ВС77
+func NormalizePlate(s string) string {
+	return latinPlate.Replace(strings.ToUpper(strings.TrimSpace(s)))
+}
+
+func plate(fl validator.FieldLevel) bool {
+	s := NormalizePlate(fl.Field().String())
+	if !plateRe.MatchString(s) {
+		return false
+	}
+	// номер 000 не выдаётся
+	digits := string([]rune(s)[1:4])
+	return digits != "000"
+}
+
+func latitude(fl validator.FieldLevel) bool {
+	return inRange(fl.Field(), 90)
+}
+
+func longitude(fl validator.FieldLevel) bool {
+	return inRange(fl.Field(), 180)
+}
+
+func inRange(v reflect.Value, limit float64) bool {
+	var f float64
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f = v.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(v.Int())
+	default:
+		return false
+	}
+	return !math.IsNaN(f) && f >= -limit && f <= limit
+}
+
+func phoneE164(fl validator.FieldLevel) bool {
+	return fl.Field().Kind() == reflect.String && phone.IsE164(fl.Field().String())
+}
+
+// after сравнивает время с полем той же структуры. Если второе поле не задано
+// (nil в DTO обновления), сравнивать не с чем и правило выполняется
+func after(fl validator.FieldLevel) bool {
+	t, ok := fl.Field().Interface().(time.Time)
+	if !ok {
+		return false
+	}
+
+	other, kind, _, found := fl.GetStructFieldOK2()
+	if !found {
+		return false
+	}
+	if kind == reflect.Ptr || kind == reflect.Invalid {
+		return true
+	}
+	start, ok := other.Interface().(time.Time)
+	if !ok {
+		return false
+	}
+	if start.IsZero() {
+		return true
+	}
+	return t.After(start)
+}
+
+func price(fl validator.FieldLevel) bool {
+	v := fl.Field()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() > 0
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) || f <= 0 {
+			return false
+		}
+		cents := f * 100
+		return math.Abs(cents-math.Round(cents)) < 1e-6
+	}
+	return false
+}
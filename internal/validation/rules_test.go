@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	Setup(v)
+	return v
+}
+
+func TestFieldRules(t *testing.T) {
+	v := newValidator()
+
+	tests := []struct {
+		tag   string
+		value any
+		valid bool
+	}{
+		{TagPlate, "А123ВС77", true},
+		{TagPlate, "a123bc777", true},
+		{TagPlate, " м001мм99 ", true},
+		{TagPlate, "А000ВС77", false},
+		{TagPlate, "Б123ВС77", false},
+		{TagPlate, "А123ВС7", false},
+		{TagPlate, "", false},
+
+		{TagLat, 55.75, true},
+		{TagLat, -90.0, true},
+		{TagLat, 90, true},
+		{TagLat, 90.01, false},
+		{TagLat, math.NaN(), false},
+		{TagLat, "55.75", false},
+
+		{TagLon, 37.62, true},
+		{TagLon, -180.0, true},
+		{TagLon, 180.5, false},
+		{TagLon, math.NaN(), false},
+
+		{TagPhone, "+79991234567", true},
+		{TagPhone, "89991234567", false},
+		{TagPhone, "+0123456789", false},
+		{TagPhone, "+1234567", false},
+		{TagPhone, "+7 999 123-45-67", false},
+		{TagPhone, 79991234567, false},
+
+		{TagPrice, 150, true},
+		{TagPrice, 99.99, true},
+		{TagPrice, 0.1, true},
+		{TagPrice, 0, false},
+		{TagPrice, -5, false},
+		{TagPrice, 10.001, false},
+		{TagPrice, math.Inf(1), false},
+		{TagPrice, math.NaN(), false},
+		{TagPrice, "100", false},
+	}
+
+	for _, tt := range tests {
+		err := v.Var(tt.value, tt.tag)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s(%#v): err = %v, want valid = %v", tt.tag, tt.value, err, tt.valid)
+		}
+	}
+}
+
+func TestAfter(t *testing.T) {
+	v := newValidator()
+	start := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+
+	type trip struct {
+		StartTime time.Time `json:"start_time"`
+		EndTime   time.Time `json:"end_time" binding:"after=StartTime"`
+	}
+	type tripPatch struct {
+		StartTime *time.Time `json:"start_time"`
+		EndTime   time.Time  `json:"end_time" binding:"after=StartTime"`
+	}
+	type missingField struct {
+		EndTime time.Time `json:"end_time" binding:"after=StartTime"`
+	}
+	type wrongType struct {
+		StartTime time.Time `json:"start_time"`
+		EndTime   string    `json:"end_time" binding:"after=StartTime"`
+	}
+
+	tests := []struct {
+		name  string
+		value any
+		valid bool
+	}{
+		{"later", trip{StartTime: start, EndTime: start.Add(time.Hour)}, true},
+		{"equal", trip{StartTime: start, EndTime: start}, false},
+		{"earlier", trip{StartTime: start, EndTime: start.Add(-time.Hour)}, false},
+		{"zero start", trip{EndTime: start}, true},
+		{"nil start in patch", tripPatch{EndTime: start}, true},
+		{"missing field", missingField{EndTime: start}, false},
+		{"not a time", wrongType{StartTime: start, EndTime: "tomorrow"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(tt.value)
+			if (err == nil) != tt.valid {
+				t.Fatalf("err = %v, want valid = %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestJSONFieldNames(t *testing.T) {
+	v := newValidator()
+
+	type input struct {
+		Plate string `json:"plate_number" binding:"plate"`
+	}
+	err := v.Struct(input{Plate: "bad"})
+	errs, ok := err.(validator.ValidationErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("err = %v, want one validation error", err)
+	}
+	if errs[0].Field() != "plate_number" {
+		t.Fatalf("field = %q, want plate_number", errs[0].Field())
+	}
+}
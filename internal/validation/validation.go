@@ -0,0 +1,72 @@
+// Package validation настраивает валидатор, который gin использует при ShouldBind*:
+// имена полей в ошибках берутся из json-тегов, добавляются доменные правила.
+// Правила задаются тегом binding в DTO пакета model
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Доменные правила
+const (
+	TagPlate = "plate" // госномер автобуса: А123ВС77
+	TagLat   = "lat"   // широта от -90 до 90
+	TagLon   = "lon"   // долгота от -180 до 180
+	TagPhone = "phone" // телефон в формате E.164
+	TagAfter = "after" // время строго позже поля из параметра: after=StartTime
+	TagPrice = "price" // цена больше нуля, не больше двух знаков после запятой
+)
+
+var rules = map[string]validator.Func{
+	TagPlate: plate,
+	TagLat:   latitude,
+	TagLon:   longitude,
+	TagPhone: phoneE164,
+	TagAfter: after,
+	TagPrice: price,
+}
+
+// Register настраивает валидатор gin. Вызывается до обработки первого запроса:
+// валидатор кеширует описание структур
+func Register() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	Setup(v)
+}
+
+// Setup регистрирует имена полей и правила в v
+func Setup(v *validator.Validate) {
+	v.RegisterTagNameFunc(jsonName)
+	for tag, fn := range rules {
+		// ошибка возможна только при пустом теге или nil-функции
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// jsonName называет поле так, как его видит клиент: по json-, затем form-тегу
+func jsonName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// Struct проверяет v по тегам binding тем же валидатором, что и тела запросов.
+// Нужен там, где DTO собирается не из тела запроса, например после merge patch
+func Struct(v any) error {
+	return binding.Validator.ValidateStruct(v)
+}
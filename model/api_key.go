@@ -0,0 +1,83 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProviderAPIKey значение Claims.Provider для запросов, аутентифицированных API-ключом
+const ProviderAPIKey = "api_key"
+
+// APIKey ключ доступа к API для интеграций. Хранится только хеш ключа
+type APIKey struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     int        `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     string     `db:"scopes"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedBy  *int       `db:"created_by"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// ScopeList возвращает права, которыми ограничен ключ
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// Active ключ не отозван и не истёк
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyCreate параметры нового ключа
+type APIKeyCreate struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes"` // Коды прав; пусто — все права роли владельца
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyResponse ключ без секрета
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     int        `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyCreated ответ при создании или ротации: секрет показывается один раз
+type APIKeyCreated struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// ToResponse преобразует APIKey в APIKeyResponse
+func (k *APIKey) ToResponse() *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// ServiceAccountCreate данные сервисного аккаунта
+type ServiceAccountCreate struct {
+	Name   string `json:"name" binding:"required,max=255"`
+	RoleID int    `json:"role_id" binding:"required,gt=0"`
+}
@@ -0,0 +1,26 @@
+package model
+
+// BulkUpdate элемент пакетного обновления: ID записи и изменяемые поля
+type BulkUpdate[T any] struct {
+	ID   int `json:"id" binding:"required,gt=0"`
+	Data T   `json:"data"`
+}
+
+// BulkItem результат элемента пакетного запроса. Index — номер элемента в запросе
+type BulkItem struct {
+	Index int `json:"index"`
+	ID    int `json:"id"`
+}
+
+// BulkResult результат пакетного запроса: по элементу на каждый элемент запроса в том же порядке
+type BulkResult struct {
+	Items []BulkItem `json:"items"`
+}
+
+func NewBulkResult(ids []int) *BulkResult {
+	items := make([]BulkItem, len(ids))
+	for i, id := range ids {
+		items[i] = BulkItem{Index: i, ID: id}
+	}
+	return &BulkResult{Items: items}
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -21,58 +22,94 @@ type Bus struct {
 }
 
 type ViewBus struct {
-	ID           int    `json:"id" binding:"required" db:"id"`
-	LicensePlate string `json:"license_plate" binding:"required" db:"license_plate"`
-	Brand        string `json:"brand" binding:"required" db:"brand"`
-	Capacity     int    `json:"capacity" binding:"required" db:"capacity"`
-	Category     string `json:"category" db:"category_name"`
-	Status       string `json:"status" db:"status_name"`
+	ID           int        `json:"id" binding:"required" db:"id"`
+	LicensePlate string     `json:"license_plate" binding:"required" db:"license_plate"`
+	Brand        string     `json:"brand" binding:"required" db:"brand"`
+	Capacity     int        `json:"capacity" binding:"required" db:"capacity"`
+	Category     string     `json:"category" db:"category_name"`
+	Status       string     `json:"status" db:"status_name"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type BusCreate struct {
-	LicensePlate string `json:"license_plate"`
-	Brand        string `json:"brand"`
-	Capacity     int    `json:"capacity"`
-	CategoryID   int    `json:"category_id"`
-	StatusID     int    `json:"status_id"`
+	LicensePlate string `json:"license_plate" binding:"required,plate"`
+	Brand        string `json:"brand" binding:"required,max=100"`
+	Capacity     int    `json:"capacity" binding:"required,gt=0,lte=500"`
+	CategoryID   int    `json:"category_id" binding:"required,gt=0"`
+	StatusID     int    `json:"status_id" binding:"required,gt=0"`
 }
 
 type BusUpdate struct {
 	ID           int     `json:"id,omitempty" db:"id"`
-	LicensePlate *string `json:"license_plate"`
-	Brand        *string `json:"brand"`
-	Capacity     *int    `json:"capacity"`
-	CategoryID   *int    `json:"category_id"`
-	StatusID     *int    `json:"status_id"`
+	LicensePlate *string `json:"license_plate" binding:"omitnil,plate"`
+	Brand        *string `json:"brand" binding:"omitnil,min=1,max=100"`
+	Capacity     *int    `json:"capacity" binding:"omitnil,gt=0,lte=500"`
+	CategoryID   *int    `json:"category_id" binding:"omitnil,gt=0"`
+	StatusID     *int    `json:"status_id" binding:"omitnil,gt=0"`
+}
+
+// BusPatch документ автобуса для PATCH (JSON Merge Patch).
+// ID и UpdatedAt только для чтения: патч их не меняет
+type BusPatch struct {
+	ID           int       `json:"id" db:"id"`
+	LicensePlate string    `json:"license_plate" db:"license_plate" binding:"required,plate"`
+	Brand        string    `json:"brand" db:"brand" binding:"required,max=100"`
+	Capacity     int       `json:"capacity" db:"capacity" binding:"required,gt=0,lte=500"`
+	CategoryID   int       `json:"category_id" db:"category_id" binding:"required,gt=0"`
+	StatusID     int       `json:"status_id" db:"status_id" binding:"required,gt=0"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type BusCategory struct {
-	ID   int    `json:"id" db:"id"`
-	Name string `json:"name" db:"name"`
+	ID        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name" binding:"required,max=100"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 func (bc *BusCategory) Validate() error {
-	if &bc.ID == nil && &bc.Name == nil {
-		return errors.New("at least one field must be updated")
+	if strings.TrimSpace(bc.Name) == "" {
+		return errors.New("название не может быть пустым")
 	}
 	return nil
 }
 
 type BusStatus struct {
-	ID   int    `json:"id" db:"id"`
-	Name string `json:"name" db:"name"`
+	ID        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name" binding:"required,max=100"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 func (bs *BusStatus) Validate() error {
-	if &bs.ID == nil && &bs.Name == nil {
-		return errors.New("at least one field must be updated")
+	if strings.TrimSpace(bs.Name) == "" {
+		return errors.New("название не может быть пустым")
 	}
 	return nil
 }
 
 func (b *BusUpdate) Validate() error {
 	if b.LicensePlate == nil && b.Brand == nil && b.Capacity == nil && b.CategoryID == nil && b.StatusID == nil {
-		return errors.New("at least one field must be updated")
+		return errors.New("не указаны поля для обновления")
 	}
 	return nil
 }
+
+func (b *BusUpdate) ToMap() map[string]interface{} {
+	result := make(map[string]interface{})
+	if b.LicensePlate != nil {
+		result["license_plate"] = *b.LicensePlate
+	}
+	if b.Brand != nil {
+		result["brand"] = *b.Brand
+	}
+	if b.Capacity != nil {
+		result["capacity"] = *b.Capacity
+	}
+	if b.CategoryID != nil {
+		result["category_id"] = *b.CategoryID
+	}
+	if b.StatusID != nil {
+		result["status_id"] = *b.StatusID
+	}
+	return result
+}
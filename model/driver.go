@@ -1,6 +1,10 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"strings"
+	"time"
+)
 
 type Driver struct {
 	ID          int          `json:"id" db:"id"`
@@ -21,31 +25,33 @@ type DriverView struct {
 }
 
 type DriverStatus struct {
-	ID   int    `json:"id" db:"id"`
-	Name string `json:"name" db:"name"`
+	ID        int        `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name" binding:"required,max=100"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type DriverOutput struct {
-	ID          int    `json:"-,omitempty" db:"id"`
-	FirstName   string `json:"first_name" db:"first_name"`
-	LastName    string `json:"last_name" db:"last_name"`
-	MiddleName  string `json:"middle_name" db:"middle_name"`
-	PhoneNumber string `json:"phone_number" db:"phone_number"`
-	Status      string `json:"status" db:"driver_status"`
+	ID          int        `json:"-,omitempty" db:"id"`
+	FirstName   string     `json:"first_name" db:"first_name"`
+	LastName    string     `json:"last_name" db:"last_name"`
+	MiddleName  string     `json:"middle_name" db:"middle_name"`
+	PhoneNumber string     `json:"phone_number" db:"phone_number"`
+	Status      string     `json:"status" db:"driver_status"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type DriverInput struct {
 	ID          int    `json:"-,omitempty" db:"id"`
-	FirstName   string `json:"first_name" db:"first_name"`
-	LastName    string `json:"last_name" db:"last_name"`
-	MiddleName  string `json:"middle_name" db:"middle_name"`
-	PhoneNumber string `json:"phone_number" db:"phone_number"`
-	Status      int    `json:"status" db:"status"`
+	FirstName   string `json:"first_name" form:"first_name" db:"first_name" binding:"required,max=100"`
+	LastName    string `json:"last_name" form:"last_name" db:"last_name" binding:"required,max=100"`
+	MiddleName  string `json:"middle_name" form:"middle_name" db:"middle_name" binding:"max=100"`
+	PhoneNumber string `json:"phone_number" form:"phone_number" db:"phone_number" binding:"required,phone"`
+	Status      int    `json:"status" form:"status" db:"status" binding:"required,gt=0"`
 }
 
 func (ds *DriverStatus) Validate() error {
-	if &ds.ID == nil && &ds.Name == nil {
-		return errors.New("id or name is required")
+	if strings.TrimSpace(ds.Name) == "" {
+		return errors.New("название не может быть пустым")
 	}
 	return nil
 }
@@ -8,37 +8,51 @@ import (
 
 // Claims represents the JWT claims structure (ready for SSO)
 type Claims struct {
-	UserID     int       `json:"user_id"`
-	Email      string    `json:"email"`
-	Role       string    `json:"role"`
-	Provider   string    `json:"provider"`    // "local", "google", "yandex", "azure", etc
-	ProviderID string    `json:"provider_id"` // sub claim from SSO provider.go
-	AuthTime   time.Time `json:"auth_time"`   // when user authenticated
-	AMR        []string  `json:"amr"`         // authentication methods: pwd, otp, mfa, federated
+	UserID         int       `json:"user_id"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+	Provider       string    `json:"provider"`                  // "local", "google", "yandex", "azure", etc
+	ProviderID     string    `json:"provider_id"`               // sub claim from SSO provider.go
+	AuthTime       time.Time `json:"auth_time"`                 // when user authenticated
+	AMR            []string  `json:"amr"`                       // authentication methods: pwd, otp, mfa, federated
+	Scopes         []string  `json:"scopes,omitempty"`          // permissions an API key is limited to, empty means all role permissions
+	ImpersonatorID int       `json:"impersonator_id,omitempty"` // admin acting as the user, 0 for a regular session
+	Language       string    `json:"lang,omitempty"`            // preferred language from the user profile
 	jwt.RegisteredClaims
 }
 
+// Impersonated reports whether the token was issued to an admin acting as the user
+func (c *Claims) Impersonated() bool {
+	return c.ImpersonatorID != 0
+}
+
 type NewClaimsParams struct {
-	UserID     int
-	Email      string
-	Role       string
-	Provider   string
-	ProviderID string
-	ExpiresAt  time.Time
-	AMR        []string
-	AuthTime   time.Time
+	UserID         int
+	Email          string
+	Role           string
+	Provider       string
+	ProviderID     string
+	ExpiresAt      time.Time
+	AMR            []string
+	AuthTime       time.Time
+	Scopes         []string
+	ImpersonatorID int
+	Language       string
 }
 
 // NewClaims creates a new Claims instance with the provided parameters.
 func NewClaims(params NewClaimsParams) *Claims {
 	return &Claims{
-		UserID:     params.UserID,
-		Email:      params.Email,
-		Role:       params.Role,
-		Provider:   params.Provider,
-		ProviderID: params.ProviderID,
-		AMR:        params.AMR,
-		AuthTime:   params.AuthTime,
+		UserID:         params.UserID,
+		Email:          params.Email,
+		Role:           params.Role,
+		Provider:       params.Provider,
+		ProviderID:     params.ProviderID,
+		AMR:            params.AMR,
+		AuthTime:       params.AuthTime,
+		Scopes:         params.Scopes,
+		ImpersonatorID: params.ImpersonatorID,
+		Language:       params.Language,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Subject:   params.ProviderID,
 			Audience:  []string{"corpord-web"},
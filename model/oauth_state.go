@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// OAuthState данные начатого входа через SSO, хранятся на сервере до callback
+type OAuthState struct {
+	Provider     string    `json:"provider"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ReturnURL    string    `json:"return_url"`
+	LinkUserID   int       `json:"link_user_id,omitempty"` // не 0 — привязка identity к уже вошедшему пользователю
+	BindingHash  string    `json:"binding_hash"`           // хеш значения cookie браузера, начавшего вход
+	CreatedAt    time.Time `json:"created_at"`
+}
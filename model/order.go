@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Order заказ пользователя
+type Order struct {
+	ID            int          `db:"id" json:"id"`
+	OrderNumber   *string      `db:"order_number" json:"order_number"`
+	UserID        *int         `db:"user_id" json:"-"`
+	ContactName   string       `db:"contact_name" json:"contact_name"`
+	ContactPhone  string       `db:"contact_phone" json:"contact_phone"`
+	ContactEmail  *string      `db:"contact_email" json:"contact_email,omitempty"`
+	Status        string       `db:"status" json:"status"`
+	TotalAmount   float64      `db:"total_amount" json:"total_amount"`
+	PaymentMethod *string      `db:"payment_method" json:"payment_method,omitempty"`
+	PaymentStatus *string      `db:"payment_status" json:"payment_status,omitempty"`
+	Notes         *string      `db:"notes" json:"notes,omitempty"`
+	CreatedAt     time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time    `db:"updated_at" json:"updated_at"`
+	Items         []*OrderItem `db:"-" json:"items"`
+}
+
+// OrderItem билет в заказе
+type OrderItem struct {
+	ID                      int       `db:"id" json:"id"`
+	OrderID                 int       `db:"order_id" json:"-"`
+	TripID                  int       `db:"trip_id" json:"trip_id"`
+	DepartureStopID         int       `db:"departure_stop_id" json:"departure_stop_id"`
+	ArrivalStopID           int       `db:"arrival_stop_id" json:"arrival_stop_id"`
+	PassengerName           string    `db:"passenger_name" json:"passenger_name"`
+	PassengerDocumentNumber *string   `db:"passenger_document_number" json:"passenger_document_number,omitempty"`
+	SeatNumber              *string   `db:"seat_number" json:"seat_number,omitempty"`
+	Price                   float64   `db:"price" json:"price"`
+	CreatedAt               time.Time `db:"created_at" json:"created_at"`
+}
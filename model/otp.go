@@ -0,0 +1,19 @@
+package model
+
+// PhoneCodeRequest запрос одноразового кода на телефон
+type PhoneCodeRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+// PhoneCodeResponse сведения об отправленном коде
+type PhoneCodeResponse struct {
+	Phone     string `json:"phone"`      // Номер в формате E.164, на который отправлен код
+	ExpiresIn int    `json:"expires_in"` // Через сколько секунд код перестанет действовать
+	ResendIn  int    `json:"resend_in"`  // Через сколько секунд можно запросить код повторно
+}
+
+// PhoneLoginRequest вход по номеру телефона и коду из SMS
+type PhoneLoginRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Типы документов пассажира
+const (
+	DocumentPassportRF       = "passport_rf"
+	DocumentBirthCertificate = "birth_certificate"
+	DocumentForeignPassport  = "foreign_passport"
+	DocumentInternational    = "international_passport"
+)
+
+// BirthDateLayout формат даты рождения в API
+const BirthDateLayout = "2006-01-02"
+
+// Passenger сохранённый пассажир. DocumentNumber в базе зашифрован
+type Passenger struct {
+	ID             int       `db:"id"`
+	UserID         int       `db:"user_id"`
+	FirstName      string    `db:"first_name"`
+	LastName       string    `db:"last_name"`
+	MiddleName     *string   `db:"middle_name"`
+	BirthDate      time.Time `db:"birth_date"`
+	DocumentType   string    `db:"document_type"`
+	DocumentNumber string    `db:"document_number"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// FullName фамилия, имя и отчество через пробел — в таком виде имя попадает в order_items.passenger_name
+func (p *Passenger) FullName() string {
+	parts := []string{p.LastName, p.FirstName}
+	if p.MiddleName != nil && *p.MiddleName != "" {
+		parts = append(parts, *p.MiddleName)
+	}
+	return strings.Join(parts, " ")
+}
+
+// PassengerCreate данные нового пассажира
+type PassengerCreate struct {
+	FirstName      string  `json:"first_name" binding:"required,max=100"`
+	LastName       string  `json:"last_name" binding:"required,max=100"`
+	MiddleName     *string `json:"middle_name,omitempty" binding:"omitempty,max=100"`
+	BirthDate      string  `json:"birth_date" binding:"required,datetime=2006-01-02" example:"1990-05-17"`
+	DocumentType   string  `json:"document_type" binding:"required,oneof=passport_rf birth_certificate foreign_passport international_passport"`
+	DocumentNumber string  `json:"document_number" binding:"required,max=50"`
+}
+
+// PassengerUpdate изменяемые поля пассажира
+type PassengerUpdate struct {
+	FirstName      *string `json:"first_name,omitempty" binding:"omitempty,min=1,max=100"`
+	LastName       *string `json:"last_name,omitempty" binding:"omitempty,min=1,max=100"`
+	MiddleName     *string `json:"middle_name,omitempty" binding:"omitempty,max=100"`
+	BirthDate      *string `json:"birth_date,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	DocumentType   *string `json:"document_type,omitempty" binding:"omitempty,oneof=passport_rf birth_certificate foreign_passport international_passport"`
+	DocumentNumber *string `json:"document_number,omitempty" binding:"omitempty,max=50"`
+}
+
+// PassengerResponse пассажир с замаскированным номером документа
+type PassengerResponse struct {
+	ID             int       `json:"id"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	MiddleName     *string   `json:"middle_name,omitempty"`
+	BirthDate      string    `json:"birth_date"`
+	DocumentType   string    `json:"document_type"`
+	DocumentNumber string    `json:"document_number" example:"******7890"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PassengerDocument данные пассажира для оформления заказа с расшифрованным документом
+type PassengerDocument struct {
+	PassengerID    int
+	Name           string
+	DocumentType   string
+	DocumentNumber string
+}
+
+// ProfileUpdate изменяемые поля профиля текущего пользователя
+type ProfileUpdate struct {
+	Name              *string `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	PreferredLanguage *string `json:"preferred_language,omitempty" binding:"omitempty,oneof=ru en"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// AnonymizedName подставляется вместо имён в записях, которые нельзя удалить (заказы, бухгалтерия)
+const AnonymizedName = "[удалено]"
+
+// ErasureResponse срок, после которого персональные данные будут обезличены
+type ErasureResponse struct {
+	RequestedAt time.Time `json:"requested_at"`
+	EraseAfter  time.Time `json:"erase_after"`
+}
+
+// ExportIdentity привязанный внешний аккаунт в выгрузке персональных данных
+type ExportIdentity struct {
+	Provider   string    `json:"provider"`
+	ProviderID string    `json:"provider_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ExportSession сессия (refresh токен) в выгрузке персональных данных
+type ExportSession struct {
+	ID        string    `json:"id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Revoked   bool      `json:"revoked"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
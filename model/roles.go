@@ -1,13 +1,58 @@
 package model
 
+import "time"
+
 // User roles
 const (
 	RoleAdmin = "admin"
 	RoleUser  = "user"
 )
 
-// ValidRoles is a map of all valid roles for validation
-var ValidRoles = map[string]bool{
-	RoleAdmin: true,
-	RoleUser:  true,
+// Permissions. Набор прав хранится в таблице permissions, здесь — коды, которые проверяет код
+const (
+	PermUsersRead        = "users:read"
+	PermUsersManage      = "users:manage"
+	PermUsersImpersonate = "users:impersonate"
+	PermRolesManage      = "roles:manage"
+	PermBusesWrite       = "buses:write"
+	PermDriversWrite     = "drivers:write"
+	PermStopsWrite       = "stops:write"
+	PermTripsWrite       = "trips:write"
+	PermOrdersRead       = "orders:read"
+	PermOrdersRefund     = "orders:refund"
+	PermAPIKeysManage    = "api_keys:manage"
+)
+
+// Role роль пользователя и её права
+type Role struct {
+	ID          int       `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	Permissions []string  `json:"permissions" db:"-"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Permission право доступа
+type Permission struct {
+	ID          int     `json:"id" db:"id"`
+	Code        string  `json:"code" db:"code"`
+	Description *string `json:"description,omitempty" db:"description"`
+}
+
+// RoleCreate данные для создания роли
+type RoleCreate struct {
+	Name        string   `json:"name" binding:"required,max=50"`
+	Description *string  `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// RolePermissionsUpdate полный список прав роли
+type RolePermissionsUpdate struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// UserRoleUpdate назначение роли пользователю
+type UserRoleUpdate struct {
+	RoleID int `json:"role_id" binding:"required,gt=0"`
 }
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Типы событий безопасности
+const (
+	SecurityEventLoginSuccess    = "login_success"
+	SecurityEventLoginFailed     = "login_failed"
+	SecurityEventLoginLocked     = "login_locked"
+	SecurityEventAccountUnlocked = "account_unlocked"
+	SecurityEventOTPSent         = "otp_sent"
+	SecurityEventAPIKeyCreated   = "api_key_created"
+	SecurityEventAPIKeyRotated   = "api_key_rotated"
+	SecurityEventAPIKeyRevoked   = "api_key_revoked"
+	SecurityEventDataExported    = "data_exported"
+	SecurityEventErasureRequest  = "erasure_requested"
+	SecurityEventErasureCancel   = "erasure_cancelled"
+	SecurityEventUserAnonymized  = "user_anonymized"
+	SecurityEventUserBlocked     = "user_blocked"
+	SecurityEventUserUnblocked   = "user_unblocked"
+	SecurityEventImpersonation   = "impersonation_started"
+)
+
+// SecurityEvent запись журнала событий безопасности
+type SecurityEvent struct {
+	ID        int64     `db:"id"`
+	EventType string    `db:"event_type"`
+	UserID    *int      `db:"user_id"`
+	ActorID   *int      `db:"actor_id"` // кто выполнил действие (например, администратор)
+	Email     *string   `db:"email"`
+	IP        *string   `db:"ip"`
+	UserAgent *string   `db:"user_agent"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -6,26 +6,38 @@ import (
 )
 
 type Stop struct {
-	ID        int       `json:"id,omitempty" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	Address   string    `json:"address" db:"address"`
-	Latitude  float64   `json:"latitude" db:"latitude"`
-	Longitude float64   `json:"longitude" db:"longitude"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID        int        `json:"id,omitempty" db:"id"`
+	Name      string     `json:"name" db:"name" binding:"required,max=200"`
+	Address   string     `json:"address" db:"address" binding:"required,max=500"`
+	Latitude  float64    `json:"latitude" db:"latitude" binding:"lat"`
+	Longitude float64    `json:"longitude" db:"longitude" binding:"lon"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 type StopUpdate struct {
 	ID        int      `json:"-,omitempty" db:"id"`
-	Name      *string  `json:"name" db:"name"`
-	Address   *string  `json:"address" db:"address"`
-	Latitude  *float64 `json:"latitude" db:"latitude"`
-	Longitude *float64 `json:"longitude" db:"longitude"`
+	Name      *string  `json:"name" db:"name" binding:"omitnil,min=1,max=200"`
+	Address   *string  `json:"address" db:"address" binding:"omitnil,min=1,max=500"`
+	Latitude  *float64 `json:"latitude" db:"latitude" binding:"omitnil,lat"`
+	Longitude *float64 `json:"longitude" db:"longitude" binding:"omitnil,lon"`
+}
+
+// StopPatch документ остановки для PATCH (JSON Merge Patch). null в патче очищает
+// адрес и координаты. ID и UpdatedAt только для чтения
+type StopPatch struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name" binding:"required,max=200"`
+	Address   *string   `json:"address" db:"address" binding:"omitnil,max=500"`
+	Latitude  *float64  `json:"latitude" db:"latitude" binding:"omitnil,lat"`
+	Longitude *float64  `json:"longitude" db:"longitude" binding:"omitnil,lon"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
 func (su *StopUpdate) Validate() error {
 	if su.Name == nil && su.Address == nil && su.Longitude == nil && su.Latitude == nil {
-		return errors.New("no fields specified")
+		return errors.New("не указаны поля для обновления")
 	}
 	return nil
 }
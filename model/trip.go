@@ -1,34 +1,49 @@
 package model
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 )
 
 type Trip struct {
 	ID        int       `json:"-,omitempty" db:"id"`
-	BusID     int       `json:"bus_id" binding:"required" db:"bus_id"`
-	DriverID  int       `json:"driver_id" binding:"required" db:"driver_id"`
+	BusID     int       `json:"bus_id" binding:"required,gt=0" db:"bus_id"`
+	DriverID  int       `json:"driver_id" binding:"required,gt=0" db:"driver_id"`
 	StartTime time.Time `json:"start_time" binding:"required" db:"start_time"`
-	EndTime   time.Time `json:"end_time" binding:"required" db:"end_time"`
-	Status    string    `json:"status" db:"status"`
-	BasePrice int       `json:"base_price" binding:"required" db:"base_price"`
+	EndTime   time.Time `json:"end_time" binding:"required,after=StartTime" db:"end_time"`
+	Status    string    `json:"status" binding:"max=50" db:"status"`
+	BasePrice int       `json:"base_price" binding:"required,price" db:"base_price"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type TripUpdate struct {
 	ID        int        `db:"id"`
-	BusID     *int       `json:"bus_id" db:"bus_id"`
-	DriverID  *int       `json:"driver_id" db:"driver_id"`
+	BusID     *int       `json:"bus_id" binding:"omitnil,gt=0" db:"bus_id"`
+	DriverID  *int       `json:"driver_id" binding:"omitnil,gt=0" db:"driver_id"`
 	StartTime *time.Time `json:"start_time" db:"start_time"`
-	EndTime   *time.Time `json:"end_time" db:"end_time"`
-	Status    *string    `json:"status" db:"status"`
-	BasePrice *int       `json:"base_price" db:"base_price"`
+	EndTime   *time.Time `json:"end_time" binding:"omitnil,after=StartTime" db:"end_time"`
+	Status    *string    `json:"status" binding:"omitnil,min=1,max=50" db:"status"`
+	BasePrice *int       `json:"base_price" binding:"omitnil,price" db:"base_price"`
 	CreatedAt *time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// TripPatch документ рейса для PATCH (JSON Merge Patch). null в end_time
+// снимает время окончания. ID и UpdatedAt только для чтения
+type TripPatch struct {
+	ID        int        `json:"id" db:"id"`
+	BusID     int        `json:"bus_id" db:"bus_id" binding:"required,gt=0"`
+	DriverID  int        `json:"driver_id" db:"driver_id" binding:"required,gt=0"`
+	StartTime time.Time  `json:"start_time" db:"start_time" binding:"required"`
+	EndTime   *time.Time `json:"end_time" db:"end_time" binding:"omitnil,after=StartTime"`
+	Status    string     `json:"status" db:"status" binding:"required,max=50"`
+	BasePrice float64    `json:"base_price" db:"base_price" binding:"required,price"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
 type TripShortInfo struct {
 	TripID     int       `db:"trip_id" json:"trip_id"`
 	BusPlate   string    `db:"license_plate" json:"license_plate"`
@@ -45,18 +60,68 @@ type TripResponse struct {
 	ID         int `json:"-,omitempty" db:"id"`
 	ViewBus    `json:"bus"`
 	DriverView `json:"driver"`
-	StartTime  time.Time `json:"start_time" db:"start_time"`
-	EndTime    time.Time `json:"end_time" db:"end_time"`
-	Status     string    `json:"status" db:"status"`
-	BasePrice  float32   `json:"base_price" db:"base_price"`
-	Stop       `json:"stops"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	StartTime  time.Time     `json:"start_time" db:"start_time"`
+	EndTime    time.Time     `json:"end_time" db:"end_time"`
+	Status     string        `json:"status" db:"status"`
+	BasePrice  float32       `json:"base_price" db:"base_price"`
+	Stops      TripStopViews `json:"stops" db:"stops"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at" db:"updated_at"`
 }
 
+// TripStopView остановка рейса в порядке следования
+type TripStopView struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	Address       string    `json:"address"`
+	Latitude      float64   `json:"latitude"`
+	Longitude     float64   `json:"longitude"`
+	ArrivalTime   time.Time `json:"arrival_time"`
+	DepartureTime time.Time `json:"departure_time"`
+	StopOrder     int       `json:"stop_order"`
+}
+
+// TripStopViews остановки рейса, приходят из базы JSON-массивом
+type TripStopViews []TripStopView
+
+func (v *TripStopViews) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*v = TripStopViews{}
+		return nil
+	case []byte:
+		return json.Unmarshal(src, v)
+	case string:
+		return json.Unmarshal([]byte(src), v)
+	default:
+		return fmt.Errorf("trip stops: unsupported type %T", src)
+	}
+}
+
+var errTripEndTime = errors.New("время окончания должно быть позже времени начала")
+
 func (tu *TripUpdate) Validate() error {
-	if tu.BusID == nil && tu.Status == nil && tu.BasePrice == nil && tu.EndTime == nil && tu.DriverID == nil {
-		return errors.New("no fields to update")
+	if tu.BusID == nil && tu.Status == nil && tu.BasePrice == nil && tu.StartTime == nil && tu.EndTime == nil && tu.DriverID == nil {
+		return errors.New("не указаны поля для обновления")
+	}
+	if tu.StartTime != nil && tu.EndTime != nil && !tu.EndTime.After(*tu.StartTime) {
+		return errTripEndTime
+	}
+	return nil
+}
+
+// ValidateWith проверяет время рейса после обновления: если передано только start_time
+// или только end_time, второе значение берётся из сохранённого рейса current
+func (tu *TripUpdate) ValidateWith(current *TripPatch) error {
+	start, end := current.StartTime, current.EndTime
+	if tu.StartTime != nil {
+		start = *tu.StartTime
+	}
+	if tu.EndTime != nil {
+		end = tu.EndTime
+	}
+	if end != nil && !end.After(start) {
+		return errTripEndTime
 	}
 	return nil
 }
@@ -79,7 +144,7 @@ func (tu *TripUpdate) ToMap() map[string]interface{} {
 		output["status"] = *tu.Status
 	}
 	if tu.BasePrice != nil {
-		output["base_price"] = &tu.BasePrice
+		output["base_price"] = *tu.BasePrice
 	}
 	return output
 }
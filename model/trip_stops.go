@@ -7,12 +7,12 @@ import (
 
 type TripStop struct {
 	ID            int       `json:"id" db:"id"`
-	TripID        int       `json:"trip_id" db:"trip_id"`
-	StopID        int       `json:"stop_id" db:"stop_id"`
-	ArrivalTime   time.Time `json:"arrival_time" db:"arrival_time"`
-	DepartureTime time.Time `json:"departure_time" db:"departure_time"`
-	StopOrder     int       `json:"stop_order" db:"stop_order"`
-	PriceToNext   float64   `json:"price_to_next" db:"price_to_next"`
+	TripID        int       `json:"trip_id" db:"trip_id" binding:"required,gt=0"`
+	StopID        int       `json:"stop_id" db:"stop_id" binding:"required,gt=0"`
+	ArrivalTime   time.Time `json:"arrival_time" db:"arrival_time" binding:"required"`
+	DepartureTime time.Time `json:"departure_time" db:"departure_time" binding:"required,gtefield=ArrivalTime"`
+	StopOrder     int       `json:"stop_order" db:"stop_order" binding:"gte=0"`
+	PriceToNext   float64   `json:"price_to_next" db:"price_to_next" binding:"gte=0"`
 }
 
 type TripStopResponse struct {
@@ -26,18 +26,21 @@ type TripStopResponse struct {
 
 type TripStopUpdate struct {
 	ID            int        `json:"-,omitempty" db:"id"`
-	TripID        *int       `json:"trip_id" db:"trip_id"`
-	StopID        *int       `json:"stop_id" db:"stop_id"`
+	TripID        *int       `json:"trip_id" db:"trip_id" binding:"omitnil,gt=0"`
+	StopID        *int       `json:"stop_id" db:"stop_id" binding:"omitnil,gt=0"`
 	ArrivalTime   *time.Time `json:"arrival_time" db:"arrival_time"`
 	DepartureTime *time.Time `json:"departure_time" db:"departure_time"`
-	StopOrder     *int       `json:"stop_order" db:"stop_order"`
-	PriceToNext   *float64   `json:"price_to_next" db:"price_to_next"`
+	StopOrder     *int       `json:"stop_order" db:"stop_order" binding:"omitnil,gte=0"`
+	PriceToNext   *float64   `json:"price_to_next" db:"price_to_next" binding:"omitnil,gte=0"`
 }
 
 func (tu *TripStopUpdate) Validate() error {
 	if tu.ArrivalTime == nil && tu.DepartureTime == nil && tu.StopOrder == nil && tu.PriceToNext == nil &&
 		tu.TripID == nil && tu.StopID == nil {
-		return errors.New("no fields to update")
+		return errors.New("не указаны поля для обновления")
+	}
+	if tu.ArrivalTime != nil && tu.DepartureTime != nil && tu.DepartureTime.Before(*tu.ArrivalTime) {
+		return errors.New("время отправления не может быть раньше времени прибытия")
 	}
 	return nil
 }
@@ -17,9 +17,9 @@ type UserCreate struct {
 
 // UserUpdate представляет данные для обновления пользователя
 type UserUpdate struct {
-	Name     *string `json:"name,omitempty"`
-	Password *string `json:"password,omitempty"`
-	Email    *string `json:"email,omitempty"`
+	Name     *string `json:"name,omitempty" binding:"omitnil,min=1,max=100"`
+	Password *string `json:"password,omitempty" binding:"omitnil,min=8"`
+	Email    *string `json:"email,omitempty" binding:"omitnil,email"`
 }
 
 // Validate проверяет валидность полей обновления пользователя
@@ -46,14 +46,21 @@ func (u *UserUpdate) Validate() error {
 
 // UserResponse представляет данные пользователя для отображения (без чувствительных данных)
 type UserResponse struct {
-	ID        int       `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	Role      string    `json:"role"`
-	UserAgent string    `json:"user_agent"`
-	IP        string    `json:"ip"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            int        `json:"id"`
+	Email         string     `json:"email"`
+	Name          string     `json:"name"`
+	Role          string     `json:"role"`
+	EmailVerified bool       `json:"email_verified"`
+	Phone         string     `json:"phone,omitempty"`
+	IsService     bool       `json:"is_service_account,omitempty"`
+	Language      string     `json:"preferred_language,omitempty"`
+	BlockedAt     *time.Time `json:"blocked_at,omitempty"`
+	BlockedReason *string    `json:"blocked_reason,omitempty"`
+	UserAgent     string     `json:"user_agent"`
+	IP            string     `json:"ip"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
 }
 
 // UserLogin представляет данные для аутентификации
@@ -66,18 +73,25 @@ type UserLogin struct {
 
 // UserDB представляет модель пользователя в базе данных
 type UserDB struct {
-	ID           int        `db:"id"`
-	Email        string     `db:"email"`
-	PasswordHash *string    `db:"password_hash"`
-	Name         string     `db:"name"`
-	Role         string     `db:"role_name"`
-	UserAgent    string     `db:"user_agent"`
-	IP           string     `db:"ip"`
-	Provider     string     `db:"provider"`
-	ProviderID   string     `db:"provider_id"`
-	CreatedAt    time.Time  `db:"created_at"`
-	UpdatedAt    time.Time  `db:"updated_at"`
-	DeletedAt    *time.Time `db:"deleted_at"`
+	ID            int        `db:"id"`
+	Email         string     `db:"email"`
+	PasswordHash  *string    `db:"password_hash"`
+	Name          string     `db:"name"`
+	Role          string     `db:"role_name"`
+	EmailVerified bool       `db:"email_verified"`
+	Phone         string     `db:"phone"`
+	PhoneVerified bool       `db:"phone_verified"`
+	IsService     bool       `db:"is_service_account"`
+	Language      string     `db:"preferred_language"`
+	BlockedAt     *time.Time `db:"blocked_at"`
+	BlockedReason *string    `db:"blocked_reason"`
+	UserAgent     string     `db:"user_agent"`
+	IP            string     `db:"ip"`
+	Provider      string     `db:"provider"`
+	ProviderID    string     `db:"provider_id"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+	DeletedAt     *time.Time `db:"deleted_at"`
 }
 
 // UserIdentity
@@ -90,16 +104,68 @@ type UserIdentity struct {
 	UpdatedAt  time.Time `db:"updated_at"`
 }
 
+// UserIdentityResponse привязанный способ входа через внешнего провайдера
+type UserIdentityResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Provider  string    `json:"provider"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SSOLinkResponse адрес, куда фронтенд отправляет пользователя для привязки провайдера
+type SSOLinkResponse struct {
+	URL string `json:"url"`
+}
+
+// ToResponse преобразует UserIdentity в UserIdentityResponse
+func (i *UserIdentity) ToResponse() *UserIdentityResponse {
+	return &UserIdentityResponse{
+		ID:        i.ID,
+		Provider:  i.Provider,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
 // ToResponse преобразует UserDB в UserResponse
 func (u *UserDB) ToResponse() *UserResponse {
 	return &UserResponse{
-		ID:        u.ID,
-		Email:     u.Email,
-		Name:      u.Name,
-		Role:      u.Role,
-		UserAgent: u.UserAgent,
-		IP:        u.IP,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:            u.ID,
+		Email:         u.Email,
+		Name:          u.Name,
+		Role:          u.Role,
+		EmailVerified: u.EmailVerified,
+		Phone:         u.Phone,
+		IsService:     u.IsService,
+		Language:      u.Language,
+		BlockedAt:     u.BlockedAt,
+		BlockedReason: u.BlockedReason,
+		UserAgent:     u.UserAgent,
+		IP:            u.IP,
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+		DeletedAt:     u.DeletedAt,
 	}
 }
+
+// UserSearch фильтр поиска пользователей в админке
+type UserSearch struct {
+	Query   string `form:"q"`     // подстрока email или имени
+	Email   string `form:"email"` // подстрока email
+	Name    string `form:"name"`  // подстрока имени
+	Role    string `form:"role"`  // точное имя роли
+	Blocked *bool  `form:"blocked"`
+}
+
+// UserBlock причина блокировки аккаунта
+type UserBlock struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// ProviderImpersonation значение Claims.Provider и AMR для токена входа от имени пользователя
+const ProviderImpersonation = "impersonation"
+
+// ImpersonationResponse токен для входа от имени пользователя. Refresh-токен не выдаётся
+type ImpersonationResponse struct {
+	AccessToken string    `json:"access_token"`
+	UserID      int       `json:"user_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
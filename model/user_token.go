@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Назначения одноразовых токенов
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+// UserToken — одноразовый токен для сброса пароля или подтверждения email.
+// В базе хранится только хеш токена.
+type UserToken struct {
+	ID        uuid.UUID  `db:"id"`
+	UserID    int        `db:"user_id"`
+	Purpose   string     `db:"purpose"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// PasswordForgotRequest — запрос письма для сброса пароля
+type PasswordForgotRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PasswordResetRequest — установка нового пароля по токену из письма
+type PasswordResetRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// EmailVerificationRequest — повторная отправка письма для подтверждения email
+type EmailVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// EmailVerifyRequest — подтверждение email по токену из письма
+type EmailVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,29 @@
+package dbx
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type includeDeletedKey struct{}
+
+// WithDeleted помечает контекст: запросы с NotDeleted вернут и мягко удалённые записи
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// IncludeDeleted сообщает, запрошены ли в контексте мягко удалённые записи
+func IncludeDeleted(ctx context.Context) bool {
+	v, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return v
+}
+
+// NotDeleted условие column IS NULL. Если контекст помечен WithDeleted, возвращает nil,
+// и squirrel пропускает такое условие в Where
+func NotDeleted(ctx context.Context, column string) sq.Sqlizer {
+	if IncludeDeleted(ctx) {
+		return nil
+	}
+	return sq.Eq{column: nil}
+}
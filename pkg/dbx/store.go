@@ -6,13 +6,15 @@ import (
 )
 
 type QueryBuilder struct {
-	DB *sqlx.DB
+	DB *DB
+	Tx *TxManager
 	Sq sq.StatementBuilderType
 }
 
 func NewQueryBuilder(db *sqlx.DB) *QueryBuilder {
 	return &QueryBuilder{
-		DB: db,
+		DB: NewDB(db),
+		Tx: NewTxManager(db),
 		Sq: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
 	}
 }
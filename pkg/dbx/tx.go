@@ -0,0 +1,211 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// sqlStateSerializationFailure и sqlStateDeadlockDetected — ошибки, после которых
+	// транзакцию можно безопасно повторить целиком
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+
+	defaultTxRetries = 3
+	defaultTxBackoff = 20 * time.Millisecond
+)
+
+type txKey struct{}
+
+// txState транзакция, открытая TxManager и переданная через контекст.
+// Транзакция — одно соединение, поэтому контекст с ней нельзя передавать
+// в другие горутины: запросы и точки сохранения в ней идут строго по очереди
+type txState struct {
+	db         *sqlx.DB
+	tx         *sqlx.Tx
+	savepoints int
+}
+
+func txFrom(ctx context.Context, db *sqlx.DB) *txState {
+	st, _ := ctx.Value(txKey{}).(*txState)
+	if st == nil || st.db != db {
+		return nil
+	}
+	return st
+}
+
+// DB обёртка над *sqlx.DB: если в контексте есть транзакция TxManager,
+// запросы выполняются в ней, иначе — на пуле соединений. Пул не встраивается,
+// чтобы мимо транзакции нельзя было пройти методами *sqlx.DB
+type DB struct {
+	db *sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *DB {
+	return &DB{db: db}
+}
+
+// Conn возвращает транзакцию из контекста или сам пул
+func (db *DB) Conn(ctx context.Context) sqlx.ExtContext {
+	if st := txFrom(ctx, db.db); st != nil {
+		return st.tx
+	}
+	return db.db
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.Conn(ctx).ExecContext(ctx, query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.Conn(ctx).QueryContext(ctx, query, args...)
+}
+
+func (db *DB) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	return db.Conn(ctx).QueryxContext(ctx, query, args...)
+}
+
+func (db *DB) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	return db.Conn(ctx).QueryRowxContext(ctx, query, args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	if st := txFrom(ctx, db.db); st != nil {
+		return st.tx.QueryRowContext(ctx, query, args...)
+	}
+	return db.db.QueryRowContext(ctx, query, args...)
+}
+
+func (db *DB) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return sqlx.GetContext(ctx, db.Conn(ctx), dest, query, args...)
+}
+
+func (db *DB) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return sqlx.SelectContext(ctx, db.Conn(ctx), dest, query, args...)
+}
+
+func (db *DB) NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error) {
+	return sqlx.NamedExecContext(ctx, db.Conn(ctx), query, arg)
+}
+
+// Transactor выполняет функцию в транзакции, переданной через контекст
+type Transactor interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// TxManager открывает транзакции и кладёт их в контекст. Репозитории, работающие
+// через DB, подхватывают транзакцию сами, поэтому сервис может объединить
+// несколько вызовов репозиториев в одну единицу работы
+type TxManager struct {
+	db      *sqlx.DB
+	retries int
+	backoff time.Duration
+}
+
+func NewTxManager(db *sqlx.DB) *TxManager {
+	return &TxManager{
+		db:      db,
+		retries: defaultTxRetries,
+		backoff: defaultTxBackoff,
+	}
+}
+
+// Do выполняет fn в транзакции с уровнем изоляции по умолчанию
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.DoWith(ctx, nil, fn)
+}
+
+// DoWith выполняет fn в транзакции. Ошибка или паника в fn откатывает транзакцию.
+// Если контекст уже в транзакции, fn выполняется внутри точки сохранения,
+// и откатывается только её работа; opts при этом игнорируются.
+// Внешняя транзакция при ошибке сериализации или взаимной блокировке повторяется
+// целиком, поэтому fn не должна иметь побочных эффектов вне базы.
+// Контекст, переданный в fn, нельзя использовать из других горутин
+func (m *TxManager) DoWith(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if st := txFrom(ctx, m.db); st != nil {
+		return m.savepoint(ctx, st, fn)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, opts, fn)
+		if err == nil || attempt >= m.retries || !IsRetryable(err) {
+			return err
+		}
+
+		delay := m.backoff<<attempt + rand.N(m.backoff)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (m *TxManager) run(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) (err error) {
+	tx, err := m.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, &txState{db: m.db, tx: tx})); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// savepoint выполняет fn в точке сохранения. Имя точки — глубина вложенности:
+// после RELEASE или ROLLBACK TO соседний вызов переиспользует то же имя
+func (m *TxManager) savepoint(ctx context.Context, st *txState, fn func(ctx context.Context) error) (err error) {
+	st.savepoints++
+	defer func() { st.savepoints-- }()
+	name := fmt.Sprintf("sp_%d", st.savepoints)
+
+	if _, err = st.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = st.tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+		if err != nil {
+			if _, rbErr := st.tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+				err = errors.Join(err, rbErr)
+			}
+		}
+	}()
+
+	if err = fn(ctx); err != nil {
+		return err
+	}
+	_, err = st.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// IsRetryable сообщает, что транзакцию прервал конфликт сериализации
+// или взаимная блокировка и её можно повторить
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
@@ -0,0 +1,225 @@
+package dbx
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+func newTestTx(t *testing.T) (*TxManager, *DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	db := sqlx.NewDb(conn, "pgx")
+	m := NewTxManager(db)
+	m.backoff = time.Millisecond
+	return m, NewDB(db), mock
+}
+
+func exactSQL(q string) string {
+	return "^" + regexp.QuoteMeta(q) + "$"
+}
+
+func TestTxSavepointRollsBackInnerFailure(t *testing.T) {
+	m, db, mock := newTestTx(t)
+	ctx := context.Background()
+	inner := errors.New("inner failed")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(exactSQL("INSERT INTO orders")).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(exactSQL("SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exactSQL("INSERT INTO tickets")).WillReturnError(inner)
+	mock.ExpectExec(exactSQL("ROLLBACK TO SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exactSQL("SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exactSQL("SAVEPOINT sp_2")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exactSQL("RELEASE SAVEPOINT sp_2")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exactSQL("RELEASE SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := m.Do(ctx, func(ctx context.Context) error {
+		if _, err := db.ExecContext(ctx, "INSERT INTO orders"); err != nil {
+			return err
+		}
+		err := m.Do(ctx, func(ctx context.Context) error {
+			_, err := db.ExecContext(ctx, "INSERT INTO tickets")
+			return err
+		})
+		if !errors.Is(err, inner) {
+			t.Fatalf("inner err = %v, want %v", err, inner)
+		}
+		// соседняя и вложенная точки получают имена по глубине
+		return m.Do(ctx, func(ctx context.Context) error {
+			return m.Do(ctx, func(context.Context) error { return nil })
+		})
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+}
+
+func TestTxRetriesSerializationFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []error
+		attempts int
+		wantErr  bool
+	}{
+		{
+			name:     "serialization failure then success",
+			failures: []error{&pgconn.PgError{Code: sqlStateSerializationFailure}},
+			attempts: 2,
+		},
+		{
+			name:     "deadlock then success",
+			failures: []error{&pgconn.PgError{Code: sqlStateDeadlockDetected}},
+			attempts: 2,
+		},
+		{
+			name: "gives up after retries",
+			failures: []error{
+				&pgconn.PgError{Code: sqlStateSerializationFailure},
+				&pgconn.PgError{Code: sqlStateSerializationFailure},
+				&pgconn.PgError{Code: sqlStateSerializationFailure},
+				&pgconn.PgError{Code: sqlStateSerializationFailure},
+			},
+			attempts: defaultTxRetries + 1,
+			wantErr:  true,
+		},
+		{
+			name:     "other errors are not retried",
+			failures: []error{&pgconn.PgError{Code: "23505"}},
+			attempts: 1,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, db, mock := newTestTx(t)
+			for i := 0; i < tt.attempts; i++ {
+				mock.ExpectBegin()
+				if i < len(tt.failures) {
+					mock.ExpectExec(exactSQL("UPDATE seats")).WillReturnError(tt.failures[i])
+					mock.ExpectRollback()
+					continue
+				}
+				mock.ExpectExec(exactSQL("UPDATE seats")).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			attempts := 0
+			started := time.Now()
+			err := m.Do(context.Background(), func(ctx context.Context) error {
+				attempts++
+				_, err := db.ExecContext(ctx, "UPDATE seats")
+				return err
+			})
+			elapsed := time.Since(started)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.attempts {
+				t.Fatalf("attempts = %d, want %d", attempts, tt.attempts)
+			}
+			// паузы растут вдвое: backoff, 2*backoff, 4*backoff...
+			var minDelay time.Duration
+			for i := 0; i < tt.attempts-1; i++ {
+				minDelay += m.backoff << i
+			}
+			if elapsed < minDelay {
+				t.Fatalf("retried after %v, want at least %v", elapsed, minDelay)
+			}
+		})
+	}
+}
+
+func TestTxRetryStopsOnCancel(t *testing.T) {
+	m, db, mock := newTestTx(t)
+	m.backoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(exactSQL("UPDATE seats")).WillReturnError(&pgconn.PgError{Code: sqlStateSerializationFailure})
+	mock.ExpectRollback()
+
+	err := m.Do(ctx, func(ctx context.Context) error {
+		defer cancel()
+		_, err := db.ExecContext(ctx, "UPDATE seats")
+		return err
+	})
+	if !IsRetryable(err) {
+		t.Fatalf("err = %v, want the serialization failure", err)
+	}
+}
+
+func TestTxRollsBackOnPanic(t *testing.T) {
+	m, db, mock := newTestTx(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(exactSQL("INSERT INTO orders")).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(exactSQL("SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exactSQL("ROLLBACK TO SAVEPOINT sp_1")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("recovered %v, want the original panic", p)
+		}
+	}()
+	_ = m.Do(context.Background(), func(ctx context.Context) error {
+		if _, err := db.ExecContext(ctx, "INSERT INTO orders"); err != nil {
+			return err
+		}
+		return m.Do(ctx, func(context.Context) error {
+			panic("boom")
+		})
+	})
+}
+
+func TestDBUsesTransactionFromContext(t *testing.T) {
+	m, db, mock := newTestTx(t)
+
+	mock.ExpectQuery(exactSQL("SELECT 1")).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec(exactSQL("UPDATE seats")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	var n int
+	if err := db.GetContext(context.Background(), &n, "SELECT 1"); err != nil || n != 1 {
+		t.Fatalf("GetContext = %d, %v", n, err)
+	}
+
+	stop := errors.New("stop")
+	err := m.Do(context.Background(), func(ctx context.Context) error {
+		if _, ok := db.Conn(ctx).(*sqlx.Tx); !ok {
+			t.Fatalf("Conn = %T, want *sqlx.Tx", db.Conn(ctx))
+		}
+		if _, err := db.ExecContext(ctx, "UPDATE seats"); err != nil {
+			return err
+		}
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Do err = %v, want %v", err, stop)
+	}
+	if _, ok := db.Conn(context.Background()).(*sqlx.DB); !ok {
+		t.Fatal("Conn outside Do must be the pool")
+	}
+}
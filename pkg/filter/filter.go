@@ -0,0 +1,115 @@
+// Package filter разбирает фильтры списков вида filter[поле]=значение и filter[поле][оператор]=значение
+// и превращает их в условия squirrel. Поля и операторы берутся только из белого списка Spec
+package filter
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidSyntax = errors.New("invalid filter syntax")
+	ErrUnknownField  = errors.New("unknown filter field")
+	ErrUnsupportedOp = errors.New("unsupported filter operator")
+	ErrInvalidValue  = errors.New("invalid filter value")
+)
+
+// Error ошибка одного параметра фильтра. Err — одна из ErrInvalidSyntax, ErrUnknownField,
+// ErrUnsupportedOp, ErrInvalidValue; Key — параметр запроса, как его прислал клиент
+type Error struct {
+	Key   string
+	Field string
+	Op    Op
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error() + ": " + e.Key
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// conditionError ошибка условия c. Без явного оператора ключ показывается без него
+func conditionError(c Condition, err error) *Error {
+	key := "filter[" + c.Field + "]"
+	if c.Op != Eq {
+		key += "[" + string(c.Op) + "]"
+	}
+	return &Error{Key: key, Field: c.Field, Op: c.Op, Err: err}
+}
+
+// Op оператор сравнения
+type Op string
+
+const (
+	Eq       Op = "eq"
+	Ne       Op = "ne"
+	Gt       Op = "gt"
+	Gte      Op = "gte"
+	Lt       Op = "lt"
+	Lte      Op = "lte"
+	In       Op = "in"       // значения через запятую
+	Contains Op = "contains" // подстрока без учёта регистра
+	IsNull   Op = "null"     // true — поле пустое, false — заполнено
+)
+
+// MaxInValues ограничивает число значений в операторе in
+const MaxInValues = 100
+
+// Condition одно условие из строки запроса
+type Condition struct {
+	Field string
+	Op    Op
+	Value string
+}
+
+// Filter набор условий, объединяемых через AND
+type Filter []Condition
+
+// FromQuery собирает условия из параметров filter[...]. Остальные параметры игнорируются.
+// Без оператора подразумевается eq. Порядок условий стабилен, чтобы запросы с одинаковыми
+// фильтрами давали одинаковый SQL
+func FromQuery(q url.Values) (Filter, error) {
+	var f Filter
+	for key, values := range q {
+		rest, ok := strings.CutPrefix(key, "filter[")
+		if !ok {
+			continue
+		}
+		field, op, err := parseKey(rest)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			f = append(f, Condition{Field: field, Op: op, Value: strings.TrimSpace(v)})
+		}
+	}
+
+	sort.SliceStable(f, func(i, j int) bool {
+		if f[i].Field != f[j].Field {
+			return f[i].Field < f[j].Field
+		}
+		return f[i].Op < f[j].Op
+	})
+	return f, nil
+}
+
+// parseKey разбирает остаток ключа после "filter[": field] или field][op]
+func parseKey(key string) (string, Op, error) {
+	field, rest, ok := strings.Cut(key, "]")
+	if !ok || field == "" {
+		return "", "", &Error{Key: "filter[" + key, Err: ErrInvalidSyntax}
+	}
+	if rest == "" {
+		return field, Eq, nil
+	}
+	op, ok := strings.CutPrefix(rest, "[")
+	if !ok || !strings.HasSuffix(op, "]") || len(op) < 2 {
+		return "", "", &Error{Key: "filter[" + key, Field: field, Err: ErrInvalidSyntax}
+	}
+	return field, Op(strings.TrimSuffix(op, "]")), nil
+}
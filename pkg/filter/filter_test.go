@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    Filter
+		wantKey string
+	}{
+		{name: "no filters", query: "page=2&sort=name"},
+		{
+			name:  "implicit eq",
+			query: "filter[status]=active",
+			want:  Filter{{Field: "status", Op: Eq, Value: "active"}},
+		},
+		{
+			name:  "operators and repeated values",
+			query: "filter[price][lt]=900&filter[price][gte]=100&filter[city]=%20Kazan%20&filter[city]=Ufa",
+			want: Filter{
+				{Field: "city", Op: Eq, Value: "Kazan"},
+				{Field: "city", Op: Eq, Value: "Ufa"},
+				{Field: "price", Op: Gte, Value: "100"},
+				{Field: "price", Op: Lt, Value: "900"},
+			},
+		},
+		{name: "unclosed field", query: "filter[status=active", wantKey: "filter[status"},
+		{name: "empty field", query: "filter[]=active", wantKey: "filter[]"},
+		{name: "empty operator", query: "filter[status][]=active", wantKey: "filter[status][]"},
+		{name: "garbage after field", query: "filter[status]x=active", wantKey: "filter[status]x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := FromQuery(q)
+			if tt.wantKey != "" {
+				var ferr *Error
+				if !errors.As(err, &ferr) || !errors.Is(err, ErrInvalidSyntax) || ferr.Key != tt.wantKey {
+					t.Fatalf("err = %v, want syntax error for %s", err, tt.wantKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(f, tt.want) {
+				t.Fatalf("filter = %+v, want %+v", f, tt.want)
+			}
+		})
+	}
+}
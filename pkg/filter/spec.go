@@ -0,0 +1,164 @@
+package filter
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Kind тип значения поля: от него зависят разбор значения и допустимые операторы
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+// Field поле, по которому разрешено фильтровать
+type Field struct {
+	Column   string // SQL-выражение
+	Kind     Kind
+	Ops      []Op // если не задано, берутся операторы по умолчанию для Kind
+	Nullable bool // разрешает оператор null
+}
+
+// Spec белый список полей: ключ — имя поля в запросе
+type Spec map[string]Field
+
+var defaultOps = map[Kind][]Op{
+	String: {Eq, Ne, In, Contains},
+	Int:    {Eq, Ne, Gt, Gte, Lt, Lte, In},
+	Float:  {Eq, Ne, Gt, Gte, Lt, Lte},
+	Bool:   {Eq},
+	Time:   {Gt, Gte, Lt, Lte},
+}
+
+func (f Field) allows(op Op) bool {
+	if op == IsNull {
+		return f.Nullable
+	}
+	ops := f.Ops
+	if ops == nil {
+		ops = defaultOps[f.Kind]
+	}
+	return slices.Contains(ops, op)
+}
+
+// Build проверяет условия по белому списку и возвращает их конъюнкцию.
+// Для пустого фильтра возвращает nil, такое условие squirrel пропускает
+func (s Spec) Build(f Filter) (sq.Sqlizer, error) {
+	if len(f) == 0 {
+		return nil, nil
+	}
+
+	where := make(sq.And, 0, len(f))
+	for _, c := range f {
+		field, ok := s[c.Field]
+		if !ok {
+			return nil, conditionError(c, ErrUnknownField)
+		}
+		if !field.allows(c.Op) {
+			return nil, conditionError(c, ErrUnsupportedOp)
+		}
+		pred, err := field.predicate(c)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, pred)
+	}
+	return where, nil
+}
+
+func (f Field) predicate(c Condition) (sq.Sqlizer, error) {
+	col := f.Column
+
+	switch c.Op {
+	case IsNull:
+		isNull, err := strconv.ParseBool(c.Value)
+		if err != nil {
+			return nil, invalidValue(c)
+		}
+		if isNull {
+			return sq.Eq{col: nil}, nil
+		}
+		return sq.NotEq{col: nil}, nil
+	case Contains:
+		if c.Value == "" {
+			return nil, invalidValue(c)
+		}
+		return sq.ILike{col: "%" + EscapeLike(c.Value) + "%"}, nil
+	case In:
+		parts := strings.Split(c.Value, ",")
+		if len(parts) > MaxInValues {
+			return nil, invalidValue(c)
+		}
+		values := make([]any, len(parts))
+		for i, part := range parts {
+			v, err := f.parse(strings.TrimSpace(part))
+			if err != nil {
+				return nil, invalidValue(c)
+			}
+			values[i] = v
+		}
+		return sq.Eq{col: values}, nil
+	}
+
+	v, err := f.parse(c.Value)
+	if err != nil {
+		return nil, invalidValue(c)
+	}
+	switch c.Op {
+	case Eq:
+		return sq.Eq{col: v}, nil
+	case Ne:
+		return sq.NotEq{col: v}, nil
+	case Gt:
+		return sq.Gt{col: v}, nil
+	case Gte:
+		return sq.GtOrEq{col: v}, nil
+	case Lt:
+		return sq.Lt{col: v}, nil
+	case Lte:
+		return sq.LtOrEq{col: v}, nil
+	}
+	return nil, conditionError(c, ErrUnsupportedOp)
+}
+
+// parse приводит значение к типу поля, чтобы в запрос уходили только корректные параметры
+func (f Field) parse(raw string) (any, error) {
+	switch f.Kind {
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Float:
+		return strconv.ParseFloat(raw, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	default:
+		if raw == "" {
+			return nil, ErrInvalidValue
+		}
+		return raw, nil
+	}
+}
+
+func invalidValue(c Condition) error {
+	return conditionError(c, ErrInvalidValue)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike экранирует спецсимволы LIKE, чтобы пользовательский ввод искался как есть
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
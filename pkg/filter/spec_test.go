@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testSpec = Spec{
+	"name":     {Column: "s.name", Kind: String},
+	"price":    {Column: "t.base_price", Kind: Float},
+	"bus_id":   {Column: "t.bus_id", Kind: Int},
+	"start":    {Column: "t.start_time", Kind: Time},
+	"active":   {Column: "d.active", Kind: Bool},
+	"end_time": {Column: "t.end_time", Kind: Time, Nullable: true},
+	"status":   {Column: "t.status", Kind: String, Ops: []Op{Eq, In}},
+}
+
+func TestSpecBuild(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		f    Filter
+		sql  string
+		args []any
+	}{
+		{name: "empty filter"},
+		{
+			name: "comparisons are joined with AND",
+			f:    Filter{{Field: "price", Op: Gte, Value: "100.5"}, {Field: "bus_id", Op: Ne, Value: "3"}},
+			sql:  "(t.base_price >= ? AND t.bus_id <> ?)",
+			args: []any{100.5, int64(3)},
+		},
+		{
+			name: "in",
+			f:    Filter{{Field: "bus_id", Op: In, Value: "1, 2,3"}},
+			sql:  "(t.bus_id IN (?,?,?))",
+			args: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name: "date without time",
+			f:    Filter{{Field: "start", Op: Lt, Value: "2025-06-01"}},
+			sql:  "(t.start_time < ?)",
+			args: []any{day},
+		},
+		{
+			name: "null",
+			f:    Filter{{Field: "end_time", Op: IsNull, Value: "true"}, {Field: "end_time", Op: IsNull, Value: "false"}},
+			sql:  "(t.end_time IS NULL AND t.end_time IS NOT NULL)",
+		},
+		{
+			name: "contains escapes LIKE wildcards",
+			f:    Filter{{Field: "name", Op: Contains, Value: `50%_off\`}},
+			sql:  "(s.name ILIKE ?)",
+			args: []any{`%50\%\_off\\%`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, err := testSpec.Build(tt.f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.sql == "" {
+				if where != nil {
+					t.Fatalf("where = %v, want nil", where)
+				}
+				return
+			}
+			sql, args, err := where.ToSql()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.sql || !reflect.DeepEqual(args, tt.args) {
+				t.Fatalf("ToSql = %q %v, want %q %v", sql, args, tt.sql, tt.args)
+			}
+		})
+	}
+}
+
+func TestSpecBuildRejects(t *testing.T) {
+	tooMany := "1"
+	for i := 0; i < MaxInValues; i++ {
+		tooMany += ",1"
+	}
+
+	tests := []struct {
+		name string
+		c    Condition
+		err  error
+		key  string
+	}{
+		{name: "unknown field", c: Condition{Field: "password", Op: Eq, Value: "x"}, err: ErrUnknownField, key: "filter[password]"},
+		{name: "operator outside field list", c: Condition{Field: "status", Op: Contains, Value: "x"}, err: ErrUnsupportedOp, key: "filter[status][contains]"},
+		{name: "operator outside kind defaults", c: Condition{Field: "active", Op: Gt, Value: "true"}, err: ErrUnsupportedOp, key: "filter[active][gt]"},
+		{name: "null on required field", c: Condition{Field: "start", Op: IsNull, Value: "true"}, err: ErrUnsupportedOp, key: "filter[start][null]"},
+		{name: "unknown operator", c: Condition{Field: "price", Op: "regex", Value: "1"}, err: ErrUnsupportedOp, key: "filter[price][regex]"},
+		{name: "not a number", c: Condition{Field: "bus_id", Op: Eq, Value: "1; DROP"}, err: ErrInvalidValue, key: "filter[bus_id]"},
+		{name: "bad time", c: Condition{Field: "start", Op: Gt, Value: "yesterday"}, err: ErrInvalidValue, key: "filter[start][gt]"},
+		{name: "bad null flag", c: Condition{Field: "end_time", Op: IsNull, Value: "maybe"}, err: ErrInvalidValue, key: "filter[end_time][null]"},
+		{name: "empty string", c: Condition{Field: "name", Op: Eq, Value: ""}, err: ErrInvalidValue, key: "filter[name]"},
+		{name: "empty contains", c: Condition{Field: "name", Op: Contains, Value: ""}, err: ErrInvalidValue, key: "filter[name][contains]"},
+		{name: "too many in values", c: Condition{Field: "bus_id", Op: In, Value: tooMany}, err: ErrInvalidValue, key: "filter[bus_id][in]"},
+		{name: "bad in value", c: Condition{Field: "bus_id", Op: In, Value: "1,x"}, err: ErrInvalidValue, key: "filter[bus_id][in]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testSpec.Build(Filter{tt.c})
+			var ferr *Error
+			if !errors.Is(err, tt.err) || !errors.As(err, &ferr) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if ferr.Key != tt.key || ferr.Field != tt.c.Field || ferr.Op != tt.c.Op {
+				t.Fatalf("error = %+v, want key %s", ferr, tt.key)
+			}
+		})
+	}
+}
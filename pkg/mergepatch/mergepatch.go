@@ -0,0 +1,79 @@
+// Package mergepatch применяет JSON Merge Patch (RFC 7396): поля патча заменяют поля документа,
+// вложенные объекты сливаются рекурсивно, null удаляет поле
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ContentType тип содержимого запроса с merge patch
+const ContentType = "application/merge-patch+json"
+
+var ErrInvalidPatch = errors.New("invalid merge patch")
+
+// Apply применяет patch к документу doc и возвращает новый документ
+func Apply(doc, patch []byte) ([]byte, error) {
+	var p any
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	var target any
+	if len(bytes.TrimSpace(doc)) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+// Into применяет patch к значению v и раскладывает результат обратно в v.
+// Поля, которых нет в структуре, и значения не того типа считаются ошибкой патча
+func Into[T any](v *T, patch []byte) error {
+	// патч не-объект заменил бы документ целиком
+	if trimmed := bytes.TrimSpace(patch); len(trimmed) == 0 || trimmed[0] != '{' {
+		return fmt.Errorf("%w: patch must be a JSON object", ErrInvalidPatch)
+	}
+
+	doc, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	merged, err := Apply(doc, patch)
+	if err != nil {
+		return err
+	}
+
+	var out T
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&out); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPatch, err)
+	}
+	*v = out
+	return nil
+}
+
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = merge(t[k], v)
+	}
+	return t
+}
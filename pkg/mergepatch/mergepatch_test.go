@@ -0,0 +1,134 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Примеры из RFC 7396, приложение A
+func TestApplyRFC7396(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc+" + "+tt.patch, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestApplyInvalidPatch(t *testing.T) {
+	if _, err := Apply([]byte(`{}`), []byte(`{"a":`)); !errors.Is(err, ErrInvalidPatch) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidPatch)
+	}
+}
+
+type testAddress struct {
+	City   string  `json:"city"`
+	Street *string `json:"street"`
+}
+
+type testDoc struct {
+	ID      int         `json:"id"`
+	Name    string      `json:"name"`
+	Tags    []string    `json:"tags"`
+	Address testAddress `json:"address"`
+	EndTime *time.Time  `json:"end_time"`
+}
+
+func TestInto(t *testing.T) {
+	street := "Ленина, 1"
+	end := time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC)
+	base := testDoc{
+		ID:      1,
+		Name:    "Рейс",
+		Tags:    []string{"a", "b"},
+		Address: testAddress{City: "Казань", Street: &street},
+		EndTime: &end,
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    testDoc
+		wantErr error
+	}{
+		{
+			name:  "null clears pointer field",
+			patch: `{"end_time":null}`,
+			want:  testDoc{ID: 1, Name: "Рейс", Tags: []string{"a", "b"}, Address: base.Address},
+		},
+		{
+			name:  "nested object is merged",
+			patch: `{"address":{"street":null}}`,
+			want:  testDoc{ID: 1, Name: "Рейс", Tags: []string{"a", "b"}, Address: testAddress{City: "Казань"}, EndTime: &end},
+		},
+		{
+			name:  "array is replaced",
+			patch: `{"tags":["c"]}`,
+			want:  testDoc{ID: 1, Name: "Рейс", Tags: []string{"c"}, Address: base.Address, EndTime: &end},
+		},
+		{name: "unknown field", patch: `{"color":"red"}`, wantErr: ErrInvalidPatch},
+		{name: "wrong type", patch: `{"name":42}`, wantErr: ErrInvalidPatch},
+		{name: "not an object", patch: `["name"]`, wantErr: ErrInvalidPatch},
+		{name: "empty patch", patch: ` `, wantErr: ErrInvalidPatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := base
+			err := Into(&doc, []byte(tt.patch))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if !reflect.DeepEqual(doc, base) {
+					t.Fatalf("failed patch changed the document: %+v", doc)
+				}
+				return
+			}
+			if !reflect.DeepEqual(doc, tt.want) {
+				t.Fatalf("doc = %+v, want %+v", doc, tt.want)
+			}
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,87 @@
+// Package paging постраничная выдача списков: по номеру страницы (page) или курсором (cursor),
+// с сортировкой по разрешённым полям и единым конвертом ответа
+package paging
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	ErrInvalidParams = errors.New("invalid pagination parameters")
+	ErrInvalidSort   = errors.New("invalid sort field")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Params параметры запроса списка: limit, page или cursor, sort.
+// sort — имя поля, с "-" в начале сортировка по убыванию (sort=-created_at)
+type Params struct {
+	Limit  int
+	Page   int
+	Cursor string
+	Sort   string
+}
+
+// FromQuery читает limit, page, cursor и sort из строки запроса.
+// Без page и cursor возвращается первая страница, limit ограничен MaxLimit
+func FromQuery(q url.Values) (Params, error) {
+	p := Params{
+		Limit:  DefaultLimit,
+		Cursor: strings.TrimSpace(q.Get("cursor")),
+		Sort:   strings.TrimSpace(q.Get("sort")),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return Params{}, ErrInvalidParams
+		}
+		p.Limit = min(n, MaxLimit)
+	}
+
+	if v := q.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return Params{}, ErrInvalidParams
+		}
+		p.Page = n
+	}
+
+	// курсор уже задаёт позицию, номер страницы с ним не сочетается
+	if p.Page > 0 && p.Cursor != "" {
+		return Params{}, ErrInvalidParams
+	}
+
+	return p, nil
+}
+
+func (p Params) limit() int {
+	if p.Limit <= 0 {
+		return DefaultLimit
+	}
+	return min(p.Limit, MaxLimit)
+}
+
+func (p Params) offset() int {
+	if p.Page <= 1 {
+		return 0
+	}
+	return (p.Page - 1) * p.limit()
+}
+
+// Page конверт ответа со списком.
+// Total считается только при выдаче по номеру страницы, курсорная выдача обходится без COUNT
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	Total      *int   `json:"total,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,59 @@
+package paging
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    Params
+		wantErr error
+	}{
+		{name: "defaults", query: "", want: Params{Limit: DefaultLimit}},
+		{name: "page and sort", query: "limit=5&page=3&sort=-start_time", want: Params{Limit: 5, Page: 3, Sort: "-start_time"}},
+		{name: "limit capped", query: "limit=1000", want: Params{Limit: MaxLimit}},
+		{name: "cursor", query: "cursor=abc", want: Params{Limit: DefaultLimit, Cursor: "abc"}},
+		{name: "zero limit", query: "limit=0", wantErr: ErrInvalidParams},
+		{name: "not a number", query: "page=two", wantErr: ErrInvalidParams},
+		{name: "negative page", query: "page=-1", wantErr: ErrInvalidParams},
+		{name: "page with cursor", query: "page=2&cursor=abc", wantErr: ErrInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := FromQuery(q)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FromQuery error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("FromQuery = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamsOffset(t *testing.T) {
+	tests := []struct {
+		p    Params
+		want int
+	}{
+		{p: Params{Limit: 10}, want: 0},
+		{p: Params{Limit: 10, Page: 1}, want: 0},
+		{p: Params{Limit: 10, Page: 3}, want: 20},
+		{p: Params{Page: 2}, want: DefaultLimit},
+	}
+
+	for _, tt := range tests {
+		if got := tt.p.offset(); got != tt.want {
+			t.Fatalf("%+v offset = %d, want %d", tt.p, got, tt.want)
+		}
+	}
+}
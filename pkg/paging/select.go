@@ -0,0 +1,43 @@
+package paging
+
+import (
+	"context"
+
+	"corpord-api/pkg/dbx"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Select выполняет запрос b постранично и возвращает конверт со строками типа T.
+// b — запрос без ORDER BY, LIMIT и OFFSET: их добавляет Spec.Apply.
+// При выдаче по номеру страницы дополнительно считается общее количество строк
+func Select[T any](ctx context.Context, qb *dbx.QueryBuilder, b sq.SelectBuilder, s Spec, p Params) (*Page[T], error) {
+	var total *int
+	if p.Page > 0 && p.Cursor == "" {
+		query, args, err := qb.Sq.Select("COUNT(*)").FromSelect(b, "counted").ToSql()
+		if err != nil {
+			return nil, err
+		}
+		var n int
+		if err := qb.DB.GetContext(ctx, &n, query, args...); err != nil {
+			return nil, err
+		}
+		total = &n
+	}
+
+	b, err := s.Apply(b, p)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := b.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []T
+	if err := qb.DB.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	return NewPage(rows, p, s, total)
+}
@@ -0,0 +1,194 @@
+package paging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Spec описывает сортировку списка. Ключи Fields — имена полей в запросе и одновременно
+// db-теги структуры строки, значения — SQL-выражения. Разрешать стоит только NOT NULL колонки:
+// по значению последней строки строится курсор
+type Spec struct {
+	Fields  map[string]string
+	Default string // поле сортировки по умолчанию
+	ID      string // SQL-выражение уникального ключа, добавляется к сортировке для стабильного порядка
+	IDField string // db-тег уникального ключа в структуре строки, по умолчанию "id"
+}
+
+type sortOrder struct {
+	field  string
+	column string
+	desc   bool
+}
+
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func (s Spec) order(raw string) (sortOrder, error) {
+	if raw == "" {
+		raw = s.Default
+	}
+	o := sortOrder{field: strings.TrimPrefix(raw, "-"), desc: strings.HasPrefix(raw, "-")}
+	column, ok := s.Fields[o.field]
+	if !ok {
+		return sortOrder{}, ErrInvalidSort
+	}
+	o.column = column
+	return o, nil
+}
+
+func (o sortOrder) String() string {
+	if o.desc {
+		return "-" + o.field
+	}
+	return o.field
+}
+
+func (s Spec) idField() string {
+	if s.IDField == "" {
+		return "id"
+	}
+	return s.IDField
+}
+
+// Apply добавляет к запросу сортировку, позицию (OFFSET или условие по курсору)
+// и LIMIT на одну строку больше страницы, чтобы узнать, есть ли следующая
+func (s Spec) Apply(b sq.SelectBuilder, p Params) (sq.SelectBuilder, error) {
+	o, err := s.order(p.Sort)
+	if err != nil {
+		return b, err
+	}
+
+	dir := "ASC"
+	cmp := ">"
+	if o.desc {
+		dir, cmp = "DESC", "<"
+	}
+
+	if p.Cursor != "" {
+		c, err := decodeCursor(p.Cursor)
+		if err != nil || c.Sort != o.String() {
+			return b, ErrInvalidCursor
+		}
+		if o.column == s.ID {
+			b = b.Where(sq.Expr(fmt.Sprintf("%s %s ?", s.ID, cmp), c.ID))
+		} else {
+			b = b.Where(sq.Expr(fmt.Sprintf("(%s, %s) %s (?, ?)", o.column, s.ID, cmp), c.Value, c.ID))
+		}
+	} else if offset := p.offset(); offset > 0 {
+		b = b.Offset(uint64(offset))
+	}
+
+	if o.column == s.ID {
+		b = b.OrderBy(s.ID + " " + dir)
+	} else {
+		b = b.OrderBy(o.column+" "+dir, s.ID+" "+dir)
+	}
+
+	return b.Limit(uint64(p.limit() + 1)), nil
+}
+
+// NewPage обрезает лишнюю строку, выбранную Apply, и строит курсор следующей страницы
+func NewPage[T any](rows []T, p Params, s Spec, total *int) (*Page[T], error) {
+	page := &Page[T]{Items: rows, Limit: p.limit(), Total: total}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if p.Cursor == "" && p.Page > 0 {
+		page.Page = p.Page
+	}
+
+	if len(rows) <= page.Limit {
+		return page, nil
+	}
+	page.Items = rows[:page.Limit]
+
+	o, err := s.order(p.Sort)
+	if err != nil {
+		return nil, err
+	}
+	last := reflect.ValueOf(page.Items[page.Limit-1])
+	value, ok := fieldByTag(last, o.field)
+	if !ok {
+		return nil, fmt.Errorf("paging: no field tagged %q in %T", o.field, page.Items[0])
+	}
+	id, ok := fieldByTag(last, s.idField())
+	if !ok {
+		return nil, fmt.Errorf("paging: no field tagged %q in %T", s.idField(), page.Items[0])
+	}
+
+	page.NextCursor = encodeCursor(cursor{Sort: o.String(), Value: value, ID: id})
+	return page, nil
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(raw string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, err
+	}
+	if c.ID == "" {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// fieldByTag возвращает значение поля с db-тегом tag в текстовом виде, которое
+// Postgres приводит к типу колонки. Ищет и во встроенных структурах
+func fieldByTag(v reflect.Value, tag string) (string, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name, _, _ := strings.Cut(f.Tag.Get("db"), ","); name == tag {
+			return formatValue(v.Field(i))
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Anonymous {
+			if s, ok := fieldByTag(v.Field(i), tag); ok {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+func formatValue(v reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano), true
+	}
+	return fmt.Sprint(v.Interface()), true
+}
@@ -0,0 +1,161 @@
+package paging
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+var testSpec = Spec{
+	Fields: map[string]string{
+		"id":         "trips.id",
+		"start_time": "trips.start_time",
+	},
+	Default: "id",
+	ID:      "trips.id",
+}
+
+type testBase struct {
+	ID int `db:"id"`
+}
+
+type testRow struct {
+	testBase
+	StartTime time.Time `db:"start_time"`
+	Name      *string   `db:"name"`
+}
+
+func testQuery() sq.SelectBuilder {
+	return sq.Select("trips.id", "trips.start_time").From("trips").PlaceholderFormat(sq.Dollar)
+}
+
+func TestSpecApply(t *testing.T) {
+	cursor := encodeCursor(cursor{Sort: "-start_time", Value: "2025-01-01T10:00:00Z", ID: "7"})
+
+	tests := []struct {
+		name     string
+		p        Params
+		wantSQL  string
+		wantArgs []any
+		wantErr  error
+	}{
+		{
+			name:    "default sort by id",
+			p:       Params{Limit: 10},
+			wantSQL: "SELECT trips.id, trips.start_time FROM trips ORDER BY trips.id ASC LIMIT 11",
+		},
+		{
+			name:    "page uses offset and id tiebreaker",
+			p:       Params{Limit: 10, Page: 3, Sort: "start_time"},
+			wantSQL: "SELECT trips.id, trips.start_time FROM trips ORDER BY trips.start_time ASC, trips.id ASC LIMIT 11 OFFSET 20",
+		},
+		{
+			name:     "cursor compares sort value and id",
+			p:        Params{Limit: 10, Cursor: cursor, Sort: "-start_time"},
+			wantSQL:  "SELECT trips.id, trips.start_time FROM trips WHERE (trips.start_time, trips.id) < ($1, $2) ORDER BY trips.start_time DESC, trips.id DESC LIMIT 11",
+			wantArgs: []any{"2025-01-01T10:00:00Z", "7"},
+		},
+		{name: "unknown field", p: Params{Sort: "password"}, wantErr: ErrInvalidSort},
+		{name: "cursor for another sort", p: Params{Cursor: cursor, Sort: "start_time"}, wantErr: ErrInvalidCursor},
+		{name: "garbage cursor", p: Params{Cursor: "%%%"}, wantErr: ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := testSpec.Apply(testQuery(), tt.p)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			query, args, err := b.ToSql()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantSQL {
+				t.Fatalf("query:\n got %s\nwant %s", query, tt.wantSQL)
+			}
+			if len(args) != len(tt.wantArgs) || (len(args) > 0 && !reflect.DeepEqual(args, tt.wantArgs)) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	rows := []*testRow{
+		{testBase: testBase{ID: 1}, StartTime: start},
+		{testBase: testBase{ID: 2}, StartTime: start.Add(time.Hour)},
+		{testBase: testBase{ID: 3}, StartTime: start.Add(2 * time.Hour)},
+	}
+
+	t.Run("extra row gives next cursor", func(t *testing.T) {
+		p := Params{Limit: 2, Sort: "start_time"}
+		page, err := NewPage(rows, p, testSpec, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Items) != 2 || page.Limit != 2 || page.Page != 0 {
+			t.Fatalf("page = %+v", page)
+		}
+		c, err := decodeCursor(page.NextCursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := cursor{Sort: "start_time", Value: start.Add(time.Hour).Format(time.RFC3339Nano), ID: "2"}
+		if c != want {
+			t.Fatalf("cursor = %+v, want %+v", c, want)
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		total := 3
+		page, err := NewPage(rows, Params{Limit: 5, Page: 1}, testSpec, &total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Items) != 3 || page.NextCursor != "" || page.Page != 1 || *page.Total != 3 {
+			t.Fatalf("page = %+v", page)
+		}
+	})
+
+	t.Run("empty result is an empty list", func(t *testing.T) {
+		page, err := NewPage[*testRow](nil, Params{}, testSpec, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Items == nil || len(page.Items) != 0 {
+			t.Fatalf("Items = %#v, want empty slice", page.Items)
+		}
+	})
+}
+
+func TestFieldByTag(t *testing.T) {
+	name := "Рейс"
+	row := &testRow{testBase: testBase{ID: 7}, Name: &name}
+
+	tests := []struct {
+		tag    string
+		want   string
+		wantOK bool
+	}{
+		{tag: "id", want: "7", wantOK: true},
+		{tag: "name", want: name, wantOK: true},
+		{tag: "missing"},
+	}
+	for _, tt := range tests {
+		got, ok := fieldByTag(reflect.ValueOf(row), tt.tag)
+		if got != tt.want || ok != tt.wantOK {
+			t.Fatalf("fieldByTag(%q) = %q, %v, want %q, %v", tt.tag, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if _, ok := fieldByTag(reflect.ValueOf(&testRow{}), "name"); ok {
+		t.Fatal("nil pointer field must not produce a cursor value")
+	}
+}
@@ -0,0 +1,76 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid phone number")
+
+// Normalize приводит номер к формату E.164 (+79991234567).
+// Пробелы, скобки, дефисы и точки отбрасываются. Номер без "+" считается
+// местным для defaultCountry (код страны без "+", например "7"):
+// российские номера вида 8XXXXXXXXXX и XXXXXXXXXX тоже приводятся к +7XXXXXXXXXX
+func Normalize(raw, defaultCountry string) (string, error) {
+	s := strings.TrimSpace(raw)
+	international := strings.HasPrefix(s, "+")
+	if strings.HasPrefix(s, "00") {
+		international = true
+		s = s[2:]
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && b.Len() == 0,
+			r == ' ', r == '-', r == '(', r == ')', r == '.':
+		default:
+			return "", ErrInvalid
+		}
+	}
+	digits := b.String()
+
+	if !international {
+		switch {
+		case defaultCountry == "7" && len(digits) == 11 && (digits[0] == '8' || digits[0] == '7'):
+			digits = "7" + digits[1:]
+		case defaultCountry == "7" && len(digits) == 10:
+			digits = "7" + digits
+		case defaultCountry != "" && !strings.HasPrefix(digits, defaultCountry):
+			digits = defaultCountry + strings.TrimLeft(digits, "0")
+		}
+	}
+
+	// E.164: до 15 цифр, код страны не начинается с 0
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", ErrInvalid
+	}
+	if strings.HasPrefix(digits, "7") && len(digits) != 11 {
+		return "", ErrInvalid
+	}
+
+	return "+" + digits, nil
+}
+
+// IsE164 проверяет, что номер уже в формате E.164
+func IsE164(s string) bool {
+	if len(s) < 9 || len(s) > 16 || s[0] != '+' || s[1] == '0' {
+		return false
+	}
+	for _, r := range s[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Mask скрывает середину номера: +7999***4567
+func Mask(s string) string {
+	if len(s) < 10 {
+		return s
+	}
+	return s[:5] + strings.Repeat("*", len(s)-9) + s[len(s)-4:]
+}
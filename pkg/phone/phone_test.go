@@ -0,0 +1,48 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw     string
+		country string
+		want    string
+		wantErr bool
+	}{
+		{raw: "+7 (999) 123-45-67", country: "7", want: "+79991234567"},
+		{raw: "89991234567", country: "7", want: "+79991234567"},
+		{raw: "9991234567", country: "7", want: "+79991234567"},
+		{raw: "0079991234567", country: "7", want: "+79991234567"},
+		{raw: "+44 20 7946 0958", country: "7", want: "+442079460958"},
+		{raw: "020 7946 0958", country: "44", want: "+442079460958"},
+		{raw: "+7999123456", country: "7", wantErr: true},
+		{raw: "+7 999 abc 45 67", country: "7", wantErr: true},
+		{raw: "12345", country: "7", wantErr: true},
+		{raw: "", country: "7", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Normalize(tt.raw, tt.country)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Normalize(%q) = %q, %v, want ErrInvalid", tt.raw, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q) = %q, %v, want %q", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestMask(t *testing.T) {
+	if got := Mask("+79991234567"); got != "+7999***4567" {
+		t.Fatalf("Mask = %q", got)
+	}
+	if got := Mask("+123"); got != "+123" {
+		t.Fatalf("Mask short = %q", got)
+	}
+}